@@ -2,14 +2,24 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/kapiw04/convenly/internal/app"
+	domainmail "github.com/kapiw04/convenly/internal/domain/mail"
+	domainsecurity "github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/domain/user"
 	"github.com/kapiw04/convenly/internal/infra/db"
 	logger "github.com/kapiw04/convenly/internal/infra/log"
+	"github.com/kapiw04/convenly/internal/infra/mail"
 	"github.com/kapiw04/convenly/internal/infra/security"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	_ "github.com/lib/pq"
@@ -18,14 +28,14 @@ import (
 func main() {
 	logger.InitializeLogger("./logs")
 
-	user := os.Getenv("POSTGRES_USER")
+	dbUser := os.Getenv("POSTGRES_USER")
 	password := os.Getenv("POSTGRES_PASSWORD")
 	dbName := os.Getenv("POSTGRES_DB")
-	if user == "" || password == "" || dbName == "" {
+	if dbUser == "" || password == "" || dbName == "" {
 		slog.Error("Environment variables POSTGRES_USER, POSTGRES_PASSWORD, and POSTGRES_DB must be set")
 		return
 	}
-	connStr := fmt.Sprintf("host=db port=5432 user=%s password=%s dbname=%s sslmode=disable", user, password, dbName)
+	connStr := fmt.Sprintf("host=db port=5432 user=%s password=%s dbname=%s sslmode=disable", dbUser, password, dbName)
 	postgresDb, err := sql.Open("postgres", connStr)
 	if err != nil {
 		slog.Error("Error connecting to the database", "err", err)
@@ -33,16 +43,178 @@ func main() {
 	}
 	defer postgresDb.Close()
 	slog.Info("Successfully connected to the database")
+	db.QueryTimeout = durationFromEnv("DB_QUERY_TIMEOUT", db.QueryTimeout)
 	hasher := &security.BcryptHasher{}
 	userRepo := db.NewPostgresUserRepo(postgresDb)
-	sessionRepo := db.NewPostgresSessionRepo(postgresDb, userRepo)
+	sessionPolicy := user.SessionPolicy{
+		TTL:    durationFromEnv("SESSION_TTL", user.DefaultSessionPolicy.TTL),
+		MaxAge: durationFromEnv("SESSION_MAX_AGE", user.DefaultSessionPolicy.MaxAge),
+	}
+	sessionRepo := db.NewPostgresSessionRepo(postgresDb, userRepo, sessionPolicy)
 	userService := app.NewUserService(userRepo, sessionRepo, hasher)
+	userService.LimitLogins(loginLimiters(postgresDb))
 	tagsRepo := db.NewPostgresTagRepo(postgresDb)
 	eventRepo := db.NewPostgresEventRepo(postgresDb, tagsRepo)
-	eventService := app.NewEventService(eventRepo)
+	txManager := db.NewPostgresTxManager(postgresDb)
+	eventService := app.NewEventService(eventRepo, txManager)
+
+	retention := durationFromEnv("EVENT_RETENTION", 30*24*time.Hour)
+	purgeInterval := durationFromEnv("EVENT_PURGE_INTERVAL", time.Hour)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go eventService.RunPurgeJob(ctx, purgeInterval, retention)
+	go userService.RunSessionCleanup(ctx, durationFromEnv("SESSION_CLEANUP_INTERVAL", time.Hour))
+	go userService.RunLoginFailureCleanup(ctx, durationFromEnv("LOGIN_FAILURE_CLEANUP_INTERVAL", time.Hour))
+
+	// requests that outlive the shutdown grace period get their queries
+	// cancelled through this context
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	tokenService := app.NewTokenService(
+		userService,
+		db.NewPostgresRefreshTokenRepo(postgresDb),
+		security.NewJWTSigner(tokenSecret()),
+		durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+	)
+
+	rateLimits := webapi.RateLimits{
+		Store:         security.NewMemoryRateLimiter(),
+		Anonymous:     rateLimitFromEnv("RATE_LIMIT_ANONYMOUS", domainsecurity.RateLimit{Requests: 60, Per: time.Minute}),
+		PreAuth:       rateLimitFromEnv("RATE_LIMIT_PRE_AUTH", domainsecurity.RateLimit{Requests: 1200, Per: time.Minute}),
+		Authenticated: rateLimitFromEnv("RATE_LIMIT_AUTHENTICATED", domainsecurity.RateLimit{Requests: 300, Per: time.Minute}),
+		Host:          rateLimitFromEnv("RATE_LIMIT_HOST", domainsecurity.RateLimit{Requests: 120, Per: time.Minute}),
+	}
+
+	accountService := app.NewAccountService(
+		userService,
+		tokenService,
+		txManager,
+		db.NewPostgresPasswordResetRepo(postgresDb),
+		db.NewPostgresEmailVerificationRepo(postgresDb),
+		mailer(),
+		envOr("APP_URL", "http://localhost:5173"),
+		app.AccountPolicy{
+			ResetTTL:        durationFromEnv("PASSWORD_RESET_TTL", app.DefaultAccountPolicy.ResetTTL),
+			VerificationTTL: durationFromEnv("EMAIL_VERIFICATION_TTL", app.DefaultAccountPolicy.VerificationTTL),
+			ResendCooldown:  durationFromEnv("VERIFICATION_RESEND_COOLDOWN", app.DefaultAccountPolicy.ResendCooldown),
+		},
+	)
+
+	router := webapi.NewRouter(userService, eventService, tokenService, accountService, rateLimits)
+	server := webapi.NewServer(requestCtx, ":8080", router.Handler)
+	go webapi.Start(server)
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	webapi.Stop(shutdownCtx, server)
+	accountService.Wait()
+}
+
+// loginLimiters returns the limiters of failed logins by IP and by email.
+// LOGIN_LIMITER=memory keeps them in memory, which is only right for a single
+// instance; by default they are stored in the database.
+func loginLimiters(postgresDb *sql.DB) (byIP, byEmail domainsecurity.LoginLimiter) {
+	ipPolicy := domainsecurity.DefaultIPLockoutPolicy
+	emailPolicy := domainsecurity.DefaultEmailLockoutPolicy
+	emailPolicy.MaxFailures = intFromEnv("LOGIN_MAX_FAILURES", emailPolicy.MaxFailures)
+	ipPolicy.MaxFailures = intFromEnv("LOGIN_MAX_FAILURES_PER_IP", ipPolicy.MaxFailures)
+
+	backend := os.Getenv("LOGIN_LIMITER")
+	if backend == "memory" {
+		return security.NewMemoryLoginLimiter(ipPolicy), security.NewMemoryLoginLimiter(emailPolicy)
+	}
+	if backend != "" && backend != "postgres" {
+		slog.Warn("Unknown login limiter, using postgres", "value", backend)
+	}
+	return db.NewPostgresLoginLimiter(postgresDb, "ip", ipPolicy), db.NewPostgresLoginLimiter(postgresDb, "email", emailPolicy)
+}
+
+// mailer sends mail through the SMTP server at SMTP_ADDR. Without one, mail
+// is written to MAIL_LOG_PATH, or to the log.
+func mailer() domainmail.Mailer {
+	from := envOr("MAIL_FROM", "noreply@convenly.local")
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		slog.Warn("SMTP_ADDR is not set, mail will not be delivered")
+		return &mail.LogMailer{Path: os.Getenv("MAIL_LOG_PATH"), From: from}
+	}
+	return &mail.SMTPMailer{
+		Addr:     addr,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+}
+
+// tokenSecret is the key access tokens are signed with. Without
+// AUTH_TOKEN_SECRET a random one is used, so tokens stop working on restart.
+func tokenSecret() []byte {
+	if secret := os.Getenv("AUTH_TOKEN_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	slog.Warn("AUTH_TOKEN_SECRET is not set, access tokens will not survive a restart")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("failed to generate token secret")
+	}
+	return secret
+}
+
+// envOr reads the environment variable, falling back to def when it is unset.
+func envOr(key string, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// durationFromEnv reads a duration such as "720h" from the environment
+// variable, falling back to def when it is unset or invalid.
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		slog.Warn("Invalid duration, using the default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return d
+}
 
-	router := webapi.NewRouter(userService, eventService)
-	server := webapi.NewServer(":8080", router.Handler)
-	webapi.Start(server)
-	defer webapi.Stop(context.Background(), server)
+// intFromEnv reads a positive integer from the environment variable, falling
+// back to def when it is unset or invalid.
+func intFromEnv(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		slog.Warn("Invalid number, using the default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return n
+}
+
+// rateLimitFromEnv reads a limit such as "60/1m" from the environment
+// variable, falling back to def when it is unset or invalid. "0/1m" turns the
+// limit off.
+func rateLimitFromEnv(key string, def domainsecurity.RateLimit) domainsecurity.RateLimit {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	requests, per, ok := strings.Cut(v, "/")
+	n, err := strconv.Atoi(requests)
+	d, perErr := time.ParseDuration(per)
+	if !ok || err != nil || perErr != nil || n < 0 || d <= 0 {
+		slog.Warn("Invalid rate limit, using the default", "key", key, "value", v)
+		return def
+	}
+	return domainsecurity.RateLimit{Requests: n, Per: d}
 }
@@ -0,0 +1,298 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/mail"
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/domain/uow"
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+// AccountPolicy decides how long the links mailed to users work.
+type AccountPolicy struct {
+	ResetTTL        time.Duration
+	VerificationTTL time.Duration
+	// ResendCooldown is how long a user has to wait before another
+	// verification or password reset mail is sent.
+	ResendCooldown time.Duration
+}
+
+var DefaultAccountPolicy = AccountPolicy{
+	ResetTTL:        time.Hour,
+	VerificationTTL: 24 * time.Hour,
+	ResendCooldown:  time.Minute,
+}
+
+// AccountService handles the parts of account management that go through
+// the user's mailbox.
+type AccountService struct {
+	users         *UserService
+	tokens        *TokenService
+	txManager     uow.TxManager
+	resets        user.PasswordResetRepo
+	verifications user.EmailVerificationRepo
+	mailer        mail.Mailer
+	appURL        string
+	policy        AccountPolicy
+	now           func() time.Time
+	// background tracks the mails sent after the request was answered
+	background sync.WaitGroup
+}
+
+// NewAccountService returns a service that mails links pointing to the
+// frontend at appURL.
+func NewAccountService(users *UserService, tokens *TokenService, txManager uow.TxManager, resets user.PasswordResetRepo, verifications user.EmailVerificationRepo, mailer mail.Mailer, appURL string, policy AccountPolicy) *AccountService {
+	return &AccountService{
+		users:         users,
+		tokens:        tokens,
+		txManager:     txManager,
+		resets:        resets,
+		verifications: verifications,
+		mailer:        mailer,
+		appURL:        appURL,
+		policy:        policy,
+		now:           time.Now,
+	}
+}
+
+// Register creates an unverified account and mails the link verifying its
+// email. The account is kept when the mail cannot be sent; the user can ask
+// for another.
+func (s *AccountService) Register(ctx context.Context, name string, rawEmail string, rawPassword string) error {
+	if err := s.users.Register(ctx, name, rawEmail, rawPassword); err != nil {
+		return err
+	}
+	u, err := s.users.GetByEmail(ctx, rawEmail)
+	if err != nil {
+		return err
+	}
+	if err := s.sendVerification(ctx, u, u.Email); err != nil {
+		slog.Error("Failed to send verification mail", "email", u.Email, "err", err)
+	}
+	return nil
+}
+
+// VerifyEmail marks the email a verification token was mailed to as
+// verified. If the user asked to change their email, the token's address
+// becomes the account's. The token is used up.
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	v, err := s.verifications.Consume(ctx, token, s.now())
+	if err != nil {
+		return err
+	}
+	return s.users.MarkEmailVerified(ctx, v.UserID, v.Email)
+}
+
+// ResendVerification mails another verification link to the user, at most
+// once per ResendCooldown.
+func (s *AccountService) ResendVerification(ctx context.Context, userID string) error {
+	u, err := s.users.GetByUUID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.EmailVerified {
+		return user.ErrEmailAlreadyVerified
+	}
+	lastSentAt, err := s.verifications.LastSentAt(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if wait := lastSentAt.Add(s.policy.ResendCooldown).Sub(s.now()); wait > 0 {
+		return &security.LockedOutError{RetryAfter: wait}
+	}
+	return s.sendVerification(ctx, u, u.Email)
+}
+
+// sendVerification mails a link verifying email to that address. It
+// replaces the links sent to the user before.
+func (s *AccountService) sendVerification(ctx context.Context, u *user.User, email string) error {
+	token, err := s.verifications.Create(ctx, u.UUID.String(), email, s.now().Add(s.policy.VerificationTTL))
+	if err != nil {
+		return err
+	}
+	link := s.appURL + "/verify-email?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "Verify your Convenly email",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"follow this link within %s to confirm that this is your email:\n\n%s\n\n"+
+			"If you did not sign up for Convenly, ignore this email.\n",
+			u.Name, humanDuration(s.policy.VerificationTTL), link),
+	})
+}
+
+// RequestPasswordReset mails a password reset link to the user with the
+// email, at most once per ResendCooldown. The mail is sent in the
+// background and unknown emails are ignored, so neither the answer nor how
+// long it takes tells who has an account.
+func (s *AccountService) RequestPasswordReset(ctx context.Context, rawEmail string) error {
+	email, err := user.NewEmail(rawEmail)
+	if err != nil {
+		return user.ErrInvalidEmailFormat
+	}
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		if err := s.sendPasswordReset(context.WithoutCancel(ctx), email); err != nil {
+			slog.Error("Failed to send password reset mail", "email", email.String(), "err", err)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until the mails being sent in the background are out.
+func (s *AccountService) Wait() {
+	s.background.Wait()
+}
+
+func (s *AccountService) sendPasswordReset(ctx context.Context, email user.Email) error {
+	u, err := s.users.userRepo.FindByEmail(ctx, email.String())
+	if errors.Is(err, user.ErrUserNotFound) {
+		slog.Info("Password reset requested for unknown email", "email", email.String())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	lastSentAt, err := s.resets.LastSentAt(ctx, u.UUID.String())
+	if err != nil {
+		return err
+	}
+	if s.now().Before(lastSentAt.Add(s.policy.ResendCooldown)) {
+		slog.Info("Password reset requested again too soon", "email", email.String())
+		return nil
+	}
+
+	token, err := s.resets.Create(ctx, u.UUID.String(), s.now().Add(s.policy.ResetTTL))
+	if err != nil {
+		return err
+	}
+	link := s.appURL + "/reset-password?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, mail.Message{
+		To:      u.Email,
+		Subject: "Reset your Convenly password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"someone asked to reset the password of your Convenly account. "+
+			"Follow this link within %s to choose a new one:\n\n%s\n\n"+
+			"If it was not you, ignore this email and your password stays the same.\n",
+			u.Name, humanDuration(s.policy.ResetTTL), link),
+	})
+}
+
+// ResetPassword sets a new password for the user a reset token was mailed
+// to and logs them out everywhere. The token is used up together with both,
+// so a failure leaves it working.
+func (s *AccountService) ResetPassword(ctx context.Context, token string, rawPassword string) error {
+	// a weak password must not use up the token
+	if _, err := user.NewPassword(rawPassword); err != nil {
+		return err
+	}
+	return s.txManager.WithinTx(ctx, func(r uow.Repos) error {
+		userID, err := r.PasswordResets.Consume(ctx, token, s.now())
+		if err != nil {
+			return err
+		}
+		if err := s.users.setPassword(ctx, r, userID, rawPassword); err != nil {
+			return err
+		}
+		return s.tokens.revokeAll(ctx, r, userID)
+	})
+}
+
+// ChangePassword sets a new password for the user, who has to give their
+// current one; a wrong one counts as a failed login from ip. The user is
+// logged out everywhere in the same transaction.
+func (s *AccountService) ChangePassword(ctx context.Context, userID string, currentPassword string, newPassword string, ip string) error {
+	if _, err := user.NewPassword(newPassword); err != nil {
+		return err
+	}
+	if _, err := s.confirmPassword(ctx, userID, currentPassword, ip); err != nil {
+		return err
+	}
+	return s.txManager.WithinTx(ctx, func(r uow.Repos) error {
+		if err := s.users.setPassword(ctx, r, userID, newPassword); err != nil {
+			return err
+		}
+		return s.tokens.revokeAll(ctx, r, userID)
+	})
+}
+
+// ChangeEmail mails a verification link to the new email. The account keeps
+// its current email until the link is followed, and the current address is
+// told about the change.
+func (s *AccountService) ChangeEmail(ctx context.Context, userID string, rawEmail string, password string, ip string) error {
+	email, err := user.NewEmail(rawEmail)
+	if err != nil {
+		return user.ErrInvalidEmailFormat
+	}
+	u, err := s.confirmPassword(ctx, userID, password, ip)
+	if err != nil {
+		return err
+	}
+	if email.Equal(user.Email(u.Email)) {
+		return user.ErrEmailUnchanged
+	}
+	_, err = s.users.userRepo.FindByEmail(ctx, email.String())
+	if err == nil {
+		return user.ErrUserExists
+	}
+	if !errors.Is(err, user.ErrUserNotFound) {
+		return err
+	}
+
+	if err := s.sendVerification(ctx, u, email.String()); err != nil {
+		return err
+	}
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      u.Email,
+		Subject: "Your Convenly email is being changed",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"someone asked to change the email of your Convenly account to %s. "+
+			"It changes once the link sent there is followed.\n\n"+
+			"If it was not you, reset your password.\n",
+			u.Name, email.String()),
+	})
+	if err != nil {
+		slog.Error("Failed to send email change notice", "email", u.Email, "err", err)
+	}
+	return nil
+}
+
+// DeleteAccount removes the user's account, with the events they host, after
+// checking their password. Sessions and refresh tokens stop working.
+func (s *AccountService) DeleteAccount(ctx context.Context, userID string, password string, ip string) error {
+	if _, err := s.confirmPassword(ctx, userID, password, ip); err != nil {
+		return err
+	}
+	return s.users.Delete(ctx, userID)
+}
+
+// confirmPassword checks that password is the user's, as a login from ip
+// would.
+func (s *AccountService) confirmPassword(ctx context.Context, userID string, password string, ip string) (*user.User, error) {
+	u, err := s.users.GetByUUID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.users.VerifyCredentials(ctx, u.Email, password, ip)
+}
+
+// humanDuration writes d, rounded to minutes, the way a mail would.
+func humanDuration(d time.Duration) string {
+	n, unit := int(d.Round(time.Minute)/time.Minute), "minute"
+	if n%60 == 0 && n > 0 {
+		n, unit = n/60, "hour"
+	}
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,342 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kapiw04/convenly/internal/domain/mail"
+	mock_mail "github.com/kapiw04/convenly/internal/domain/mail/mocks"
+	"github.com/kapiw04/convenly/internal/domain/security"
+	mock_security "github.com/kapiw04/convenly/internal/domain/security/mocks"
+	"github.com/kapiw04/convenly/internal/domain/uow"
+	"github.com/kapiw04/convenly/internal/domain/user"
+	mock_user "github.com/kapiw04/convenly/internal/domain/user/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type accountServiceMocks struct {
+	userRepo      *mock_user.MockUserRepo
+	sessionRepo   *mock_user.MockSessionRepo
+	hasher        *mock_security.MockHasher
+	refreshTokens *mock_user.MockRefreshTokenRepo
+	resets        *mock_user.MockPasswordResetRepo
+	verifications *mock_user.MockEmailVerificationRepo
+	mailer        *mock_mail.MockMailer
+}
+
+func newAccountService(t *testing.T, now time.Time) (*AccountService, accountServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := accountServiceMocks{
+		userRepo:      mock_user.NewMockUserRepo(ctrl),
+		sessionRepo:   mock_user.NewMockSessionRepo(ctrl),
+		hasher:        mock_security.NewMockHasher(ctrl),
+		refreshTokens: mock_user.NewMockRefreshTokenRepo(ctrl),
+		resets:        mock_user.NewMockPasswordResetRepo(ctrl),
+		verifications: mock_user.NewMockEmailVerificationRepo(ctrl),
+		mailer:        mock_mail.NewMockMailer(ctrl),
+	}
+	users := NewUserService(m.userRepo, m.sessionRepo, m.hasher)
+	tokens := NewTokenService(users, m.refreshTokens, mock_security.NewMockTokenSigner(ctrl), 15*time.Minute, 30*24*time.Hour)
+	tx := inlineTx{uow.Repos{Users: m.userRepo, Sessions: m.sessionRepo, PasswordResets: m.resets, RefreshTokens: m.refreshTokens}}
+	svc := NewAccountService(users, tokens, tx, m.resets, m.verifications, m.mailer, "https://convenly.test", DefaultAccountPolicy)
+	svc.now = func() time.Time { return now }
+	return svc, m
+}
+
+func TestAccountService_RequestPasswordReset(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Name: "Alice", Email: "alice@example.com"}
+
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.resets.EXPECT().LastSentAt(gomock.Any(), u.UUID.String()).Return(time.Time{}, nil)
+	m.resets.EXPECT().Create(gomock.Any(), u.UUID.String(), now.Add(time.Hour)).Return("reset-token", nil)
+	m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg mail.Message) error {
+		require.Equal(t, "alice@example.com", msg.To)
+		require.Contains(t, msg.Body, "https://convenly.test/reset-password?token=reset-token")
+		require.Contains(t, msg.Body, "within 1 hour")
+		return nil
+	})
+
+	err := svc.RequestPasswordReset(context.Background(), "Alice@Example.com")
+	svc.Wait()
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_RequestPasswordReset_MailerErrorIsHidden(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+	u := &user.User{UUID: uuid.New(), Name: "Alice", Email: "alice@example.com"}
+
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.resets.EXPECT().LastSentAt(gomock.Any(), u.UUID.String()).Return(time.Time{}, nil)
+	m.resets.EXPECT().Create(gomock.Any(), u.UUID.String(), gomock.Any()).Return("reset-token", nil)
+	m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+
+	err := svc.RequestPasswordReset(context.Background(), "alice@example.com")
+	svc.Wait()
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_RequestPasswordReset_Cooldown(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Name: "Alice", Email: "alice@example.com"}
+
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.resets.EXPECT().LastSentAt(gomock.Any(), u.UUID.String()).Return(now.Add(-30*time.Second), nil)
+	m.resets.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+
+	err := svc.RequestPasswordReset(context.Background(), "alice@example.com")
+	svc.Wait()
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "nobody@example.com").Return(nil, user.ErrUserNotFound)
+
+	err := svc.RequestPasswordReset(context.Background(), "nobody@example.com")
+	svc.Wait()
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_ResetPassword(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com", PasswordHash: "old-hash"}
+
+	m.resets.EXPECT().Consume(gomock.Any(), "reset-token", now).Return(u.UUID.String(), nil)
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+	m.hasher.EXPECT().Hash("NewSecret123!").Return("new-hash", nil)
+	m.userRepo.EXPECT().SetPasswordHash(gomock.Any(), u.UUID.String(), "new-hash").Return(nil)
+	m.sessionRepo.EXPECT().DeleteByUser(gomock.Any(), u.UUID.String()).Return(nil)
+	m.userRepo.EXPECT().RevokeAccessTokens(gomock.Any(), u.UUID.String(), gomock.Any()).Return(nil)
+	m.refreshTokens.EXPECT().DeleteByUser(gomock.Any(), u.UUID.String()).Return(nil)
+
+	err := svc.ResetPassword(context.Background(), "reset-token", "NewSecret123!")
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_ResetPassword_RevokeErrorFailsReset(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com", PasswordHash: "old-hash"}
+	dbErr := errors.New("connection reset")
+
+	m.resets.EXPECT().Consume(gomock.Any(), "reset-token", now).Return(u.UUID.String(), nil)
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+	m.hasher.EXPECT().Hash("NewSecret123!").Return("new-hash", nil)
+	m.userRepo.EXPECT().SetPasswordHash(gomock.Any(), u.UUID.String(), "new-hash").Return(nil)
+	m.sessionRepo.EXPECT().DeleteByUser(gomock.Any(), u.UUID.String()).Return(nil)
+	m.userRepo.EXPECT().RevokeAccessTokens(gomock.Any(), u.UUID.String(), gomock.Any()).Return(nil)
+	m.refreshTokens.EXPECT().DeleteByUser(gomock.Any(), u.UUID.String()).Return(dbErr)
+
+	// returned from the unit of work, which rolls back the used-up token
+	err := svc.ResetPassword(context.Background(), "reset-token", "NewSecret123!")
+
+	require.ErrorIs(t, err, dbErr)
+}
+
+func TestAccountService_ResetPassword_WeakPasswordKeepsToken(t *testing.T) {
+	svc, _ := newAccountService(t, time.Now())
+
+	err := svc.ResetPassword(context.Background(), "reset-token", "weak")
+
+	require.ErrorIs(t, err, user.ErrPasswordTooShort)
+}
+
+func TestAccountService_ResetPassword_InvalidToken(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+
+	m.resets.EXPECT().Consume(gomock.Any(), "used-token", gomock.Any()).Return("", user.ErrInvalidResetToken)
+
+	err := svc.ResetPassword(context.Background(), "used-token", "NewSecret123!")
+
+	require.ErrorIs(t, err, user.ErrInvalidResetToken)
+}
+
+func TestAccountService_Register_SendsVerification(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Name: "Alice", Email: "alice@example.com"}
+
+	m.hasher.EXPECT().Hash("Secret123!").Return("hash", nil)
+	m.userRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, saved *user.User) error {
+		require.False(t, saved.EmailVerified)
+		return nil
+	})
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.verifications.EXPECT().Create(gomock.Any(), u.UUID.String(), "alice@example.com", now.Add(24*time.Hour)).Return("verify-token", nil)
+	m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg mail.Message) error {
+		require.Equal(t, "alice@example.com", msg.To)
+		require.Contains(t, msg.Body, "https://convenly.test/verify-email?token=verify-token")
+		return nil
+	})
+
+	err := svc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!")
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_VerifyEmail(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com"}
+
+	m.verifications.EXPECT().Consume(gomock.Any(), "verify-token", now).
+		Return(&user.EmailVerification{UserID: u.UUID.String(), Email: "alice@example.com"}, nil)
+	m.userRepo.EXPECT().MarkEmailVerified(gomock.Any(), u.UUID.String(), "alice@example.com").Return(nil)
+
+	err := svc.VerifyEmail(context.Background(), "verify-token")
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_VerifyEmail_ChangesEmail(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+	u := &user.User{UUID: uuid.New(), Email: "old@example.com", EmailVerified: true}
+
+	m.verifications.EXPECT().Consume(gomock.Any(), "verify-token", gomock.Any()).
+		Return(&user.EmailVerification{UserID: u.UUID.String(), Email: "new@example.com"}, nil)
+	m.userRepo.EXPECT().MarkEmailVerified(gomock.Any(), u.UUID.String(), "new@example.com").Return(nil)
+
+	err := svc.VerifyEmail(context.Background(), "verify-token")
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_ResendVerification(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com"}
+
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+	m.verifications.EXPECT().LastSentAt(gomock.Any(), u.UUID.String()).Return(now.Add(-2*time.Minute), nil)
+	m.verifications.EXPECT().Create(gomock.Any(), u.UUID.String(), "alice@example.com", gomock.Any()).Return("verify-token", nil)
+	m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	err := svc.ResendVerification(context.Background(), u.UUID.String())
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_ResendVerification_Throttled(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com"}
+
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+	m.verifications.EXPECT().LastSentAt(gomock.Any(), u.UUID.String()).Return(now.Add(-20*time.Second), nil)
+
+	err := svc.ResendVerification(context.Background(), u.UUID.String())
+
+	var lockedOut *security.LockedOutError
+	require.ErrorAs(t, err, &lockedOut)
+	require.Equal(t, 40*time.Second, lockedOut.RetryAfter)
+}
+
+func TestAccountService_ResendVerification_AlreadyVerified(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com", EmailVerified: true}
+
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+
+	err := svc.ResendVerification(context.Background(), u.UUID.String())
+
+	require.ErrorIs(t, err, user.ErrEmailAlreadyVerified)
+}
+
+func TestAccountService_ChangePassword(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com", PasswordHash: "old-hash"}
+
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil).Times(2)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.hasher.EXPECT().Compare("Secret123!", "old-hash").Return(true)
+	m.hasher.EXPECT().Hash("NewSecret123!").Return("new-hash", nil)
+	m.userRepo.EXPECT().SetPasswordHash(gomock.Any(), u.UUID.String(), "new-hash").Return(nil)
+	m.sessionRepo.EXPECT().DeleteByUser(gomock.Any(), u.UUID.String()).Return(nil)
+	m.userRepo.EXPECT().RevokeAccessTokens(gomock.Any(), u.UUID.String(), gomock.Any()).Return(nil)
+	m.refreshTokens.EXPECT().DeleteByUser(gomock.Any(), u.UUID.String()).Return(nil)
+
+	err := svc.ChangePassword(context.Background(), u.UUID.String(), "Secret123!", "NewSecret123!", "203.0.113.7")
+
+	require.NoError(t, err)
+}
+
+func TestAccountService_ChangePassword_WrongCurrentPassword(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com", PasswordHash: "old-hash"}
+
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.hasher.EXPECT().Compare("Wrong123!", "old-hash").Return(false)
+
+	err := svc.ChangePassword(context.Background(), u.UUID.String(), "Wrong123!", "NewSecret123!", "203.0.113.7")
+
+	require.ErrorIs(t, err, user.ErrInvalidCredentials)
+}
+
+func TestAccountService_ChangeEmail(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newAccountService(t, now)
+	u := &user.User{UUID: uuid.New(), Name: "Alice", Email: "alice@example.com", PasswordHash: "hash", EmailVerified: true}
+
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.hasher.EXPECT().Compare("Secret123!", "hash").Return(true)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@new.example.com").Return(nil, user.ErrUserNotFound)
+	m.verifications.EXPECT().Create(gomock.Any(), u.UUID.String(), "alice@new.example.com", now.Add(24*time.Hour)).Return("verify-token", nil)
+	var sent []mail.Message
+	m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg mail.Message) error {
+		sent = append(sent, msg)
+		return nil
+	}).Times(2)
+
+	err := svc.ChangeEmail(context.Background(), u.UUID.String(), "Alice@New.Example.com", "Secret123!", "203.0.113.7")
+
+	require.NoError(t, err)
+	require.Len(t, sent, 2)
+	require.Equal(t, "alice@new.example.com", sent[0].To)
+	require.Contains(t, sent[0].Body, "https://convenly.test/verify-email?token=verify-token")
+	require.Equal(t, "alice@example.com", sent[1].To)
+}
+
+func TestAccountService_ChangeEmail_Taken(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com", PasswordHash: "hash"}
+
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.hasher.EXPECT().Compare("Secret123!", "hash").Return(true)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "bob@example.com").Return(&user.User{UUID: uuid.New(), Email: "bob@example.com"}, nil)
+
+	err := svc.ChangeEmail(context.Background(), u.UUID.String(), "bob@example.com", "Secret123!", "203.0.113.7")
+
+	require.ErrorIs(t, err, user.ErrUserExists)
+}
+
+func TestAccountService_DeleteAccount(t *testing.T) {
+	svc, m := newAccountService(t, time.Now())
+	u := &user.User{UUID: uuid.New(), Email: "alice@example.com", PasswordHash: "hash"}
+
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(u, nil)
+	m.hasher.EXPECT().Compare("Secret123!", "hash").Return(true)
+	m.userRepo.EXPECT().DeleteByUUID(gomock.Any(), u.UUID.String()).Return(nil)
+
+	err := svc.DeleteAccount(context.Background(), u.UUID.String(), "Secret123!", "203.0.113.7")
+
+	require.NoError(t, err)
+}
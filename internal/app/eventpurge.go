@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// PurgeDeletedEvents permanently removes the events deleted more than
+// retention before now and returns how many were removed.
+func (s *EventService) PurgeDeletedEvents(ctx context.Context, now time.Time, retention time.Duration) (int, error) {
+	return s.eventRepo.PurgeDeleted(ctx, now.Add(-retention))
+}
+
+// RunPurgeJob purges deleted events past the retention window right away and
+// then every interval, until ctx is cancelled.
+func (s *EventService) RunPurgeJob(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.PurgeDeletedEvents(ctx, time.Now(), retention)
+		if err != nil {
+			slog.Error("Failed to purge deleted events", "err", err)
+		} else if n > 0 {
+			slog.Info("Purged deleted events", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mock_event "github.com/kapiw04/convenly/internal/domain/event/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEventService_PurgeDeletedEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	eventRepo.EXPECT().PurgeDeleted(gomock.Any(), time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)).Return(2, nil)
+
+	svc := newEventService(eventRepo)
+	n, err := svc.PurgeDeletedEvents(context.Background(), now, 30*24*time.Hour)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}
+
+func TestEventService_RunPurgeJob_StopsWithContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the first purge runs right away; cancel the job from inside it
+	eventRepo.EXPECT().PurgeDeleted(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, time.Time) (int, error) {
+		cancel()
+		return 0, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		newEventService(eventRepo).RunPurgeJob(ctx, time.Hour, 24*time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purge job did not stop")
+	}
+}
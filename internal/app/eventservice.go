@@ -1,63 +1,417 @@
 package app
 
-import "github.com/kapiw04/convenly/internal/domain/event"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/domain/uow"
+)
 
 type EventService struct {
 	eventRepo event.EventRepo
+	txManager uow.TxManager
+}
+
+func (s *EventService) GetAttendeesCount(ctx context.Context, eid string) (int, error) {
+	return s.eventRepo.GetAttendeesCount(ctx, eid)
+}
+
+// GetSeatsTaken counts the seats taken towards the event's capacity.
+func (s *EventService) GetSeatsTaken(ctx context.Context, eid string) (int, error) {
+	return s.eventRepo.GetSeatsTaken(ctx, eid)
+}
+
+func NewEventService(repo event.EventRepo, txManager uow.TxManager) *EventService {
+	return &EventService{eventRepo: repo, txManager: txManager}
+}
+
+func (s *EventService) CreateEvent(ctx context.Context, e *event.Event) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if !e.Status.IsInitial() {
+		return event.ErrInvalidInitialStatus
+	}
+	return s.eventRepo.Save(ctx, e)
+}
+
+func (s *EventService) UpdateEvent(ctx context.Context, userID, eventID string, upd *event.EventUpdate) (*event.Event, error) {
+	var e *event.Event
+	err := s.txManager.WithinTx(ctx, func(r uow.Repos) error {
+		var err error
+		e, err = r.Events.FindByID(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if e.OrganizerID != userID {
+			return event.ErrNotOrganizer
+		}
+		if upd != nil && upd.Status != nil && *upd.Status != e.Status {
+			if err := e.TransitionTo(*upd.Status); err != nil {
+				return err
+			}
+		}
+		upd.Apply(e)
+		if err := e.Validate(); err != nil {
+			return err
+		}
+		return r.Events.Update(ctx, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// CancelEvent cancels the event. The event and its attendance are kept, but
+// it no longer takes registrations. Cancelling a cancelled event does
+// nothing.
+func (s *EventService) CancelEvent(ctx context.Context, userID, eventID string) (*event.Event, error) {
+	var e *event.Event
+	err := s.txManager.WithinTx(ctx, func(r uow.Repos) error {
+		var err error
+		e, err = r.Events.FindByID(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if e.OrganizerID != userID {
+			return event.ErrNotOrganizer
+		}
+		if e.Status == event.StatusCancelled {
+			return nil
+		}
+		if err := e.TransitionTo(event.StatusCancelled); err != nil {
+			return err
+		}
+		return r.Events.UpdateStatus(ctx, eventID, e.Status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (s *EventService) GetEventByID(ctx context.Context, eventID string) (*event.Event, error) {
+	return s.eventRepo.FindByID(ctx, eventID)
+}
+
+// GetVisibleEvent returns the event if the user can see it. Drafts of other
+// organizers are reported as not found.
+func (s *EventService) GetVisibleEvent(ctx context.Context, userID, eventID string) (*event.Event, error) {
+	e, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !e.VisibleTo(userID) {
+		return nil, event.ErrEventNotFound
+	}
+	return e, nil
+}
+
+func (s *EventService) GetEventByTag(ctx context.Context, tagNames []string) ([]*event.Event, error) {
+	return s.eventRepo.FindAllByTags(ctx, tagNames)
+}
+
+func (s *EventService) GetAllEvents(ctx context.Context) ([]*event.Event, error) {
+	return s.eventRepo.FindAll(ctx)
+}
+
+func (s *EventService) GetEventsWithFilters(ctx context.Context, filter *event.EventFilter) ([]*event.Event, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.eventRepo.FindAllWithFilters(ctx, filter)
+}
+
+// ListEvents returns one page of the events matching the filter.
+func (s *EventService) ListEvents(ctx context.Context, filter *event.EventFilter) (*event.Page[*event.Event], error) {
+	if filter == nil {
+		filter = &event.EventFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.FindAllWithFilters(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.eventRepo.CountWithFilters(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewPage(events, filter.Pagination, total), nil
+}
+
+// ListEventsByCursor returns the events matching the filter that come after
+// filter.Keyset.After in start time order.
+func (s *EventService) ListEventsByCursor(ctx context.Context, filter *event.EventFilter) (*event.CursorPage[*event.Event], error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if filter == nil || filter.Keyset == nil {
+		return nil, event.ErrInvalidKeysetLimit
+	}
+
+	// fetch one extra event to find out whether there is a next page
+	limit := filter.Keyset.Limit
+	query := *filter
+	query.Keyset = &event.Keyset{After: filter.Keyset.After, Limit: limit + 1}
+
+	events, err := s.eventRepo.FindAllWithFilters(ctx, &query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &event.CursorPage[*event.Event]{Items: events, PageSize: limit}
+	if len(events) > limit {
+		page.Items = events[:limit]
+		page.HasNext = true
+		page.NextCursor = event.CursorFor(page.Items[limit-1]).Encode()
+	}
+	if page.Items == nil {
+		page.Items = []*event.Event{}
+	}
+	return page, nil
+}
+
+// RegisterAttendance registers the user for the event. When the event is full
+// the user is put on its waitlist instead and their position is returned; a
+// position of 0 means the user got a seat.
+func (s *EventService) RegisterAttendance(ctx context.Context, userID, eventID string) (int, error) {
+	err := s.eventRepo.RegisterAttendance(ctx, userID, eventID)
+	if errors.Is(err, event.ErrEventFull) {
+		return s.eventRepo.JoinWaitlist(ctx, userID, eventID)
+	}
+	return 0, err
+}
+
+func (s *EventService) IsUserAttending(ctx context.Context, userID, eventID string) bool {
+	return s.eventRepo.IsUserAttending(ctx, userID, eventID)
+}
+
+func (s *EventService) GetAttendees(ctx context.Context, eventID string) ([]string, error) {
+	return s.eventRepo.GetAttendees(ctx, eventID)
+}
+
+// GetAttendeeRoster returns the profiles of the users registered for the
+// event. Only the organizer can browse the roster.
+func (s *EventService) GetAttendeeRoster(ctx context.Context, userID, eventID string, pagination *event.Pagination) ([]event.Attendee, error) {
+	e, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.OrganizerID != userID {
+		return nil, event.ErrNotOrganizer
+	}
+	return s.eventRepo.GetAttendeeProfiles(ctx, eventID, pagination)
+}
+
+// ListAttendeeRoster returns one page of the roster with its total. Only the
+// organizer can browse the roster.
+func (s *EventService) ListAttendeeRoster(ctx context.Context, userID, eventID string, pagination *event.Pagination) (*event.Page[event.Attendee], error) {
+	attendees, err := s.GetAttendeeRoster(ctx, userID, eventID, pagination)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.eventRepo.CountAttendeeProfiles(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewPage(attendees, pagination, total), nil
+}
+
+func (s *EventService) RemoveAttendance(ctx context.Context, userID, eventID string) error {
+	return s.eventRepo.RemoveAttendance(ctx, userID, eventID)
+}
+
+// occurrence loads the event and its occurrence at recurrenceID.
+func (s *EventService) occurrence(ctx context.Context, eventID string, recurrenceID time.Time) (*event.Event, *event.Occurrence, error) {
+	e, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	exceptions, err := s.eventRepo.FindOccurrenceExceptions(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	o, err := e.Occurrence(recurrenceID, exceptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return e, o, nil
 }
 
-func (s *EventService) GetAttendeesCount(eid string) (int, error) {
-	return s.eventRepo.GetAttendeesCount(eid)
+// GetOccurrenceExceptions returns the cancelled and rescheduled occurrences
+// of the recurring series among the events, by event ID.
+func (s *EventService) GetOccurrenceExceptions(ctx context.Context, events []*event.Event) (map[string][]event.OccurrenceException, error) {
+	exceptions := make(map[string][]event.OccurrenceException)
+	for _, e := range events {
+		if !e.IsRecurring() || e.RecurrenceID != nil {
+			continue
+		}
+		if _, ok := exceptions[e.EventID]; ok {
+			continue
+		}
+		ex, err := s.eventRepo.FindOccurrenceExceptions(ctx, e.EventID)
+		if err != nil {
+			return nil, err
+		}
+		exceptions[e.EventID] = ex
+	}
+	return exceptions, nil
 }
 
-func NewEventService(repo event.EventRepo) *EventService {
-	return &EventService{eventRepo: repo}
+// RegisterOccurrence registers the user for a single occurrence of a
+// recurring event.
+func (s *EventService) RegisterOccurrence(ctx context.Context, userID, eventID string, recurrenceID time.Time) error {
+	if _, _, err := s.occurrence(ctx, eventID, recurrenceID); err != nil {
+		return err
+	}
+	return s.eventRepo.RegisterOccurrence(ctx, userID, eventID, recurrenceID)
 }
 
-func (s *EventService) CreateEvent(e *event.Event) error {
-	return s.eventRepo.Save(e)
+// UnregisterOccurrence removes the user's registration for a single
+// occurrence. Users can leave occurrences that were cancelled since.
+func (s *EventService) UnregisterOccurrence(ctx context.Context, userID, eventID string, recurrenceID time.Time) error {
+	e, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if _, err := e.Occurrence(recurrenceID, nil); err != nil {
+		return err
+	}
+	return s.eventRepo.RemoveOccurrenceAttendance(ctx, userID, eventID, recurrenceID)
 }
 
-func (s *EventService) GetEventByID(eventID string) (*event.Event, error) {
-	return s.eventRepo.FindByID(eventID)
+// CancelOccurrence cancels one occurrence of a recurring event, leaving the
+// rest of the series as it is. Only the organizer can cancel occurrences.
+func (s *EventService) CancelOccurrence(ctx context.Context, userID, eventID string, recurrenceID time.Time) error {
+	e, _, err := s.occurrence(ctx, eventID, recurrenceID)
+	if err != nil {
+		return err
+	}
+	if e.OrganizerID != userID {
+		return event.ErrNotOrganizer
+	}
+	return s.eventRepo.SaveOccurrenceException(ctx, &event.OccurrenceException{
+		EventID:      eventID,
+		RecurrenceID: recurrenceID,
+		Cancelled:    true,
+	})
 }
 
-func (s *EventService) GetEventByTag(tagNames []string) ([]*event.Event, error) {
-	return s.eventRepo.FindAllByTags(tagNames)
+// RescheduleOccurrence moves one occurrence of a recurring event. Nil times
+// keep the occurrence's current ones.
+func (s *EventService) RescheduleOccurrence(ctx context.Context, userID, eventID string, recurrenceID time.Time, startsAt, endsAt *time.Time) (*event.Occurrence, error) {
+	e, o, err := s.occurrence(ctx, eventID, recurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	if e.OrganizerID != userID {
+		return nil, event.ErrNotOrganizer
+	}
+	if startsAt != nil {
+		o.StartsAt = *startsAt
+	}
+	if endsAt != nil {
+		o.EndsAt = *endsAt
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	err = s.eventRepo.SaveOccurrenceException(ctx, &event.OccurrenceException{
+		EventID:      eventID,
+		RecurrenceID: recurrenceID,
+		StartsAt:     &o.StartsAt,
+		EndsAt:       &o.EndsAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
 }
 
-func (s *EventService) GetAllEvents() ([]*event.Event, error) {
-	return s.eventRepo.FindAll()
+func (s *EventService) LeaveWaitlist(ctx context.Context, userID, eventID string) error {
+	return s.eventRepo.LeaveWaitlist(ctx, userID, eventID)
 }
 
-func (s *EventService) GetEventsWithFilters(filter *event.EventFilter) ([]*event.Event, error) {
-	return s.eventRepo.FindAllWithFilters(filter)
+func (s *EventService) GetWaitlistPosition(ctx context.Context, userID, eventID string) (int, error) {
+	return s.eventRepo.GetWaitlistPosition(ctx, userID, eventID)
 }
 
-func (s *EventService) RegisterAttendance(userID, eventID string) error {
-	return s.eventRepo.RegisterAttendance(userID, eventID)
+// GetWaitlist returns the event's waitlist in order. Only the organizer can
+// see the whole queue.
+func (s *EventService) GetWaitlist(ctx context.Context, userID, eventID string) ([]event.WaitlistEntry, error) {
+	e, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.OrganizerID != userID {
+		return nil, event.ErrNotOrganizer
+	}
+	return s.eventRepo.GetWaitlist(ctx, eventID)
 }
 
-func (s *EventService) IsUserAttending(userID, eventID string) bool {
-	return s.eventRepo.IsUserAttending(userID, eventID)
+func (s *EventService) GetHostingEvents(ctx context.Context, userID string, pagination *event.Pagination) ([]*event.Event, error) {
+	return s.eventRepo.FindByOrganizer(ctx, userID, pagination)
 }
 
-func (s *EventService) GetAttendees(eventID string) ([]string, error) {
-	return s.eventRepo.GetAttendees(eventID)
+func (s *EventService) GetAttendingEvents(ctx context.Context, userID string, pagination *event.Pagination) ([]*event.Event, error) {
+	return s.eventRepo.FindAttendingEvents(ctx, userID, pagination)
 }
 
-func (s *EventService) RemoveAttendance(userID, eventID string) error {
-	return s.eventRepo.RemoveAttendance(userID, eventID)
+func (s *EventService) ListHostingEvents(ctx context.Context, userID string, pagination *event.Pagination) (*event.Page[*event.Event], error) {
+	events, err := s.eventRepo.FindByOrganizer(ctx, userID, pagination)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.eventRepo.CountByOrganizer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewPage(events, pagination, total), nil
 }
 
-func (s *EventService) GetHostingEvents(userID string, pagination *event.Pagination) ([]*event.Event, error) {
-	return s.eventRepo.FindByOrganizer(userID, pagination)
+func (s *EventService) ListAttendingEvents(ctx context.Context, userID string, pagination *event.Pagination) (*event.Page[*event.Event], error) {
+	events, err := s.eventRepo.FindAttendingEvents(ctx, userID, pagination)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.eventRepo.CountAttendingEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewPage(events, pagination, total), nil
 }
 
-func (s *EventService) GetAttendingEvents(userID string, pagination *event.Pagination) ([]*event.Event, error) {
-	return s.eventRepo.FindAttendingEvents(userID, pagination)
+// DeleteEvent soft-deletes the event. The organizer can restore it until it
+// is purged.
+func (s *EventService) DeleteEvent(ctx context.Context, eventID string) error {
+	return s.eventRepo.Delete(ctx, eventID)
 }
 
-func (s *EventService) DeleteEvent(eventID string) error {
-	return s.eventRepo.Delete(eventID)
+// RestoreEvent brings back a deleted event. Only the organizer can restore
+// their events.
+func (s *EventService) RestoreEvent(ctx context.Context, userID, eventID string) (*event.Event, error) {
+	var e *event.Event
+	err := s.txManager.WithinTx(ctx, func(r uow.Repos) error {
+		deleted, err := r.Events.FindDeletedByID(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if deleted.OrganizerID != userID {
+			return event.ErrNotOrganizer
+		}
+		if err := r.Events.Restore(ctx, eventID); err != nil {
+			return err
+		}
+		e, err = r.Events.FindByID(ctx, eventID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
 }
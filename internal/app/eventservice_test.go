@@ -1,16 +1,32 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/kapiw04/convenly/internal/domain/event"
 	mock_event "github.com/kapiw04/convenly/internal/domain/event/mocks"
+	"github.com/kapiw04/convenly/internal/domain/uow"
+	mock_uow "github.com/kapiw04/convenly/internal/domain/uow/mocks"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+// inlineTx runs units of work straight against the given repositories.
+type inlineTx struct {
+	repos uow.Repos
+}
+
+func (m inlineTx) WithinTx(_ context.Context, fn func(uow.Repos) error) error {
+	return fn(m.repos)
+}
+
+func newEventService(eventRepo event.EventRepo) *EventService {
+	return NewEventService(eventRepo, inlineTx{uow.Repos{Events: eventRepo}})
+}
+
 func TestEventService_CreateEvent_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -21,17 +37,20 @@ func TestEventService_CreateEvent_Success(t *testing.T) {
 		EventID:     "event-1",
 		Name:        "Test Event",
 		Description: "Description",
-		Date:        time.Now().Add(24 * time.Hour),
+		StartsAt:    time.Now().Add(24 * time.Hour),
+		EndsAt:      time.Now().Add(26 * time.Hour),
+		Timezone:    "Europe/Warsaw",
+		Status:      event.StatusPublished,
 		Latitude:    52.0,
 		Longitude:   21.0,
 		Fee:         10.0,
 		OrganizerID: "organizer-1",
 	}
 
-	eventRepo.EXPECT().Save(testEvent).Return(nil)
+	eventRepo.EXPECT().Save(gomock.Any(), testEvent).Return(nil)
 
-	svc := NewEventService(eventRepo)
-	err := svc.CreateEvent(testEvent)
+	svc := newEventService(eventRepo)
+	err := svc.CreateEvent(context.Background(), testEvent)
 
 	require.NoError(t, err)
 }
@@ -43,14 +62,18 @@ func TestEventService_CreateEvent_RepoError(t *testing.T) {
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
 	testEvent := &event.Event{
-		EventID: "event-1",
-		Name:    "Test Event",
+		EventID:  "event-1",
+		Name:     "Test Event",
+		StartsAt: time.Now().Add(24 * time.Hour),
+		EndsAt:   time.Now().Add(26 * time.Hour),
+		Timezone: "UTC",
+		Status:   event.StatusPublished,
 	}
 
-	eventRepo.EXPECT().Save(testEvent).Return(errors.New("database error"))
+	eventRepo.EXPECT().Save(gomock.Any(), testEvent).Return(errors.New("database error"))
 
-	svc := NewEventService(eventRepo)
-	err := svc.CreateEvent(testEvent)
+	svc := newEventService(eventRepo)
+	err := svc.CreateEvent(context.Background(), testEvent)
 
 	require.Error(t, err)
 }
@@ -66,25 +89,41 @@ func TestEventService_GetEventByID_Success(t *testing.T) {
 		Name:    "Test Event",
 	}
 
-	eventRepo.EXPECT().FindByID("event-1").Return(expected, nil)
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(expected, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetEventByID("event-1")
+	svc := newEventService(eventRepo)
+	result, err := svc.GetEventByID(context.Background(), "event-1")
 
 	require.NoError(t, err)
 	require.Equal(t, expected, result)
 }
 
+func TestEventService_PassesContextToRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	eventRepo.EXPECT().FindByID(ctx, "event-1").Return(&event.Event{EventID: "event-1"}, nil)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.GetEventByID(ctx, "event-1")
+
+	require.NoError(t, err)
+}
+
 func TestEventService_GetEventByID_NotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().FindByID("nonexistent").Return(nil, errors.New("not found"))
+	eventRepo.EXPECT().FindByID(gomock.Any(), "nonexistent").Return(nil, errors.New("not found"))
 
-	svc := NewEventService(eventRepo)
-	_, err := svc.GetEventByID("nonexistent")
+	svc := newEventService(eventRepo)
+	_, err := svc.GetEventByID(context.Background(), "nonexistent")
 
 	require.Error(t, err)
 }
@@ -100,10 +139,10 @@ func TestEventService_GetAllEvents_Success(t *testing.T) {
 		{EventID: "event-2", Name: "Event 2"},
 	}
 
-	eventRepo.EXPECT().FindAll().Return(expected, nil)
+	eventRepo.EXPECT().FindAll(gomock.Any()).Return(expected, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetAllEvents()
+	svc := newEventService(eventRepo)
+	result, err := svc.GetAllEvents(context.Background())
 
 	require.NoError(t, err)
 	require.Len(t, result, 2)
@@ -124,10 +163,10 @@ func TestEventService_GetEventsWithFilters_Success(t *testing.T) {
 		{EventID: "event-1", Name: "Event 1", Fee: 10.0},
 	}
 
-	eventRepo.EXPECT().FindAllWithFilters(filter).Return(expected, nil)
+	eventRepo.EXPECT().FindAllWithFilters(gomock.Any(), filter).Return(expected, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetEventsWithFilters(filter)
+	svc := newEventService(eventRepo)
+	result, err := svc.GetEventsWithFilters(context.Background(), filter)
 
 	require.NoError(t, err)
 	require.Len(t, result, 1)
@@ -143,10 +182,10 @@ func TestEventService_GetEventByTag_Success(t *testing.T) {
 		{EventID: "event-1", Name: "Event 1", Tags: []string{"music"}},
 	}
 
-	eventRepo.EXPECT().FindAllByTags([]string{"music"}).Return(expected, nil)
+	eventRepo.EXPECT().FindAllByTags(gomock.Any(), []string{"music"}).Return(expected, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetEventByTag([]string{"music"})
+	svc := newEventService(eventRepo)
+	result, err := svc.GetEventByTag(context.Background(), []string{"music"})
 
 	require.NoError(t, err)
 	require.Len(t, result, 1)
@@ -158,12 +197,13 @@ func TestEventService_RegisterAttendance_Success(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().RegisterAttendance("user-1", "event-1").Return(nil)
+	eventRepo.EXPECT().RegisterAttendance(gomock.Any(), "user-1", "event-1").Return(nil)
 
-	svc := NewEventService(eventRepo)
-	err := svc.RegisterAttendance("user-1", "event-1")
+	svc := newEventService(eventRepo)
+	position, err := svc.RegisterAttendance(context.Background(), "user-1", "event-1")
 
 	require.NoError(t, err)
+	require.Equal(t, 0, position)
 }
 
 func TestEventService_RegisterAttendance_Error(t *testing.T) {
@@ -172,10 +212,10 @@ func TestEventService_RegisterAttendance_Error(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().RegisterAttendance("user-1", "event-1").Return(errors.New("already registered"))
+	eventRepo.EXPECT().RegisterAttendance(gomock.Any(), "user-1", "event-1").Return(errors.New("already registered"))
 
-	svc := NewEventService(eventRepo)
-	err := svc.RegisterAttendance("user-1", "event-1")
+	svc := newEventService(eventRepo)
+	_, err := svc.RegisterAttendance(context.Background(), "user-1", "event-1")
 
 	require.Error(t, err)
 }
@@ -188,10 +228,10 @@ func TestEventService_GetAttendees_Success(t *testing.T) {
 
 	expected := []string{"user-1", "user-2"}
 
-	eventRepo.EXPECT().GetAttendees("event-1").Return(expected, nil)
+	eventRepo.EXPECT().GetAttendees(gomock.Any(), "event-1").Return(expected, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetAttendees("event-1")
+	svc := newEventService(eventRepo)
+	result, err := svc.GetAttendees(context.Background(), "event-1")
 
 	require.NoError(t, err)
 	require.Equal(t, expected, result)
@@ -203,10 +243,10 @@ func TestEventService_RemoveAttendance_Success(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().RemoveAttendance("user-1", "event-1").Return(nil)
+	eventRepo.EXPECT().RemoveAttendance(gomock.Any(), "user-1", "event-1").Return(nil)
 
-	svc := NewEventService(eventRepo)
-	err := svc.RemoveAttendance("user-1", "event-1")
+	svc := newEventService(eventRepo)
+	err := svc.RemoveAttendance(context.Background(), "user-1", "event-1")
 
 	require.NoError(t, err)
 }
@@ -217,10 +257,10 @@ func TestEventService_RemoveAttendance_Error(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().RemoveAttendance("user-1", "event-1").Return(errors.New("not registered"))
+	eventRepo.EXPECT().RemoveAttendance(gomock.Any(), "user-1", "event-1").Return(errors.New("not registered"))
 
-	svc := NewEventService(eventRepo)
-	err := svc.RemoveAttendance("user-1", "event-1")
+	svc := newEventService(eventRepo)
+	err := svc.RemoveAttendance(context.Background(), "user-1", "event-1")
 
 	require.Error(t, err)
 }
@@ -236,10 +276,10 @@ func TestEventService_GetHostingEvents_Success(t *testing.T) {
 		{EventID: "event-2", Name: "Hosted Event 2", OrganizerID: "user-1"},
 	}
 
-	eventRepo.EXPECT().FindByOrganizer("user-1", (*event.Pagination)(nil)).Return(expected, nil)
+	eventRepo.EXPECT().FindByOrganizer(gomock.Any(), "user-1", (*event.Pagination)(nil)).Return(expected, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetHostingEvents("user-1", nil)
+	svc := newEventService(eventRepo)
+	result, err := svc.GetHostingEvents(context.Background(), "user-1", nil)
 
 	require.NoError(t, err)
 	require.Len(t, result, 2)
@@ -252,10 +292,10 @@ func TestEventService_GetHostingEvents_Error(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().FindByOrganizer("user-1", (*event.Pagination)(nil)).Return(nil, errors.New("database error"))
+	eventRepo.EXPECT().FindByOrganizer(gomock.Any(), "user-1", (*event.Pagination)(nil)).Return(nil, errors.New("database error"))
 
-	svc := NewEventService(eventRepo)
-	_, err := svc.GetHostingEvents("user-1", nil)
+	svc := newEventService(eventRepo)
+	_, err := svc.GetHostingEvents(context.Background(), "user-1", nil)
 
 	require.Error(t, err)
 }
@@ -266,10 +306,10 @@ func TestEventService_GetHostingEvents_Empty(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().FindByOrganizer("user-1", (*event.Pagination)(nil)).Return([]*event.Event{}, nil)
+	eventRepo.EXPECT().FindByOrganizer(gomock.Any(), "user-1", (*event.Pagination)(nil)).Return([]*event.Event{}, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetHostingEvents("user-1", nil)
+	svc := newEventService(eventRepo)
+	result, err := svc.GetHostingEvents(context.Background(), "user-1", nil)
 
 	require.NoError(t, err)
 	require.Len(t, result, 0)
@@ -286,10 +326,10 @@ func TestEventService_GetAttendingEvents_Success(t *testing.T) {
 		{EventID: "event-2", Name: "Attending Event 2"},
 	}
 
-	eventRepo.EXPECT().FindAttendingEvents("user-1", (*event.Pagination)(nil)).Return(expected, nil)
+	eventRepo.EXPECT().FindAttendingEvents(gomock.Any(), "user-1", (*event.Pagination)(nil)).Return(expected, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetAttendingEvents("user-1", nil)
+	svc := newEventService(eventRepo)
+	result, err := svc.GetAttendingEvents(context.Background(), "user-1", nil)
 
 	require.NoError(t, err)
 	require.Len(t, result, 2)
@@ -302,10 +342,10 @@ func TestEventService_GetAttendingEvents_Error(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().FindAttendingEvents("user-1", (*event.Pagination)(nil)).Return(nil, errors.New("database error"))
+	eventRepo.EXPECT().FindAttendingEvents(gomock.Any(), "user-1", (*event.Pagination)(nil)).Return(nil, errors.New("database error"))
 
-	svc := NewEventService(eventRepo)
-	_, err := svc.GetAttendingEvents("user-1", nil)
+	svc := newEventService(eventRepo)
+	_, err := svc.GetAttendingEvents(context.Background(), "user-1", nil)
 
 	require.Error(t, err)
 }
@@ -316,10 +356,10 @@ func TestEventService_GetAttendingEvents_Empty(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().FindAttendingEvents("user-1", (*event.Pagination)(nil)).Return([]*event.Event{}, nil)
+	eventRepo.EXPECT().FindAttendingEvents(gomock.Any(), "user-1", (*event.Pagination)(nil)).Return([]*event.Event{}, nil)
 
-	svc := NewEventService(eventRepo)
-	result, err := svc.GetAttendingEvents("user-1", nil)
+	svc := newEventService(eventRepo)
+	result, err := svc.GetAttendingEvents(context.Background(), "user-1", nil)
 
 	require.NoError(t, err)
 	require.Len(t, result, 0)
@@ -331,10 +371,10 @@ func TestEventService_DeleteEvent_Success(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().Delete("event-1").Return(nil)
+	eventRepo.EXPECT().Delete(gomock.Any(), "event-1").Return(nil)
 
-	svc := NewEventService(eventRepo)
-	err := svc.DeleteEvent("event-1")
+	svc := newEventService(eventRepo)
+	err := svc.DeleteEvent(context.Background(), "event-1")
 
 	require.NoError(t, err)
 }
@@ -345,10 +385,705 @@ func TestEventService_DeleteEvent_Error(t *testing.T) {
 
 	eventRepo := mock_event.NewMockEventRepo(ctrl)
 
-	eventRepo.EXPECT().Delete("event-1").Return(errors.New("database error"))
+	eventRepo.EXPECT().Delete(gomock.Any(), "event-1").Return(errors.New("database error"))
 
-	svc := NewEventService(eventRepo)
-	err := svc.DeleteEvent("event-1")
+	svc := newEventService(eventRepo)
+	err := svc.DeleteEvent(context.Background(), "event-1")
 
 	require.Error(t, err)
 }
+
+func TestEventService_UpdateEvent_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	existing := &event.Event{
+		EventID:     "event-1",
+		Name:        "Old Name",
+		Description: "Old description",
+		StartsAt:    time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC),
+		EndsAt:      time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC),
+		Timezone:    "UTC",
+		Status:      event.StatusPublished,
+		Fee:         10.0,
+		OrganizerID: "organizer-1",
+		Tags:        []string{"Music"},
+	}
+	newName := "New Name"
+	newTags := []string{"Tech", "Meetup"}
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(existing, nil)
+	eventRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *event.Event) error {
+		require.Equal(t, "New Name", e.Name)
+		require.Equal(t, "Old description", e.Description)
+		require.Equal(t, float32(10.0), e.Fee)
+		require.Equal(t, []string{"Tech", "Meetup"}, e.Tags)
+		return nil
+	})
+
+	svc := newEventService(eventRepo)
+	result, err := svc.UpdateEvent(context.Background(), "organizer-1", "event-1", &event.EventUpdate{Name: &newName, Tags: &newTags})
+
+	require.NoError(t, err)
+	require.Equal(t, "New Name", result.Name)
+}
+
+func TestEventService_UpdateEvent_ClearCapacity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	capacity := 20
+	existing := &event.Event{
+		EventID:     "event-1",
+		Name:        "Limited",
+		StartsAt:    time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC),
+		EndsAt:      time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC),
+		Timezone:    "UTC",
+		Status:      event.StatusPublished,
+		Capacity:    &capacity,
+		OrganizerID: "organizer-1",
+	}
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(existing, nil)
+	eventRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *event.Event) error {
+		require.Nil(t, e.Capacity)
+		return nil
+	})
+
+	svc := newEventService(eventRepo)
+	result, err := svc.UpdateEvent(context.Background(), "organizer-1", "event-1", &event.EventUpdate{ClearCapacity: true})
+
+	require.NoError(t, err)
+	require.Nil(t, result.Capacity)
+}
+
+func TestEventService_UpdateEvent_NotOrganizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	existing := &event.Event{EventID: "event-1", OrganizerID: "organizer-1"}
+	newName := "New Name"
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(existing, nil)
+	eventRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.UpdateEvent(context.Background(), "someone-else", "event-1", &event.EventUpdate{Name: &newName})
+
+	require.ErrorIs(t, err, event.ErrNotOrganizer)
+}
+
+func TestEventService_UpdateEvent_EndBeforeStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	existing := &event.Event{
+		EventID:     "event-1",
+		StartsAt:    time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC),
+		EndsAt:      time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC),
+		Timezone:    "UTC",
+		Status:      event.StatusPublished,
+		OrganizerID: "organizer-1",
+	}
+	newStart := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(existing, nil)
+	eventRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.UpdateEvent(context.Background(), "organizer-1", "event-1", &event.EventUpdate{StartsAt: &newStart})
+
+	require.ErrorIs(t, err, event.ErrInvalidTimeRange)
+}
+
+func TestEventService_UpdateEvent_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "nonexistent").Return(nil, event.ErrEventNotFound)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.UpdateEvent(context.Background(), "organizer-1", "nonexistent", &event.EventUpdate{})
+
+	require.ErrorIs(t, err, event.ErrEventNotFound)
+}
+
+func TestEventService_CreateEvent_InvalidCapacity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	eventRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
+
+	capacity := 0
+	svc := newEventService(eventRepo)
+	err := svc.CreateEvent(context.Background(), &event.Event{EventID: "event-1", Name: "Test Event", Capacity: &capacity})
+
+	require.ErrorIs(t, err, event.ErrInvalidCapacity)
+}
+
+func TestEventService_RegisterAttendance_EventFullJoinsWaitlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().RegisterAttendance(gomock.Any(), "user-1", "event-1").Return(event.ErrEventFull)
+	eventRepo.EXPECT().JoinWaitlist(gomock.Any(), "user-1", "event-1").Return(3, nil)
+
+	svc := newEventService(eventRepo)
+	position, err := svc.RegisterAttendance(context.Background(), "user-1", "event-1")
+
+	require.NoError(t, err)
+	require.Equal(t, 3, position)
+}
+
+func TestEventService_RegisterAttendance_OtherErrorsSkipWaitlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().RegisterAttendance(gomock.Any(), "user-1", "event-1").Return(event.ErrAlreadyRegistered)
+	eventRepo.EXPECT().JoinWaitlist(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.RegisterAttendance(context.Background(), "user-1", "event-1")
+
+	require.ErrorIs(t, err, event.ErrAlreadyRegistered)
+}
+
+func TestEventService_GetWaitlist_Organizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	expected := []event.WaitlistEntry{
+		{UserID: "user-2", Position: 1},
+		{UserID: "user-3", Position: 2},
+	}
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(&event.Event{EventID: "event-1", OrganizerID: "organizer-1"}, nil)
+	eventRepo.EXPECT().GetWaitlist(gomock.Any(), "event-1").Return(expected, nil)
+
+	svc := newEventService(eventRepo)
+	result, err := svc.GetWaitlist(context.Background(), "organizer-1", "event-1")
+
+	require.NoError(t, err)
+	require.Equal(t, expected, result)
+}
+
+func TestEventService_GetWaitlist_NotOrganizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(&event.Event{EventID: "event-1", OrganizerID: "organizer-1"}, nil)
+	eventRepo.EXPECT().GetWaitlist(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.GetWaitlist(context.Background(), "user-2", "event-1")
+
+	require.ErrorIs(t, err, event.ErrNotOrganizer)
+}
+
+func TestEventService_GetAttendeeRoster_Organizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	pagination := &event.Pagination{Page: 1, PageSize: 50}
+	roster := []event.Attendee{
+		{UserID: "user-2", Name: "Attendee", Email: "attendee@example.com", RegisteredAt: time.Now()},
+	}
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(&event.Event{EventID: "event-1", OrganizerID: "organizer-1"}, nil)
+	eventRepo.EXPECT().GetAttendeeProfiles(gomock.Any(), "event-1", pagination).Return(roster, nil)
+
+	svc := newEventService(eventRepo)
+	got, err := svc.GetAttendeeRoster(context.Background(), "organizer-1", "event-1", pagination)
+
+	require.NoError(t, err)
+	require.Equal(t, roster, got)
+}
+
+func TestEventService_ListAttendeeRoster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	pagination := &event.Pagination{Page: 1, PageSize: 1}
+	roster := []event.Attendee{
+		{UserID: "user-2", Name: "Attendee", Email: "attendee@example.com", RegisteredAt: time.Now()},
+	}
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(&event.Event{EventID: "event-1", OrganizerID: "organizer-1"}, nil)
+	eventRepo.EXPECT().GetAttendeeProfiles(gomock.Any(), "event-1", pagination).Return(roster, nil)
+	eventRepo.EXPECT().CountAttendeeProfiles(gomock.Any(), "event-1").Return(3, nil)
+
+	svc := newEventService(eventRepo)
+	page, err := svc.ListAttendeeRoster(context.Background(), "organizer-1", "event-1", pagination)
+
+	require.NoError(t, err)
+	require.Equal(t, &event.Page[event.Attendee]{Items: roster, Page: 1, PageSize: 1, Total: 3, HasNext: true}, page)
+}
+
+func TestEventService_GetAttendeeRoster_NotOrganizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(&event.Event{EventID: "event-1", OrganizerID: "organizer-1"}, nil)
+	eventRepo.EXPECT().GetAttendeeProfiles(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.GetAttendeeRoster(context.Background(), "user-2", "event-1", nil)
+
+	require.ErrorIs(t, err, event.ErrNotOrganizer)
+}
+
+func TestEventService_GetEventsWithFilters_InvalidLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	eventRepo.EXPECT().FindAllWithFilters(gomock.Any(), gomock.Any()).Times(0)
+
+	radius := 10.0
+	svc := newEventService(eventRepo)
+	_, err := svc.GetEventsWithFilters(context.Background(), &event.EventFilter{RadiusKm: &radius})
+
+	require.ErrorIs(t, err, event.ErrLocationRequired)
+}
+
+func TestEventService_ListEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	filter := &event.EventFilter{Pagination: &event.Pagination{Page: 1, PageSize: 1}}
+	events := []*event.Event{{EventID: "event-1"}}
+
+	eventRepo.EXPECT().FindAllWithFilters(gomock.Any(), filter).Return(events, nil)
+	eventRepo.EXPECT().CountWithFilters(gomock.Any(), filter).Return(3, nil)
+
+	svc := newEventService(eventRepo)
+	page, err := svc.ListEvents(context.Background(), filter)
+
+	require.NoError(t, err)
+	require.Equal(t, events, page.Items)
+	require.Equal(t, 3, page.Total)
+	require.True(t, page.HasNext)
+}
+
+func TestEventService_ListHostingEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	pagination := &event.Pagination{Page: 2, PageSize: 2}
+
+	eventRepo.EXPECT().FindByOrganizer(gomock.Any(), "organizer-1", pagination).Return([]*event.Event{{EventID: "event-3"}}, nil)
+	eventRepo.EXPECT().CountByOrganizer(gomock.Any(), "organizer-1").Return(3, nil)
+
+	svc := newEventService(eventRepo)
+	page, err := svc.ListHostingEvents(context.Background(), "organizer-1", pagination)
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, 2, page.Page)
+	require.False(t, page.HasNext)
+}
+
+func TestEventService_ListEventsByCursor_HasNext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	startsAt := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	events := []*event.Event{
+		{EventID: "00000000-0000-0000-0000-000000000001", StartsAt: startsAt},
+		{EventID: "00000000-0000-0000-0000-000000000002", StartsAt: startsAt},
+		{EventID: "00000000-0000-0000-0000-000000000003", StartsAt: startsAt},
+	}
+
+	eventRepo.EXPECT().FindAllWithFilters(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f *event.EventFilter) ([]*event.Event, error) {
+		require.Equal(t, 3, f.Keyset.Limit)
+		return events, nil
+	})
+
+	svc := newEventService(eventRepo)
+	page, err := svc.ListEventsByCursor(context.Background(), &event.EventFilter{Keyset: &event.Keyset{Limit: 2}})
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	require.True(t, page.HasNext)
+
+	cursor, err := event.DecodeCursor(page.NextCursor)
+	require.NoError(t, err)
+	require.Equal(t, events[1].EventID, cursor.EventID)
+}
+
+func TestEventService_ListEventsByCursor_LastPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	eventRepo.EXPECT().FindAllWithFilters(gomock.Any(), gomock.Any()).Return([]*event.Event{{EventID: "event-1"}}, nil)
+
+	svc := newEventService(eventRepo)
+	page, err := svc.ListEventsByCursor(context.Background(), &event.EventFilter{Keyset: &event.Keyset{Limit: 2}})
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.False(t, page.HasNext)
+	require.Empty(t, page.NextCursor)
+}
+
+func weeklySeries() *event.Event {
+	startsAt := time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)
+	return &event.Event{
+		EventID:     "event-1",
+		Name:        "Weekly Meetup",
+		StartsAt:    startsAt,
+		EndsAt:      startsAt.Add(2 * time.Hour),
+		Timezone:    "UTC",
+		Recurrence:  "FREQ=WEEKLY;COUNT=4",
+		OrganizerID: "organizer-1",
+		Status:      event.StatusPublished,
+	}
+}
+
+func TestEventService_GetOccurrenceExceptions_OnlySeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	recurrenceID := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	cancelled := []event.OccurrenceException{{EventID: "event-1", RecurrenceID: recurrenceID, Cancelled: true}}
+	occurrence := weeklySeries()
+	occurrence.RecurrenceID = &recurrenceID
+
+	eventRepo.EXPECT().FindOccurrenceExceptions(gomock.Any(), "event-1").Return(cancelled, nil).Times(1)
+
+	svc := newEventService(eventRepo)
+	exceptions, err := svc.GetOccurrenceExceptions(context.Background(), []*event.Event{
+		weeklySeries(),
+		occurrence,
+		{EventID: "event-2", Name: "One-off"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, map[string][]event.OccurrenceException{"event-1": cancelled}, exceptions)
+}
+
+func TestEventService_RegisterOccurrence_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	recurrenceID := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().FindOccurrenceExceptions(gomock.Any(), "event-1").Return(nil, nil)
+	eventRepo.EXPECT().RegisterOccurrence(gomock.Any(), "user-1", "event-1", recurrenceID).Return(nil)
+
+	svc := newEventService(eventRepo)
+	err := svc.RegisterOccurrence(context.Background(), "user-1", "event-1", recurrenceID)
+
+	require.NoError(t, err)
+}
+
+func TestEventService_RegisterOccurrence_Cancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	recurrenceID := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().FindOccurrenceExceptions(gomock.Any(), "event-1").Return([]event.OccurrenceException{
+		{EventID: "event-1", RecurrenceID: recurrenceID, Cancelled: true},
+	}, nil)
+	eventRepo.EXPECT().RegisterOccurrence(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	err := svc.RegisterOccurrence(context.Background(), "user-1", "event-1", recurrenceID)
+
+	require.ErrorIs(t, err, event.ErrOccurrenceNotFound)
+}
+
+func TestEventService_UnregisterOccurrence_NotGenerated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().RemoveOccurrenceAttendance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	err := svc.UnregisterOccurrence(context.Background(), "user-1", "event-1", time.Date(2025, 3, 11, 18, 0, 0, 0, time.UTC))
+
+	require.ErrorIs(t, err, event.ErrOccurrenceNotFound)
+}
+
+func TestEventService_CancelOccurrence_NotOrganizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().FindOccurrenceExceptions(gomock.Any(), "event-1").Return(nil, nil)
+	eventRepo.EXPECT().SaveOccurrenceException(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	err := svc.CancelOccurrence(context.Background(), "user-2", "event-1", time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC))
+
+	require.ErrorIs(t, err, event.ErrNotOrganizer)
+}
+
+func TestEventService_RescheduleOccurrence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	recurrenceID := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	startsAt := time.Date(2025, 3, 11, 19, 0, 0, 0, time.UTC)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().FindOccurrenceExceptions(gomock.Any(), "event-1").Return(nil, nil)
+	eventRepo.EXPECT().SaveOccurrenceException(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ex *event.OccurrenceException) error {
+		require.Equal(t, recurrenceID, ex.RecurrenceID)
+		require.False(t, ex.Cancelled)
+		require.Equal(t, startsAt, *ex.StartsAt)
+		require.Equal(t, startsAt.Add(2*time.Hour), *ex.EndsAt)
+		return nil
+	})
+
+	svc := newEventService(eventRepo)
+	endsAt := startsAt.Add(2 * time.Hour)
+	o, err := svc.RescheduleOccurrence(context.Background(), "organizer-1", "event-1", recurrenceID, &startsAt, &endsAt)
+
+	require.NoError(t, err)
+	require.Equal(t, startsAt, o.StartsAt)
+}
+
+func TestEventService_RescheduleOccurrence_EndBeforeStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	recurrenceID := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	startsAt := time.Date(2025, 3, 10, 21, 0, 0, 0, time.UTC)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().FindOccurrenceExceptions(gomock.Any(), "event-1").Return(nil, nil)
+	eventRepo.EXPECT().SaveOccurrenceException(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.RescheduleOccurrence(context.Background(), "organizer-1", "event-1", recurrenceID, &startsAt, nil)
+
+	require.ErrorIs(t, err, event.ErrInvalidTimeRange)
+}
+
+func TestEventService_CreateEvent_CancelledStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	eventRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
+
+	e := weeklySeries()
+	e.Status = event.StatusCancelled
+
+	svc := newEventService(eventRepo)
+	err := svc.CreateEvent(context.Background(), e)
+
+	require.ErrorIs(t, err, event.ErrInvalidInitialStatus)
+}
+
+func TestEventService_UpdateEvent_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	draft := weeklySeries()
+	draft.Status = event.StatusDraft
+	published := event.StatusPublished
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(draft, nil)
+	eventRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *event.Event) error {
+		require.Equal(t, event.StatusPublished, e.Status)
+		return nil
+	})
+
+	svc := newEventService(eventRepo)
+	_, err := svc.UpdateEvent(context.Background(), "organizer-1", "event-1", &event.EventUpdate{Status: &published})
+
+	require.NoError(t, err)
+}
+
+func TestEventService_UpdateEvent_InvalidTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	cancelled := weeklySeries()
+	cancelled.Status = event.StatusCancelled
+	published := event.StatusPublished
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(cancelled, nil)
+	eventRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.UpdateEvent(context.Background(), "organizer-1", "event-1", &event.EventUpdate{Status: &published})
+
+	require.ErrorIs(t, err, event.ErrInvalidStatusTransition)
+}
+
+func TestEventService_CancelEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().UpdateStatus(gomock.Any(), "event-1", event.StatusCancelled).Return(nil)
+
+	svc := newEventService(eventRepo)
+	e, err := svc.CancelEvent(context.Background(), "organizer-1", "event-1")
+
+	require.NoError(t, err)
+	require.Equal(t, event.StatusCancelled, e.Status)
+}
+
+func TestEventService_CancelEvent_AlreadyCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	cancelled := weeklySeries()
+	cancelled.Status = event.StatusCancelled
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(cancelled, nil)
+	eventRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.CancelEvent(context.Background(), "organizer-1", "event-1")
+
+	require.NoError(t, err)
+}
+
+func TestEventService_CancelEvent_NotOrganizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.CancelEvent(context.Background(), "user-2", "event-1")
+
+	require.ErrorIs(t, err, event.ErrNotOrganizer)
+}
+
+func TestEventService_CancelEvent_UsesTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	txRepo := mock_event.NewMockEventRepo(ctrl)
+
+	txRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	txRepo.EXPECT().UpdateStatus(gomock.Any(), "event-1", event.StatusCancelled).Return(nil)
+
+	svc := NewEventService(eventRepo, inlineTx{uow.Repos{Events: txRepo}})
+	_, err := svc.CancelEvent(context.Background(), "organizer-1", "event-1")
+
+	require.NoError(t, err)
+}
+
+func TestEventService_UpdateEvent_TransactionFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	txManager := mock_uow.NewMockTxManager(ctrl)
+	txErr := errors.New("could not begin transaction")
+
+	txManager.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(txErr)
+
+	svc := NewEventService(eventRepo, txManager)
+	name := "Renamed"
+	e, err := svc.UpdateEvent(context.Background(), "organizer-1", "event-1", &event.EventUpdate{Name: &name})
+
+	require.ErrorIs(t, err, txErr)
+	require.Nil(t, e)
+}
+
+func TestEventService_GetVisibleEvent_Draft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	draft := weeklySeries()
+	draft.Status = event.StatusDraft
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(draft, nil).Times(2)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.GetVisibleEvent(context.Background(), "user-2", "event-1")
+	require.ErrorIs(t, err, event.ErrEventNotFound)
+
+	e, err := svc.GetVisibleEvent(context.Background(), "organizer-1", "event-1")
+	require.NoError(t, err)
+	require.Equal(t, "event-1", e.EventID)
+}
+
+func TestEventService_RestoreEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindDeletedByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().Restore(gomock.Any(), "event-1").Return(nil)
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+
+	svc := newEventService(eventRepo)
+	e, err := svc.RestoreEvent(context.Background(), "organizer-1", "event-1")
+
+	require.NoError(t, err)
+	require.Equal(t, "event-1", e.EventID)
+}
+
+func TestEventService_RestoreEvent_NotOrganizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+
+	eventRepo.EXPECT().FindDeletedByID(gomock.Any(), "event-1").Return(weeklySeries(), nil)
+	eventRepo.EXPECT().Restore(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := newEventService(eventRepo)
+	_, err := svc.RestoreEvent(context.Background(), "user-2", "event-1")
+
+	require.ErrorIs(t, err, event.ErrNotOrganizer)
+}
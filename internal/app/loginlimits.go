@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+// LimitLogins throttles failed logins per client IP and per email. Once
+// either is locked out, credentials are not checked and
+// security.LockedOutError is returned instead. A nil limiter leaves that key
+// unlimited.
+func (s *UserService) LimitLogins(byIP, byEmail security.LoginLimiter) {
+	s.ipLimiter = byIP
+	s.emailLimiter = byEmail
+}
+
+func (s *UserService) checkLoginLimits(ctx context.Context, ip string, email user.Email) error {
+	var wait time.Duration
+	if s.ipLimiter != nil && ip != "" {
+		d, err := s.ipLimiter.Wait(ctx, ip, s.now())
+		if err != nil {
+			return err
+		}
+		wait = max(wait, d)
+	}
+	if s.emailLimiter != nil {
+		d, err := s.emailLimiter.Wait(ctx, email.String(), s.now())
+		if err != nil {
+			return err
+		}
+		wait = max(wait, d)
+	}
+	if wait > 0 {
+		return &security.LockedOutError{RetryAfter: wait}
+	}
+	return nil
+}
+
+func (s *UserService) recordLoginFailure(ctx context.Context, ip string, email user.Email) error {
+	slog.Warn("Failed login attempt", "email", email.String(), "ip", ip)
+	if s.ipLimiter != nil && ip != "" {
+		lockout, err := s.ipLimiter.Fail(ctx, ip, s.now())
+		if err != nil {
+			return err
+		}
+		if lockout > 0 {
+			slog.Warn("Locked out IP after failed logins", "ip", ip, "lockout", lockout)
+		}
+	}
+	if s.emailLimiter != nil {
+		lockout, err := s.emailLimiter.Fail(ctx, email.String(), s.now())
+		if err != nil {
+			return err
+		}
+		if lockout > 0 {
+			slog.Warn("Locked out account after failed logins", "email", email.String(), "lockout", lockout)
+		}
+	}
+	return nil
+}
+
+// resetLoginFailures forgets the failures of an account after a successful
+// login. Those of the IP are kept, or an attacker could clear them by
+// logging into an account of their own in between guesses.
+func (s *UserService) resetLoginFailures(ctx context.Context, email user.Email) error {
+	if s.emailLimiter == nil {
+		return nil
+	}
+	return s.emailLimiter.Reset(ctx, email.String())
+}
+
+// PurgeLoginFailures forgets old failed logins and returns how many keys were
+// forgotten.
+func (s *UserService) PurgeLoginFailures(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for _, l := range []security.LoginLimiter{s.ipLimiter, s.emailLimiter} {
+		if l == nil {
+			continue
+		}
+		n, err := l.Purge(ctx, now)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// RunLoginFailureCleanup purges old failed logins right away and then every
+// interval, until ctx is cancelled.
+func (s *UserService) RunLoginFailureCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.PurgeLoginFailures(ctx, time.Now())
+		if err != nil {
+			slog.Error("Failed to purge login failures", "err", err)
+		} else if n > 0 {
+			slog.Info("Purged login failures", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
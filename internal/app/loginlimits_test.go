@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+	mock_security "github.com/kapiw04/convenly/internal/domain/security/mocks"
+	"github.com/kapiw04/convenly/internal/domain/user"
+	mock_user "github.com/kapiw04/convenly/internal/domain/user/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type loginLimitMocks struct {
+	userRepo     *mock_user.MockUserRepo
+	sessionRepo  *mock_user.MockSessionRepo
+	hasher       *mock_security.MockHasher
+	ipLimiter    *mock_security.MockLoginLimiter
+	emailLimiter *mock_security.MockLoginLimiter
+}
+
+func newLimitedUserService(t *testing.T, now time.Time) (*UserService, loginLimitMocks) {
+	ctrl := gomock.NewController(t)
+	m := loginLimitMocks{
+		userRepo:     mock_user.NewMockUserRepo(ctrl),
+		sessionRepo:  mock_user.NewMockSessionRepo(ctrl),
+		hasher:       mock_security.NewMockHasher(ctrl),
+		ipLimiter:    mock_security.NewMockLoginLimiter(ctrl),
+		emailLimiter: mock_security.NewMockLoginLimiter(ctrl),
+	}
+	svc := NewUserService(m.userRepo, m.sessionRepo, m.hasher)
+	svc.LimitLogins(m.ipLimiter, m.emailLimiter)
+	svc.now = func() time.Time { return now }
+	return svc, m
+}
+
+var limitedDevice = user.Device{IP: "203.0.113.7"}
+
+func TestUserService_Login_LockedOut(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newLimitedUserService(t, now)
+
+	m.ipLimiter.EXPECT().Wait(gomock.Any(), "203.0.113.7", now).Return(time.Duration(0), nil)
+	m.emailLimiter.EXPECT().Wait(gomock.Any(), "test@example.com", now).Return(2*time.Minute, nil)
+
+	_, err := svc.Login(context.Background(), "Test@Example.com", "Password123!", limitedDevice)
+
+	require.ErrorIs(t, err, security.ErrTooManyAttempts)
+	var lockedOut *security.LockedOutError
+	require.ErrorAs(t, err, &lockedOut)
+	require.Equal(t, 2*time.Minute, lockedOut.RetryAfter)
+}
+
+func TestUserService_Login_LockedOutByIP(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newLimitedUserService(t, now)
+
+	m.ipLimiter.EXPECT().Wait(gomock.Any(), "203.0.113.7", now).Return(time.Hour, nil)
+	m.emailLimiter.EXPECT().Wait(gomock.Any(), "test@example.com", now).Return(time.Minute, nil)
+
+	_, err := svc.Login(context.Background(), "test@example.com", "Password123!", limitedDevice)
+
+	var lockedOut *security.LockedOutError
+	require.ErrorAs(t, err, &lockedOut)
+	require.Equal(t, time.Hour, lockedOut.RetryAfter)
+}
+
+func TestUserService_Login_RecordsFailure(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newLimitedUserService(t, now)
+
+	m.ipLimiter.EXPECT().Wait(gomock.Any(), gomock.Any(), now).Return(time.Duration(0), nil)
+	m.emailLimiter.EXPECT().Wait(gomock.Any(), gomock.Any(), now).Return(time.Duration(0), nil)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(&user.User{PasswordHash: "hashedpassword"}, nil)
+	m.hasher.EXPECT().Compare("WrongPassword1!", "hashedpassword").Return(false)
+	m.ipLimiter.EXPECT().Fail(gomock.Any(), "203.0.113.7", now).Return(time.Duration(0), nil)
+	m.emailLimiter.EXPECT().Fail(gomock.Any(), "test@example.com", now).Return(time.Minute, nil)
+
+	_, err := svc.Login(context.Background(), "test@example.com", "WrongPassword1!", limitedDevice)
+
+	require.ErrorIs(t, err, user.ErrInvalidCredentials)
+}
+
+func TestUserService_Login_RecordsFailureForUnknownEmail(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newLimitedUserService(t, now)
+
+	m.ipLimiter.EXPECT().Wait(gomock.Any(), gomock.Any(), now).Return(time.Duration(0), nil)
+	m.emailLimiter.EXPECT().Wait(gomock.Any(), gomock.Any(), now).Return(time.Duration(0), nil)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "nobody@example.com").Return(nil, user.ErrUserNotFound)
+	m.ipLimiter.EXPECT().Fail(gomock.Any(), "203.0.113.7", now).Return(time.Duration(0), nil)
+	m.emailLimiter.EXPECT().Fail(gomock.Any(), "nobody@example.com", now).Return(time.Duration(0), nil)
+
+	_, err := svc.Login(context.Background(), "nobody@example.com", "Password123!", limitedDevice)
+
+	require.ErrorIs(t, err, user.ErrUserNotFound)
+}
+
+func TestUserService_Login_SuccessResetsEmailOnly(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newLimitedUserService(t, now)
+
+	m.ipLimiter.EXPECT().Wait(gomock.Any(), gomock.Any(), now).Return(time.Duration(0), nil)
+	m.emailLimiter.EXPECT().Wait(gomock.Any(), gomock.Any(), now).Return(time.Duration(0), nil)
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(&user.User{Email: "test@example.com", PasswordHash: "hashedpassword"}, nil)
+	m.hasher.EXPECT().Compare("Password123!", "hashedpassword").Return(true)
+	m.emailLimiter.EXPECT().Reset(gomock.Any(), "test@example.com").Return(nil)
+	m.sessionRepo.EXPECT().Create(gomock.Any(), "test@example.com", limitedDevice).Return(&user.Session{Token: "session-id"}, nil)
+
+	session, err := svc.Login(context.Background(), "test@example.com", "Password123!", limitedDevice)
+
+	require.NoError(t, err)
+	require.Equal(t, "session-id", session.Token)
+}
+
+func TestUserService_PurgeLoginFailures(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newLimitedUserService(t, now)
+
+	m.ipLimiter.EXPECT().Purge(gomock.Any(), now).Return(2, nil)
+	m.emailLimiter.EXPECT().Purge(gomock.Any(), now).Return(3, nil)
+
+	n, err := svc.PurgeLoginFailures(context.Background(), now)
+
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// PurgeExpiredSessions removes the sessions that expired before now and
+// returns how many were removed.
+func (s *UserService) PurgeExpiredSessions(ctx context.Context, now time.Time) (int, error) {
+	return s.sessionRepo.DeleteExpired(ctx, now)
+}
+
+// RunSessionCleanup removes expired sessions right away and then every
+// interval, until ctx is cancelled.
+func (s *UserService) RunSessionCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.PurgeExpiredSessions(ctx, time.Now())
+		if err != nil {
+			slog.Error("Failed to remove expired sessions", "err", err)
+		} else if n > 0 {
+			slog.Info("Removed expired sessions", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
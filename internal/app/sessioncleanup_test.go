@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mock_security "github.com/kapiw04/convenly/internal/domain/security/mocks"
+	mock_user "github.com/kapiw04/convenly/internal/domain/user/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUserService_PurgeExpiredSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	sessionRepo.EXPECT().DeleteExpired(gomock.Any(), now).Return(3, nil)
+
+	svc := NewUserService(mock_user.NewMockUserRepo(ctrl), sessionRepo, mock_security.NewMockHasher(ctrl))
+	n, err := svc.PurgeExpiredSessions(context.Background(), now)
+
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+}
+
+func TestUserService_RunSessionCleanup_StopsWithContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the first cleanup runs right away; cancel the job from inside it
+	sessionRepo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, time.Time) (int, error) {
+		cancel()
+		return 0, nil
+	})
+
+	svc := NewUserService(mock_user.NewMockUserRepo(ctrl), sessionRepo, mock_security.NewMockHasher(ctrl))
+	done := make(chan struct{})
+	go func() {
+		svc.RunSessionCleanup(ctx, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("session cleanup did not stop")
+	}
+}
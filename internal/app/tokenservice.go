@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/domain/uow"
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+// TokenPair is what a client gets in exchange for its credentials or a
+// refresh token.
+type TokenPair struct {
+	AccessToken     string
+	AccessExpiresAt time.Time
+	RefreshToken    string
+}
+
+// TokenService authenticates clients that cannot use the session cookie.
+// They send a short-lived signed access token with each request and trade a
+// refresh token, stored server-side, for a new pair when it expires.
+type TokenService struct {
+	users         *UserService
+	refreshTokens user.RefreshTokenRepo
+	signer        security.TokenSigner
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+	now           func() time.Time
+}
+
+func NewTokenService(users *UserService, refreshTokens user.RefreshTokenRepo, signer security.TokenSigner, accessTTL, refreshTTL time.Duration) *TokenService {
+	return &TokenService{
+		users:         users,
+		refreshTokens: refreshTokens,
+		signer:        signer,
+		accessTTL:     accessTTL,
+		refreshTTL:    refreshTTL,
+		now:           time.Now,
+	}
+}
+
+// IssueTokens checks the credentials, sent from ip, and returns a new token
+// pair.
+func (s *TokenService) IssueTokens(ctx context.Context, rawEmail string, rawPassword string, ip string) (*TokenPair, error) {
+	u, err := s.users.VerifyCredentials(ctx, rawEmail, rawPassword, ip)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, u.UUID.String())
+}
+
+// Refresh trades a refresh token for a new pair. The old refresh token
+// cannot be used again.
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	userID, err := s.refreshTokens.Consume(ctx, refreshToken, s.now())
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, userID)
+}
+
+// Revoke makes the refresh token unusable. Access tokens issued with it stay
+// valid until they expire.
+func (s *TokenService) Revoke(ctx context.Context, refreshToken string) error {
+	return s.refreshTokens.Delete(ctx, refreshToken)
+}
+
+// RevokeAll makes every token of the user unusable, access tokens included.
+func (s *TokenService) RevokeAll(ctx context.Context, userID string) error {
+	return s.revokeAll(ctx, uow.Repos{Users: s.users.userRepo, RefreshTokens: s.refreshTokens}, userID)
+}
+
+// revokeAll is RevokeAll on the repositories of r, so it can be part of a
+// larger unit of work.
+func (s *TokenService) revokeAll(ctx context.Context, r uow.Repos, userID string) error {
+	if err := r.Users.RevokeAccessTokens(ctx, userID, s.now()); err != nil {
+		return err
+	}
+	return r.RefreshTokens.DeleteByUser(ctx, userID)
+}
+
+// Authenticate returns the user an access token was issued to. Tokens issued
+// before the user's tokens were revoked are invalid; as iat is in whole
+// seconds, so are those issued in the second they were revoked.
+func (s *TokenService) Authenticate(ctx context.Context, accessToken string) (*user.User, error) {
+	claims, err := s.signer.Verify(accessToken, s.now())
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.users.GetByUUID(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if !u.TokensValidAfter.IsZero() && !claims.IssuedAt.After(u.TokensValidAfter.Truncate(time.Second)) {
+		return nil, security.ErrInvalidToken
+	}
+	return u, nil
+}
+
+func (s *TokenService) issue(ctx context.Context, userID string) (*TokenPair, error) {
+	now := s.now()
+	claims := security.Claims{
+		Subject:   userID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(s.accessTTL),
+	}
+	access, err := s.signer.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := s.refreshTokens.Create(ctx, userID, now.Add(s.refreshTTL))
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{AccessToken: access, AccessExpiresAt: claims.ExpiresAt, RefreshToken: refresh}, nil
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kapiw04/convenly/internal/domain/security"
+	mock_security "github.com/kapiw04/convenly/internal/domain/security/mocks"
+	"github.com/kapiw04/convenly/internal/domain/user"
+	mock_user "github.com/kapiw04/convenly/internal/domain/user/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type tokenServiceMocks struct {
+	userRepo      *mock_user.MockUserRepo
+	hasher        *mock_security.MockHasher
+	refreshTokens *mock_user.MockRefreshTokenRepo
+	signer        *mock_security.MockTokenSigner
+}
+
+func newTokenService(t *testing.T, now time.Time) (*TokenService, tokenServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := tokenServiceMocks{
+		userRepo:      mock_user.NewMockUserRepo(ctrl),
+		hasher:        mock_security.NewMockHasher(ctrl),
+		refreshTokens: mock_user.NewMockRefreshTokenRepo(ctrl),
+		signer:        mock_security.NewMockTokenSigner(ctrl),
+	}
+	users := NewUserService(m.userRepo, mock_user.NewMockSessionRepo(ctrl), m.hasher)
+	svc := NewTokenService(users, m.refreshTokens, m.signer, 15*time.Minute, 30*24*time.Hour)
+	svc.now = func() time.Time { return now }
+	return svc, m
+}
+
+func TestTokenService_IssueTokens(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newTokenService(t, now)
+	u := &user.User{UUID: uuid.New(), Email: "test@example.com", PasswordHash: "hashedpassword"}
+
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(u, nil)
+	m.hasher.EXPECT().Compare("Password123!", "hashedpassword").Return(true)
+	m.signer.EXPECT().Sign(security.Claims{
+		Subject:   u.UUID.String(),
+		IssuedAt:  now,
+		ExpiresAt: now.Add(15 * time.Minute),
+	}).Return("access", nil)
+	m.refreshTokens.EXPECT().Create(gomock.Any(), u.UUID.String(), now.Add(30*24*time.Hour)).Return("refresh", nil)
+
+	pair, err := svc.IssueTokens(context.Background(), "test@example.com", "Password123!", "")
+
+	require.NoError(t, err)
+	require.Equal(t, &TokenPair{AccessToken: "access", AccessExpiresAt: now.Add(15 * time.Minute), RefreshToken: "refresh"}, pair)
+}
+
+func TestTokenService_IssueTokens_WrongPassword(t *testing.T) {
+	svc, m := newTokenService(t, time.Now())
+	u := &user.User{UUID: uuid.New(), Email: "test@example.com", PasswordHash: "hashedpassword"}
+
+	m.userRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(u, nil)
+	m.hasher.EXPECT().Compare("WrongPassword1!", "hashedpassword").Return(false)
+
+	_, err := svc.IssueTokens(context.Background(), "test@example.com", "WrongPassword1!", "")
+
+	require.ErrorIs(t, err, user.ErrInvalidCredentials)
+}
+
+func TestTokenService_Refresh(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newTokenService(t, now)
+
+	m.refreshTokens.EXPECT().Consume(gomock.Any(), "old-refresh", now).Return("user-id", nil)
+	m.signer.EXPECT().Sign(gomock.Any()).Return("access", nil)
+	m.refreshTokens.EXPECT().Create(gomock.Any(), "user-id", gomock.Any()).Return("new-refresh", nil)
+
+	pair, err := svc.Refresh(context.Background(), "old-refresh")
+
+	require.NoError(t, err)
+	require.Equal(t, "new-refresh", pair.RefreshToken)
+}
+
+func TestTokenService_Refresh_Invalid(t *testing.T) {
+	svc, m := newTokenService(t, time.Now())
+
+	m.refreshTokens.EXPECT().Consume(gomock.Any(), "used", gomock.Any()).Return("", user.ErrInvalidRefreshToken)
+
+	_, err := svc.Refresh(context.Background(), "used")
+
+	require.ErrorIs(t, err, user.ErrInvalidRefreshToken)
+}
+
+func TestTokenService_Authenticate(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newTokenService(t, now)
+	u := &user.User{UUID: uuid.New(), Role: user.HOST}
+
+	m.signer.EXPECT().Verify("access", now).Return(&security.Claims{Subject: u.UUID.String()}, nil)
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil)
+
+	got, err := svc.Authenticate(context.Background(), "access")
+
+	require.NoError(t, err)
+	require.Equal(t, u, got)
+}
+
+func TestTokenService_Authenticate_Revoked(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newTokenService(t, now)
+	u := &user.User{UUID: uuid.New(), TokensValidAfter: now.Add(-time.Minute).Add(300 * time.Millisecond)}
+
+	m.signer.EXPECT().Verify("old", now).Return(&security.Claims{Subject: u.UUID.String(), IssuedAt: now.Add(-time.Hour)}, nil)
+	m.signer.EXPECT().Verify("same-second", now).Return(&security.Claims{Subject: u.UUID.String(), IssuedAt: now.Add(-time.Minute)}, nil)
+	m.signer.EXPECT().Verify("new", now).Return(&security.Claims{Subject: u.UUID.String(), IssuedAt: now.Add(-time.Minute + time.Second)}, nil)
+	m.userRepo.EXPECT().FindByUUID(gomock.Any(), u.UUID.String()).Return(u, nil).Times(3)
+
+	_, err := svc.Authenticate(context.Background(), "old")
+	require.ErrorIs(t, err, security.ErrInvalidToken)
+	_, err = svc.Authenticate(context.Background(), "same-second")
+	require.ErrorIs(t, err, security.ErrInvalidToken)
+	got, err := svc.Authenticate(context.Background(), "new")
+	require.NoError(t, err)
+	require.Equal(t, u, got)
+}
+
+func TestTokenService_RevokeAll(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, m := newTokenService(t, now)
+
+	m.userRepo.EXPECT().RevokeAccessTokens(gomock.Any(), "user-id", now).Return(nil)
+	m.refreshTokens.EXPECT().DeleteByUser(gomock.Any(), "user-id").Return(nil)
+
+	require.NoError(t, svc.RevokeAll(context.Background(), "user-id"))
+}
+
+func TestTokenService_Authenticate_Expired(t *testing.T) {
+	svc, m := newTokenService(t, time.Now())
+
+	m.signer.EXPECT().Verify("access", gomock.Any()).Return(nil, security.ErrTokenExpired)
+
+	_, err := svc.Authenticate(context.Background(), "access")
+
+	require.ErrorIs(t, err, security.ErrTokenExpired)
+}
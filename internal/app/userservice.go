@@ -1,9 +1,13 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/domain/uow"
 	"github.com/kapiw04/convenly/internal/domain/user"
 )
 
@@ -11,13 +15,17 @@ type UserService struct {
 	userRepo    user.UserRepo
 	sessionRepo user.SessionRepo
 	h           security.Hasher
+	// ipLimiter and emailLimiter throttle password guessing, see LimitLogins
+	ipLimiter    security.LoginLimiter
+	emailLimiter security.LoginLimiter
+	now          func() time.Time
 }
 
 func NewUserService(repo user.UserRepo, sessionRepo user.SessionRepo, h security.Hasher) *UserService {
-	return &UserService{userRepo: repo, sessionRepo: sessionRepo, h: h}
+	return &UserService{userRepo: repo, sessionRepo: sessionRepo, h: h, now: time.Now}
 }
 
-func (s *UserService) Register(name string, rawEmail string, rawPassword string) error {
+func (s *UserService) Register(ctx context.Context, name string, rawEmail string, rawPassword string) error {
 	password, err := user.NewPassword(rawPassword)
 	if err != nil {
 		return err
@@ -28,7 +36,7 @@ func (s *UserService) Register(name string, rawEmail string, rawPassword string)
 		return err
 	}
 
-	err = s.userRepo.Save(&user.User{
+	err = s.userRepo.Save(ctx, &user.User{
 		Name:         name,
 		Email:        rawEmail,
 		PasswordHash: passwordHash,
@@ -41,57 +49,178 @@ func (s *UserService) Register(name string, rawEmail string, rawPassword string)
 	return nil
 }
 
-func (s *UserService) GetByEmail(rawEmail string) (*user.User, error) {
+func (s *UserService) GetByEmail(ctx context.Context, rawEmail string) (*user.User, error) {
 	slog.Info("Getting user with email: %s", "email", rawEmail)
 	email, err := user.NewEmail(rawEmail)
 	if err != nil {
 		return nil, err
 	}
-	return s.userRepo.FindByEmail(email.String())
+	return s.userRepo.FindByEmail(ctx, email.String())
 }
 
-func (s *UserService) GetByUUID(userID string) (*user.User, error) {
+func (s *UserService) GetByUUID(ctx context.Context, userID string) (*user.User, error) {
 	slog.Info("Getting user with UUID: %s", "uuid", userID)
-	return s.userRepo.FindByUUID(userID)
+	return s.userRepo.FindByUUID(ctx, userID)
 }
 
-func (s *UserService) Login(rawEmail string, rawPassword string) (string, error) {
+// Login checks the credentials and starts a session on the device.
+func (s *UserService) Login(ctx context.Context, rawEmail string, rawPassword string, device user.Device) (*user.Session, error) {
+	u, err := s.VerifyCredentials(ctx, rawEmail, rawPassword, device.IP)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionRepo.Create(ctx, u.Email, device)
+}
+
+// VerifyCredentials returns the user with the email if the password is
+// theirs. The attempt, made from ip, counts towards the login limits.
+func (s *UserService) VerifyCredentials(ctx context.Context, rawEmail string, rawPassword string, ip string) (*user.User, error) {
 	email, err := user.NewEmail(rawEmail)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	password, err := user.NewPassword(rawPassword)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	u, err := s.userRepo.FindByEmail(string(email))
+	if err := s.checkLoginLimits(ctx, ip, email); err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.FindByEmail(ctx, string(email))
+	if err == nil && !s.h.Compare(string(password), u.PasswordHash) {
+		err = user.ErrInvalidCredentials
+	}
+	switch {
+	case errors.Is(err, user.ErrInvalidCredentials), errors.Is(err, user.ErrUserNotFound):
+		if err := s.recordLoginFailure(ctx, ip, email); err != nil {
+			return nil, err
+		}
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+	if err := s.resetLoginFailures(ctx, email); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// SetPassword replaces the user's password and ends all their sessions, so
+// whoever knew the old one is logged out.
+func (s *UserService) SetPassword(ctx context.Context, userID string, rawPassword string) error {
+	return s.setPassword(ctx, uow.Repos{Users: s.userRepo, Sessions: s.sessionRepo}, userID, rawPassword)
+}
+
+// setPassword is SetPassword on the repositories of r, so it can be part of
+// a larger unit of work.
+func (s *UserService) setPassword(ctx context.Context, r uow.Repos, userID string, rawPassword string) error {
+	password, err := user.NewPassword(rawPassword)
+	if err != nil {
+		return err
+	}
+	u, err := r.Users.FindByUUID(ctx, userID)
 	if err != nil {
-		return "", err
+		return err
 	}
-	ok := s.h.Compare(string(password), u.PasswordHash)
-	if !ok {
-		return "", user.ErrInvalidCredentials
+	passwordHash, err := s.h.Hash(string(password))
+	if err != nil {
+		return err
 	}
-	return s.sessionRepo.Create(string(email))
+	if err := r.Users.SetPasswordHash(ctx, userID, passwordHash); err != nil {
+		return err
+	}
+	if err := r.Sessions.DeleteByUser(ctx, userID); err != nil {
+		return err
+	}
+	// the owner has proven themselves, so a lockout of the account is over
+	return s.resetLoginFailures(ctx, user.Email(u.Email))
 }
 
-func (s *UserService) Logout(sessionID string) error {
-	return s.sessionRepo.Delete(sessionID)
+// MarkEmailVerified records that the user has shown they read mail sent to
+// their email.
+func (s *UserService) MarkEmailVerified(ctx context.Context, userID string, email string) error {
+	return s.userRepo.MarkEmailVerified(ctx, userID, email)
 }
 
-func (s *UserService) GetBySessionID(sessionID string) (*user.User, error) {
-	u, err := s.sessionRepo.Get(sessionID)
+// UpdateProfile changes the fields of the user's profile set in upd.
+func (s *UserService) UpdateProfile(ctx context.Context, userID string, upd *user.ProfileUpdate) (*user.User, error) {
+	u, err := s.userRepo.FindByUUID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	return &u, nil
+	upd.Apply(u)
+	if upd != nil && upd.Name != nil {
+		if err := s.userRepo.UpdateName(ctx, userID, u.Name); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// Delete removes the user's account, ending their sessions and deleting the
+// events they host.
+func (s *UserService) Delete(ctx context.Context, userID string) error {
+	return s.userRepo.DeleteByUUID(ctx, userID)
+}
+
+func (s *UserService) Logout(ctx context.Context, sessionID string) error {
+	return s.sessionRepo.Delete(ctx, sessionID)
+}
+
+// ListSessions returns where the user is logged in.
+func (s *UserService) ListSessions(ctx context.Context, userID string) ([]*user.Session, error) {
+	return s.sessionRepo.ListByUser(ctx, userID)
+}
+
+// RevokeSession ends one of the user's sessions by its ID.
+func (s *UserService) RevokeSession(ctx context.Context, userID string, sessionID string) error {
+	return s.sessionRepo.DeleteByID(ctx, userID, sessionID)
+}
+
+// LogoutEverywhere ends every session of the user, on all devices.
+func (s *UserService) LogoutEverywhere(ctx context.Context, userID string) error {
+	return s.sessionRepo.DeleteByUser(ctx, userID)
 }
 
-func (s *UserService) PromoteToHost(userID string) error {
-	u, err := s.userRepo.FindByUUID(userID)
+// Authenticate returns the user of a session along with the session, which
+// is renewed by the lookup.
+func (s *UserService) Authenticate(ctx context.Context, sessionID string) (*user.User, *user.Session, error) {
+	session, err := s.sessionRepo.Get(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	u, err := s.userRepo.FindByUUID(ctx, session.UserID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, session, nil
+}
+
+func (s *UserService) GetBySessionID(ctx context.Context, sessionID string) (*user.User, error) {
+	u, _, err := s.Authenticate(ctx, sessionID)
+	return u, err
+}
+
+func (s *UserService) PromoteToHost(ctx context.Context, userID string) error {
+	return s.userRepo.SetRole(ctx, userID, user.HOST)
+}
+
+// GetCalendarToken returns a new calendar feed token if the user has none,
+// or "" if they have one: it cannot be shown again, only rotated.
+func (s *UserService) GetCalendarToken(ctx context.Context, userID string) (string, error) {
+	return s.userRepo.CalendarToken(ctx, userID)
+}
+
+// RotateCalendarToken replaces the user's calendar feed token, invalidating
+// any subscription that uses the old one.
+func (s *UserService) RotateCalendarToken(ctx context.Context, userID string) (string, error) {
+	return s.userRepo.RotateCalendarToken(ctx, userID)
+}
+
+func (s *UserService) GetByCalendarToken(ctx context.Context, token string) (*user.User, error) {
+	if token == "" {
+		return nil, user.ErrUserNotFound
 	}
-	u.Role = user.HOST
-	return s.userRepo.Update(u)
+	return s.userRepo.FindByCalendarToken(ctx, token)
 }
@@ -1,9 +1,11 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"testing"
 
+	"github.com/google/uuid"
 	mock_security "github.com/kapiw04/convenly/internal/domain/security/mocks"
 	"github.com/kapiw04/convenly/internal/domain/user"
 	mock_user "github.com/kapiw04/convenly/internal/domain/user/mocks"
@@ -20,10 +22,10 @@ func TestUserService_Register_Success(t *testing.T) {
 	hasher := mock_security.NewMockHasher(ctrl)
 
 	hasher.EXPECT().Hash("Password123!").Return("hashedpassword", nil)
-	userRepo.EXPECT().Save(gomock.Any()).Return(nil)
+	userRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	err := svc.Register("TestUser", "test@example.com", "Password123!")
+	err := svc.Register(context.Background(), "TestUser", "test@example.com", "Password123!")
 
 	require.NoError(t, err)
 }
@@ -37,7 +39,7 @@ func TestUserService_Register_InvalidEmail(t *testing.T) {
 	hasher := mock_security.NewMockHasher(ctrl)
 	userRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(user.ErrInvalidEmailFormat).
 		Times(1)
 
@@ -48,7 +50,7 @@ func TestUserService_Register_InvalidEmail(t *testing.T) {
 		Times(1)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	err := svc.Register("TestUser", "invalid-email", "Password123!")
+	err := svc.Register(context.Background(), "TestUser", "invalid-email", "Password123!")
 
 	require.Error(t, err)
 }
@@ -62,7 +64,7 @@ func TestUserService_Register_InvalidPassword(t *testing.T) {
 	hasher := mock_security.NewMockHasher(ctrl)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	err := svc.Register("TestUser", "test@example.com", "short")
+	err := svc.Register(context.Background(), "TestUser", "test@example.com", "short")
 
 	require.Error(t, err)
 }
@@ -78,7 +80,7 @@ func TestUserService_Register_HashError(t *testing.T) {
 	hasher.EXPECT().Hash("Password123!").Return("", errors.New("hashing failed"))
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	err := svc.Register("TestUser", "test@example.com", "Password123!")
+	err := svc.Register(context.Background(), "TestUser", "test@example.com", "Password123!")
 
 	require.Error(t, err)
 }
@@ -92,10 +94,10 @@ func TestUserService_Register_SaveError(t *testing.T) {
 	hasher := mock_security.NewMockHasher(ctrl)
 
 	hasher.EXPECT().Hash("Password123!").Return("hashedpassword", nil)
-	userRepo.EXPECT().Save(gomock.Any()).Return(errors.New("database error"))
+	userRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	err := svc.Register("TestUser", "test@example.com", "Password123!")
+	err := svc.Register(context.Background(), "TestUser", "test@example.com", "Password123!")
 
 	require.Error(t, err)
 }
@@ -113,15 +115,16 @@ func TestUserService_Login_Success(t *testing.T) {
 		PasswordHash: "hashedpassword",
 	}
 
-	userRepo.EXPECT().FindByEmail("test@example.com").Return(testUser, nil)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(testUser, nil)
 	hasher.EXPECT().Compare("Password123!", "hashedpassword").Return(true)
-	sessionRepo.EXPECT().Create("test@example.com").Return("session-id", nil)
+	device := user.Device{UserAgent: "curl/8.0", IP: "203.0.113.7"}
+	sessionRepo.EXPECT().Create(gomock.Any(), "test@example.com", device).Return(&user.Session{Token: "session-id"}, nil)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	sessionID, err := svc.Login("test@example.com", "Password123!")
+	session, err := svc.Login(context.Background(), "test@example.com", "Password123!", device)
 
 	require.NoError(t, err)
-	require.Equal(t, "session-id", sessionID)
+	require.Equal(t, "session-id", session.Token)
 }
 
 func TestUserService_Login_InvalidEmail(t *testing.T) {
@@ -133,7 +136,7 @@ func TestUserService_Login_InvalidEmail(t *testing.T) {
 	hasher := mock_security.NewMockHasher(ctrl)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	_, err := svc.Login("invalid-email", "Password123!")
+	_, err := svc.Login(context.Background(), "invalid-email", "Password123!", user.Device{})
 
 	require.Error(t, err)
 }
@@ -147,7 +150,7 @@ func TestUserService_Login_InvalidPassword(t *testing.T) {
 	hasher := mock_security.NewMockHasher(ctrl)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	_, err := svc.Login("test@example.com", "short")
+	_, err := svc.Login(context.Background(), "test@example.com", "short", user.Device{})
 
 	require.Error(t, err)
 }
@@ -160,10 +163,10 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
 	hasher := mock_security.NewMockHasher(ctrl)
 
-	userRepo.EXPECT().FindByEmail("test@example.com").Return(nil, errors.New("user not found"))
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(nil, errors.New("user not found"))
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	_, err := svc.Login("test@example.com", "Password123!")
+	_, err := svc.Login(context.Background(), "test@example.com", "Password123!", user.Device{})
 
 	require.Error(t, err)
 }
@@ -181,11 +184,11 @@ func TestUserService_Login_WrongPassword(t *testing.T) {
 		PasswordHash: "hashedpassword",
 	}
 
-	userRepo.EXPECT().FindByEmail("test@example.com").Return(testUser, nil)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(testUser, nil)
 	hasher.EXPECT().Compare("WrongPassword123!", "hashedpassword").Return(false)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	_, err := svc.Login("test@example.com", "WrongPassword123!")
+	_, err := svc.Login(context.Background(), "test@example.com", "WrongPassword123!", user.Device{})
 
 	require.ErrorIs(t, err, user.ErrInvalidCredentials)
 }
@@ -198,10 +201,10 @@ func TestUserService_Logout_Success(t *testing.T) {
 	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
 	hasher := mock_security.NewMockHasher(ctrl)
 
-	sessionRepo.EXPECT().Delete("session-id").Return(nil)
+	sessionRepo.EXPECT().Delete(gomock.Any(), "session-id").Return(nil)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	err := svc.Logout("session-id")
+	err := svc.Logout(context.Background(), "session-id")
 
 	require.NoError(t, err)
 }
@@ -214,19 +217,10 @@ func TestUserService_PromoteToHost_Success(t *testing.T) {
 	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
 	hasher := mock_security.NewMockHasher(ctrl)
 
-	testUser := &user.User{
-		Name: "TestUser",
-		Role: user.ATTENDEE,
-	}
-
-	userRepo.EXPECT().FindByUUID("user-uuid").Return(testUser, nil)
-	userRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *user.User) error {
-		require.Equal(t, user.HOST, u.Role)
-		return nil
-	})
+	userRepo.EXPECT().SetRole(gomock.Any(), "user-uuid", user.HOST).Return(nil)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	err := svc.PromoteToHost("user-uuid")
+	err := svc.PromoteToHost(context.Background(), "user-uuid")
 
 	require.NoError(t, err)
 }
@@ -239,10 +233,10 @@ func TestUserService_PromoteToHost_UserNotFound(t *testing.T) {
 	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
 	hasher := mock_security.NewMockHasher(ctrl)
 
-	userRepo.EXPECT().FindByUUID("nonexistent-uuid").Return(nil, errors.New("user not found"))
+	userRepo.EXPECT().SetRole(gomock.Any(), "nonexistent-uuid", user.HOST).Return(user.ErrUserNotFound)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	err := svc.PromoteToHost("nonexistent-uuid")
+	err := svc.PromoteToHost(context.Background(), "nonexistent-uuid")
 
 	require.Error(t, err)
 }
@@ -255,15 +249,17 @@ func TestUserService_GetBySessionID_Success(t *testing.T) {
 	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
 	hasher := mock_security.NewMockHasher(ctrl)
 
-	testUser := user.User{
+	testUser := &user.User{
+		UUID:  uuid.New(),
 		Name:  "TestUser",
 		Email: "test@example.com",
 	}
 
-	sessionRepo.EXPECT().Get("session-id").Return(testUser, nil)
+	sessionRepo.EXPECT().Get(gomock.Any(), "session-id").Return(&user.Session{Token: "session-id", UserID: testUser.UUID}, nil)
+	userRepo.EXPECT().FindByUUID(gomock.Any(), testUser.UUID.String()).Return(testUser, nil)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	u, err := svc.GetBySessionID("session-id")
+	u, err := svc.GetBySessionID(context.Background(), "session-id")
 
 	require.NoError(t, err)
 	require.Equal(t, "TestUser", u.Name)
@@ -277,10 +273,96 @@ func TestUserService_GetBySessionID_NotFound(t *testing.T) {
 	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
 	hasher := mock_security.NewMockHasher(ctrl)
 
-	sessionRepo.EXPECT().Get("invalid-session").Return(user.User{}, errors.New("session not found"))
+	sessionRepo.EXPECT().Get(gomock.Any(), "invalid-session").Return(nil, user.ErrSessionNotFound)
 
 	svc := NewUserService(userRepo, sessionRepo, hasher)
-	_, err := svc.GetBySessionID("invalid-session")
+	_, err := svc.GetBySessionID(context.Background(), "invalid-session")
 
-	require.Error(t, err)
+	require.ErrorIs(t, err, user.ErrSessionNotFound)
+}
+
+func TestUserService_RevokeSession_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mock_user.NewMockUserRepo(ctrl)
+	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
+	hasher := mock_security.NewMockHasher(ctrl)
+
+	sessionRepo.EXPECT().DeleteByID(gomock.Any(), "user-id", "other-session").Return(user.ErrSessionNotFound)
+
+	svc := NewUserService(userRepo, sessionRepo, hasher)
+	err := svc.RevokeSession(context.Background(), "user-id", "other-session")
+
+	require.ErrorIs(t, err, user.ErrSessionNotFound)
+}
+
+func TestUserService_LogoutEverywhere(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mock_user.NewMockUserRepo(ctrl)
+	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
+	hasher := mock_security.NewMockHasher(ctrl)
+
+	sessionRepo.EXPECT().DeleteByUser(gomock.Any(), "user-id").Return(nil)
+
+	svc := NewUserService(userRepo, sessionRepo, hasher)
+	require.NoError(t, svc.LogoutEverywhere(context.Background(), "user-id"))
+}
+
+func TestUserService_GetByCalendarToken_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mock_user.NewMockUserRepo(ctrl)
+	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
+	hasher := mock_security.NewMockHasher(ctrl)
+
+	expected := &user.User{Name: "TestUser", Email: "test@example.com"}
+	userRepo.EXPECT().FindByCalendarToken(gomock.Any(), "token-123").Return(expected, nil)
+
+	svc := NewUserService(userRepo, sessionRepo, hasher)
+	u, err := svc.GetByCalendarToken(context.Background(), "token-123")
+
+	require.NoError(t, err)
+	require.Equal(t, expected, u)
+}
+
+func TestUserService_GetByCalendarToken_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mock_user.NewMockUserRepo(ctrl)
+	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
+	hasher := mock_security.NewMockHasher(ctrl)
+
+	userRepo.EXPECT().FindByCalendarToken(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := NewUserService(userRepo, sessionRepo, hasher)
+	_, err := svc.GetByCalendarToken(context.Background(), "")
+
+	require.ErrorIs(t, err, user.ErrUserNotFound)
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mock_user.NewMockUserRepo(ctrl)
+	sessionRepo := mock_user.NewMockSessionRepo(ctrl)
+	hasher := mock_security.NewMockHasher(ctrl)
+
+	existing := &user.User{UUID: uuid.New(), Name: "TestUser", Email: "test@example.com", Role: user.HOST}
+	userRepo.EXPECT().FindByUUID(gomock.Any(), existing.UUID.String()).Return(existing, nil)
+	userRepo.EXPECT().UpdateName(gomock.Any(), existing.UUID.String(), "Renamed User").Return(nil)
+
+	svc := NewUserService(userRepo, sessionRepo, hasher)
+	name := "Renamed User"
+	u, err := svc.UpdateProfile(context.Background(), existing.UUID.String(), &user.ProfileUpdate{Name: &name})
+
+	require.NoError(t, err)
+	require.Equal(t, "Renamed User", u.Name)
+	require.Equal(t, "test@example.com", u.Email)
+	require.Equal(t, user.HOST, u.Role)
 }
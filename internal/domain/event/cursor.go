@@ -0,0 +1,61 @@
+package event
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor points at an event in the (starts_at, event_id) ordering of a listing.
+// Keyset pagination resumes right after it.
+type Cursor struct {
+	StartsAt time.Time
+	EventID  string
+}
+
+func CursorFor(e *Event) Cursor {
+	return Cursor{StartsAt: e.StartsAt, EventID: e.EventID}
+}
+
+// Encode returns the opaque form of the cursor handed out to clients.
+func (c Cursor) Encode() string {
+	raw := c.StartsAt.UTC().Format(time.RFC3339Nano) + "," + c.EventID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	startsAt, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, startsAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{StartsAt: t, EventID: id}, nil
+}
+
+// Keyset selects keyset pagination: up to Limit events strictly after the
+// cursor, or from the start when After is nil.
+type Keyset struct {
+	After *Cursor
+	Limit int
+}
+
+// CursorPage is one page of a keyset paginated listing. NextCursor is empty
+// on the last page.
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	PageSize   int    `json:"page_size"`
+	HasNext    bool   `json:"has_next"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,47 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{
+		StartsAt: time.Date(2025, 12, 31, 18, 30, 0, 123, time.UTC),
+		EventID:  "123e4567-e89b-12d3-a456-426614174000",
+	}
+
+	decoded, err := DecodeCursor(c.Encode())
+
+	require.NoError(t, err)
+	require.True(t, c.StartsAt.Equal(decoded.StartsAt))
+	require.Equal(t, c.EventID, decoded.EventID)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"not base64!",
+		"bm8tY29tbWE",                    // "no-comma"
+		"bm90LWEtZGF0ZSx4",               // "not-a-date,x"
+		"MjAyNS0wMS0wMVQwMDowMDowMFoseA", // "2025-01-01T00:00:00Z,x"
+	} {
+		_, err := DecodeCursor(s)
+		require.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestEventFilter_Validate_Keyset(t *testing.T) {
+	f := &EventFilter{Keyset: &Keyset{Limit: 10}}
+	require.NoError(t, f.Validate())
+
+	f = &EventFilter{Keyset: &Keyset{Limit: 10}, Pagination: &Pagination{Page: 1, PageSize: 10}}
+	require.Equal(t, ErrPaginationConflict, f.Validate())
+
+	f = &EventFilter{Keyset: &Keyset{Limit: 10}, Query: "jazz"}
+	require.Equal(t, ErrCursorNotSupported, f.Validate())
+
+	f = &EventFilter{Keyset: &Keyset{}}
+	require.Equal(t, ErrInvalidKeysetLimit, f.Validate())
+}
@@ -0,0 +1,41 @@
+package event
+
+import "errors"
+
+var (
+	ErrEventNotFound    = errors.New("event not found")
+	ErrNotOrganizer     = errors.New("only the organizer can modify this event")
+	ErrEventFull        = errors.New("event is full")
+	ErrInvalidCapacity  = errors.New("capacity must be at least 1")
+	ErrCapacityConflict = errors.New("capacity and clear_capacity cannot be combined")
+
+	ErrInvalidStatus           = errors.New("invalid status, supported values: draft, published, cancelled, completed")
+	ErrInvalidStatusTransition = errors.New("event cannot move to that status")
+	ErrInvalidInitialStatus    = errors.New("new events must be draft or published")
+	ErrEventCancelled          = errors.New("event is cancelled")
+	ErrRegistrationClosed      = errors.New("event is not open for registration")
+
+	ErrInvalidTimeRange = errors.New("event must end after it starts")
+	ErrInvalidTimezone  = errors.New("timezone must be a valid IANA time zone name")
+
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule, supported: FREQ=DAILY|WEEKLY|MONTHLY with INTERVAL, COUNT or UNTIL, and BYDAY")
+	ErrRecurrenceStart    = errors.New("event start must be the first occurrence of its recurrence rule")
+	ErrNotRecurring       = errors.New("event does not repeat")
+	ErrOccurrenceNotFound = errors.New("occurrence not found")
+
+	ErrInvalidLatitude  = errors.New("latitude must be between -90 and 90")
+	ErrInvalidLongitude = errors.New("longitude must be between -180 and 180")
+	ErrInvalidRadius    = errors.New("radius must be greater than 0")
+	ErrLocationRequired = errors.New("a location is required to search or sort by distance")
+	ErrQueryTooLong     = errors.New("search query is too long")
+	ErrInvalidSort      = errors.New("invalid sort, supported values: date_asc, date_desc, fee_asc, fee_desc, popularity, newest, distance")
+
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrPaginationConflict = errors.New("page and cursor pagination cannot be combined")
+	ErrCursorNotSupported = errors.New("cursor pagination only supports ordering by date")
+	ErrInvalidKeysetLimit = errors.New("cursor page size must be at least 1")
+
+	ErrAlreadyRegistered = errors.New("user is already registered for this event")
+	ErrAlreadyWaitlisted = errors.New("user is already on the waitlist")
+	ErrNotWaitlisted     = errors.New("user is not on the waitlist")
+)
@@ -2,18 +2,168 @@ package event
 
 //go:generate mockgen -destination=./mocks/mock_eventrepo.go -package mock_event . EventRepo
 
-import "time"
+import (
+	"context"
+	"math"
+	"time"
+	// Embedded so timezone validation does not depend on the host's zoneinfo.
+	_ "time/tzdata"
+)
 
 type Event struct {
 	EventID     string    `json:"event_id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
-	Date        time.Time `json:"date"`
-	Latitude    float64   `json:"latitude"`
-	Longitude   float64   `json:"longitude"`
-	Fee         float32   `json:"fee"`
-	OrganizerID string    `json:"organizer_id"`
-	Tags        []string  `json:"tag,omitempty"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	// Timezone is the IANA name of the zone the event takes place in.
+	// StartsAt and EndsAt are absolute instants; it only matters for
+	// presenting them in local time.
+	Timezone string `json:"timezone"`
+	// Recurrence is an RRULE repeating the event, empty for one-off events.
+	// StartsAt and EndsAt then describe the first occurrence.
+	Recurrence  string   `json:"recurrence,omitempty"`
+	Latitude    float64  `json:"latitude"`
+	Longitude   float64  `json:"longitude"`
+	Fee         float32  `json:"fee"`
+	Capacity    *int     `json:"capacity,omitempty"`
+	OrganizerID string   `json:"organizer_id"`
+	Tags        []string `json:"tag,omitempty"`
+	Status      Status   `json:"status"`
+	// DistanceKm is the distance from the searched location. It is only set
+	// by searches that include a location.
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+	// SearchRank and Headline are only set by text searches. Headline is an
+	// HTML snippet of the description: the text is escaped and the matched
+	// words are wrapped in <b></b>.
+	SearchRank *float32 `json:"rank,omitempty"`
+	Headline   string   `json:"headline,omitempty"`
+	// RecurrenceID is set on the occurrences of a recurring event expanded
+	// by listings with a date window; StartsAt and EndsAt are then those of
+	// the occurrence.
+	RecurrenceID *time.Time `json:"recurrence_id,omitempty"`
+}
+
+func (e *Event) Validate() error {
+	if e.Capacity != nil && *e.Capacity < 1 {
+		return ErrInvalidCapacity
+	}
+	if !e.EndsAt.After(e.StartsAt) {
+		return ErrInvalidTimeRange
+	}
+	if _, err := ParseStatus(string(e.Status)); err != nil {
+		return err
+	}
+	if _, err := LoadTimezone(e.Timezone); err != nil {
+		return err
+	}
+	if e.IsRecurring() {
+		if err := e.validateRecurrence(); err != nil {
+			return err
+		}
+	}
+	return GeoPoint{Latitude: e.Latitude, Longitude: e.Longitude}.Validate()
+}
+
+// LoadTimezone returns the location for an IANA time zone name. Unlike
+// time.LoadLocation it rejects the empty string and "Local", which depend on
+// the server rather than on the event.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// RemainingSeats returns the number of free seats given the current number of
+// attendees, or nil when the event has no capacity limit.
+func (e *Event) RemainingSeats(attendees int) *int {
+	if e.Capacity == nil {
+		return nil
+	}
+	remaining := max(*e.Capacity-attendees, 0)
+	return &remaining
+}
+
+type Attendee struct {
+	UserID       string    `json:"user_id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	RegisteredAt time.Time `json:"registered_at"`
+	// RecurrenceID is set for users registered for a single occurrence of
+	// a recurring event rather than the whole series.
+	RecurrenceID *time.Time `json:"recurrence_id,omitempty"`
+}
+
+type WaitlistEntry struct {
+	UserID   string    `json:"user_id"`
+	Position int       `json:"position"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type EventUpdate struct {
+	Name        *string
+	Description *string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	Timezone    *string
+	Recurrence  *string
+	Latitude    *float64
+	Longitude   *float64
+	Fee         *float32
+	Capacity    *int
+	Tags        *[]string
+	// ClearCapacity removes the capacity limit and takes precedence over
+	// Capacity.
+	ClearCapacity bool
+	// Status is not set by Apply; it is applied with Event.TransitionTo so
+	// that invalid transitions are refused.
+	Status *Status
+}
+
+func (u *EventUpdate) Apply(e *Event) {
+	if u == nil {
+		return
+	}
+	if u.Name != nil {
+		e.Name = *u.Name
+	}
+	if u.Description != nil {
+		e.Description = *u.Description
+	}
+	if u.StartsAt != nil {
+		e.StartsAt = *u.StartsAt
+	}
+	if u.EndsAt != nil {
+		e.EndsAt = *u.EndsAt
+	}
+	if u.Timezone != nil {
+		e.Timezone = *u.Timezone
+	}
+	if u.Recurrence != nil {
+		e.Recurrence = *u.Recurrence
+	}
+	if u.Latitude != nil {
+		e.Latitude = *u.Latitude
+	}
+	if u.Longitude != nil {
+		e.Longitude = *u.Longitude
+	}
+	if u.Fee != nil {
+		e.Fee = *u.Fee
+	}
+	if u.ClearCapacity {
+		e.Capacity = nil
+	} else if u.Capacity != nil {
+		e.Capacity = u.Capacity
+	}
+	if u.Tags != nil {
+		e.Tags = *u.Tags
+	}
 }
 
 type Pagination struct {
@@ -35,27 +185,150 @@ func (p *Pagination) Limit() int {
 	return p.PageSize
 }
 
+// Page is one page of a paginated listing together with the metadata
+// clients need to navigate the rest of it.
+type Page[T any] struct {
+	Items    []T  `json:"items"`
+	Page     int  `json:"page"`
+	PageSize int  `json:"page_size"`
+	Total    int  `json:"total"`
+	HasNext  bool `json:"has_next"`
+}
+
+// NewPage wraps items fetched with pagination. A nil pagination means the
+// items are the whole listing.
+func NewPage[T any](items []T, pagination *Pagination, total int) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	if pagination == nil || pagination.Limit() == 0 {
+		return &Page[T]{Items: items, Page: 1, PageSize: len(items), Total: total}
+	}
+	return &Page[T]{
+		Items:    items,
+		Page:     max(pagination.Page, 1),
+		PageSize: pagination.PageSize,
+		Total:    total,
+		HasNext:  pagination.Offset()+len(items) < total,
+	}
+}
+
+// MaxQueryLength is the longest accepted free-text search query, in bytes.
+const MaxQueryLength = 200
+
 type EventFilter struct {
-	DateFrom   *time.Time
-	DateTo     *time.Time
-	MinFee     *float32
-	MaxFee     *float32
-	Tags       []string
+	// DateFrom and DateTo select events overlapping that window, so a
+	// multi-day event matches any window it is still running in.
+	DateFrom *time.Time
+	DateTo   *time.Time
+	MinFee   *float32
+	MaxFee   *float32
+	Tags     []string
+	// Query is a free-text search over the event name and description.
+	Query string
+	// Near restricts and annotates the results with the distance from a
+	// location. RadiusKm, when set, drops events further away than that.
+	Near     *GeoPoint
+	RadiusKm *float64
+	// Sort defaults to relevance for text searches and to SortDateAsc
+	// otherwise.
+	Sort SortOrder
+	// Pagination and Keyset are alternative ways to page through the
+	// results; at most one of them may be set.
 	Pagination *Pagination
+	Keyset     *Keyset
+}
+
+func (f *EventFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	if len(f.Query) > MaxQueryLength {
+		return ErrQueryTooLong
+	}
+	if f.Keyset != nil {
+		if f.Pagination != nil {
+			return ErrPaginationConflict
+		}
+		if !f.Sort.SupportsKeyset() || (f.Sort == "" && f.Query != "") {
+			return ErrCursorNotSupported
+		}
+		if f.Keyset.Limit < 1 {
+			return ErrInvalidKeysetLimit
+		}
+	}
+	if f.Sort != "" {
+		if _, err := ParseSortOrder(string(f.Sort)); err != nil {
+			return err
+		}
+	}
+	if f.Near == nil {
+		if f.RadiusKm != nil || f.Sort == SortDistance {
+			return ErrLocationRequired
+		}
+		return nil
+	}
+	if err := f.Near.Validate(); err != nil {
+		return err
+	}
+	if f.RadiusKm != nil && (math.IsNaN(*f.RadiusKm) || *f.RadiusKm <= 0) {
+		return ErrInvalidRadius
+	}
+	return nil
+}
+
+// OccurrenceWindow returns the window recurring events are expanded into
+// occurrences in. Listings without a date window show each series once.
+func (f *EventFilter) OccurrenceWindow() (from, to time.Time, ok bool) {
+	if f == nil || (f.DateFrom == nil && f.DateTo == nil) {
+		return from, to, false
+	}
+	if f.DateFrom != nil {
+		from = *f.DateFrom
+	}
+	if f.DateTo != nil {
+		to = *f.DateTo
+	} else {
+		to = from.Add(MaxExpansionWindow)
+	}
+	return from, to, true
 }
 
 type EventRepo interface {
-	Save(*Event) error
-	FindByID(string) (*Event, error)
-	FindAll() ([]*Event, error)
-	FindAllWithFilters(filter *EventFilter) ([]*Event, error)
-	FindAllByTags(tagNames []string) ([]*Event, error)
-	RegisterAttendance(userID, eventID string) error
-	IsUserAttending(userID string, eventID string) bool
-	GetAttendees(eventID string) ([]string, error)
-	GetAttendeesCount(eventID string) (int, error)
-	RemoveAttendance(userID, eventID string) error
-	FindByOrganizer(userID string, pagination *Pagination) ([]*Event, error)
-	FindAttendingEvents(userID string, pagination *Pagination) ([]*Event, error)
-	Delete(eventID string) error
+	Save(ctx context.Context, e *Event) error
+	Update(ctx context.Context, e *Event) error
+	FindByID(ctx context.Context, eventID string) (*Event, error)
+	FindAll(ctx context.Context) ([]*Event, error)
+	FindAllWithFilters(ctx context.Context, filter *EventFilter) ([]*Event, error)
+	CountWithFilters(ctx context.Context, filter *EventFilter) (int, error)
+	FindAllByTags(ctx context.Context, tagNames []string) ([]*Event, error)
+	RegisterAttendance(ctx context.Context, userID, eventID string) error
+	IsUserAttending(ctx context.Context, userID string, eventID string) bool
+	GetAttendees(ctx context.Context, eventID string) ([]string, error)
+	GetAttendeesCount(ctx context.Context, eventID string) (int, error)
+	// GetSeatsTaken counts the seats taken towards the capacity, including
+	// those of the fullest upcoming occurrence of a series.
+	GetSeatsTaken(ctx context.Context, eventID string) (int, error)
+	GetAttendeeProfiles(ctx context.Context, eventID string, pagination *Pagination) ([]Attendee, error)
+	CountAttendeeProfiles(ctx context.Context, eventID string) (int, error)
+	RemoveAttendance(ctx context.Context, userID, eventID string) error
+	JoinWaitlist(ctx context.Context, userID, eventID string) (int, error)
+	LeaveWaitlist(ctx context.Context, userID, eventID string) error
+	GetWaitlist(ctx context.Context, eventID string) ([]WaitlistEntry, error)
+	GetWaitlistPosition(ctx context.Context, userID, eventID string) (int, error)
+	FindByOrganizer(ctx context.Context, userID string, pagination *Pagination) ([]*Event, error)
+	CountByOrganizer(ctx context.Context, userID string) (int, error)
+	FindAttendingEvents(ctx context.Context, userID string, pagination *Pagination) ([]*Event, error)
+	CountAttendingEvents(ctx context.Context, userID string) (int, error)
+	UpdateStatus(ctx context.Context, eventID string, status Status) error
+	FindOccurrenceExceptions(ctx context.Context, eventID string) ([]OccurrenceException, error)
+	SaveOccurrenceException(ctx context.Context, ex *OccurrenceException) error
+	RegisterOccurrence(ctx context.Context, userID, eventID string, recurrenceID time.Time) error
+	RemoveOccurrenceAttendance(ctx context.Context, userID, eventID string, recurrenceID time.Time) error
+	// Delete soft-deletes the event; Restore undoes it until the event is
+	// purged.
+	Delete(ctx context.Context, eventID string) error
+	FindDeletedByID(ctx context.Context, eventID string) (*Event, error)
+	Restore(ctx context.Context, eventID string) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
 }
@@ -0,0 +1,150 @@
+package event
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newMeetup() *Event {
+	startsAt := time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC)
+	return &Event{
+		Name:     "Meetup",
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(2 * time.Hour),
+		Timezone: "Europe/Warsaw",
+		Status:   StatusPublished,
+	}
+}
+
+func TestEvent_Validate_NoCapacity(t *testing.T) {
+	e := newMeetup()
+	require.NoError(t, e.Validate())
+}
+
+func TestEvent_Validate_PositiveCapacity(t *testing.T) {
+	capacity := 1
+	e := newMeetup()
+	e.Capacity = &capacity
+	require.NoError(t, e.Validate())
+}
+
+func TestEvent_Validate_ZeroCapacity(t *testing.T) {
+	capacity := 0
+	e := newMeetup()
+	e.Capacity = &capacity
+	require.Equal(t, ErrInvalidCapacity, e.Validate())
+}
+
+func TestEvent_Validate_InvalidCoordinates(t *testing.T) {
+	e := newMeetup()
+	e.Latitude = 90.5
+	require.Equal(t, ErrInvalidLatitude, e.Validate())
+
+	e = newMeetup()
+	e.Longitude = -180.1
+	require.Equal(t, ErrInvalidLongitude, e.Validate())
+}
+
+func TestEvent_Validate_TimeRange(t *testing.T) {
+	e := newMeetup()
+	e.EndsAt = e.StartsAt
+	require.Equal(t, ErrInvalidTimeRange, e.Validate())
+
+	e.EndsAt = e.StartsAt.Add(-time.Minute)
+	require.Equal(t, ErrInvalidTimeRange, e.Validate())
+
+	e.EndsAt = e.StartsAt.Add(72 * time.Hour)
+	require.NoError(t, e.Validate())
+}
+
+func TestEvent_Validate_Timezone(t *testing.T) {
+	for _, tz := range []string{"", "Local", "Mars/Olympus_Mons", "+02:00"} {
+		e := newMeetup()
+		e.Timezone = tz
+		require.Equal(t, ErrInvalidTimezone, e.Validate(), tz)
+	}
+
+	e := newMeetup()
+	e.Timezone = "UTC"
+	require.NoError(t, e.Validate())
+}
+
+func TestEventFilter_Validate_RadiusWithoutLocation(t *testing.T) {
+	radius := 5.0
+	f := &EventFilter{RadiusKm: &radius}
+	require.Equal(t, ErrLocationRequired, f.Validate())
+
+	f = &EventFilter{Sort: SortDistance}
+	require.Equal(t, ErrLocationRequired, f.Validate())
+}
+
+func TestEventFilter_Validate_Radius(t *testing.T) {
+	radius := 0.0
+	f := &EventFilter{Near: &GeoPoint{Latitude: 52.23, Longitude: 21.01}, RadiusKm: &radius}
+	require.Equal(t, ErrInvalidRadius, f.Validate())
+
+	radius = 10
+	require.NoError(t, f.Validate())
+}
+
+func TestEventFilter_Validate_QueryTooLong(t *testing.T) {
+	f := &EventFilter{Query: strings.Repeat("a", MaxQueryLength+1)}
+	require.Equal(t, ErrQueryTooLong, f.Validate())
+
+	f.Query = strings.Repeat("a", MaxQueryLength)
+	require.NoError(t, f.Validate())
+}
+
+func TestEvent_RemainingSeats_Unlimited(t *testing.T) {
+	e := &Event{}
+	require.Nil(t, e.RemainingSeats(42))
+}
+
+func TestEvent_RemainingSeats(t *testing.T) {
+	capacity := 10
+	e := &Event{Capacity: &capacity}
+	require.Equal(t, 7, *e.RemainingSeats(3))
+}
+
+func TestEvent_RemainingSeats_NeverNegative(t *testing.T) {
+	capacity := 2
+	e := &Event{Capacity: &capacity}
+	require.Equal(t, 0, *e.RemainingSeats(5))
+}
+
+func TestEventUpdate_Apply_OnlyGivenFields(t *testing.T) {
+	name := "New"
+	e := &Event{Name: "Old", Description: "Desc", Tags: []string{"Music"}}
+	(&EventUpdate{Name: &name}).Apply(e)
+	require.Equal(t, "New", e.Name)
+	require.Equal(t, "Desc", e.Description)
+	require.Equal(t, []string{"Music"}, e.Tags)
+}
+
+func TestEventUpdate_Apply_ClearCapacity(t *testing.T) {
+	capacity := 10
+	e := &Event{Capacity: &capacity}
+	(&EventUpdate{}).Apply(e)
+	require.Equal(t, 10, *e.Capacity)
+
+	(&EventUpdate{ClearCapacity: true}).Apply(e)
+	require.Nil(t, e.Capacity)
+}
+
+func TestNewPage(t *testing.T) {
+	page := NewPage([]int{1, 2}, &Pagination{Page: 1, PageSize: 2}, 5)
+	require.Equal(t, &Page[int]{Items: []int{1, 2}, Page: 1, PageSize: 2, Total: 5, HasNext: true}, page)
+
+	page = NewPage([]int{5}, &Pagination{Page: 3, PageSize: 2}, 5)
+	require.False(t, page.HasNext)
+}
+
+func TestNewPage_Unpaginated(t *testing.T) {
+	page := NewPage[int](nil, nil, 0)
+	require.NotNil(t, page.Items)
+	require.Equal(t, 1, page.Page)
+	require.False(t, page.HasNext)
+}
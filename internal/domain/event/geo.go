@@ -0,0 +1,67 @@
+package event
+
+import "math"
+
+// EarthRadiusKm is the mean Earth radius used for distance calculations.
+const EarthRadiusKm = 6371.0
+
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func (p GeoPoint) Validate() error {
+	if math.IsNaN(p.Latitude) || p.Latitude < -90 || p.Latitude > 90 {
+		return ErrInvalidLatitude
+	}
+	if math.IsNaN(p.Longitude) || p.Longitude < -180 || p.Longitude > 180 {
+		return ErrInvalidLongitude
+	}
+	return nil
+}
+
+// BoundingBox is a latitude/longitude rectangle in degrees.
+type BoundingBox struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// BoundingBox returns a rectangle containing every point within radiusKm of
+// p. It is meant as a cheap, index-friendly prefilter before the exact
+// distance check. When the circle reaches a pole or crosses the antimeridian
+// the box spans every longitude.
+func (p GeoPoint) BoundingBox(radiusKm float64) BoundingBox {
+	angular := radiusKm / EarthRadiusKm
+	dLat := angular * 180 / math.Pi
+
+	box := BoundingBox{
+		MinLat: p.Latitude - dLat,
+		MaxLat: p.Latitude + dLat,
+		MinLng: -180,
+		MaxLng: 180,
+	}
+	if box.MinLat <= -90 || box.MaxLat >= 90 || angular >= math.Pi/2 {
+		box.MinLat = max(box.MinLat, -90)
+		box.MaxLat = min(box.MaxLat, 90)
+		return box
+	}
+
+	dLng := math.Asin(math.Sin(angular)/math.Cos(p.Latitude*math.Pi/180)) * 180 / math.Pi
+	if p.Longitude-dLng >= -180 && p.Longitude+dLng <= 180 {
+		box.MinLng = p.Longitude - dLng
+		box.MaxLng = p.Longitude + dLng
+	}
+	return box
+}
+
+// DistanceKm returns the great-circle distance between p and q using the
+// haversine formula.
+func (p GeoPoint) DistanceKm(q GeoPoint) float64 {
+	lat1 := p.Latitude * math.Pi / 180
+	lat2 := q.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (q.Longitude - p.Longitude) * math.Pi / 180
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(min(h, 1)))
+}
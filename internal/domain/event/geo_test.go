@@ -0,0 +1,51 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	warsaw = GeoPoint{Latitude: 52.2297, Longitude: 21.0122}
+	krakow = GeoPoint{Latitude: 50.0647, Longitude: 19.9450}
+)
+
+func TestGeoPoint_Validate(t *testing.T) {
+	require.NoError(t, GeoPoint{Latitude: -90, Longitude: 180}.Validate())
+	require.Equal(t, ErrInvalidLatitude, GeoPoint{Latitude: -90.01}.Validate())
+	require.Equal(t, ErrInvalidLongitude, GeoPoint{Longitude: 180.01}.Validate())
+}
+
+func TestGeoPoint_DistanceKm(t *testing.T) {
+	require.InDelta(t, 252, warsaw.DistanceKm(krakow), 2)
+	require.InDelta(t, 0, warsaw.DistanceKm(warsaw), 1e-9)
+}
+
+func TestGeoPoint_BoundingBox_ContainsCircle(t *testing.T) {
+	box := warsaw.BoundingBox(300)
+
+	require.Less(t, box.MinLat, krakow.Latitude)
+	require.Greater(t, box.MaxLat, warsaw.Latitude)
+	require.Less(t, box.MinLng, krakow.Longitude)
+	require.Greater(t, box.MaxLng, warsaw.Longitude)
+
+	// the point due east at exactly the radius must still be inside
+	east := GeoPoint{Latitude: warsaw.Latitude, Longitude: box.MaxLng}
+	require.GreaterOrEqual(t, warsaw.DistanceKm(east), 299.0)
+}
+
+func TestGeoPoint_BoundingBox_Antimeridian(t *testing.T) {
+	box := GeoPoint{Latitude: 0, Longitude: 179.9}.BoundingBox(50)
+
+	require.Equal(t, -180.0, box.MinLng)
+	require.Equal(t, 180.0, box.MaxLng)
+}
+
+func TestGeoPoint_BoundingBox_Pole(t *testing.T) {
+	box := GeoPoint{Latitude: 89.9, Longitude: 0}.BoundingBox(50)
+
+	require.Equal(t, 90.0, box.MaxLat)
+	require.Equal(t, -180.0, box.MinLng)
+	require.Equal(t, 180.0, box.MaxLng)
+}
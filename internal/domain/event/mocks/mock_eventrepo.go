@@ -10,7 +10,9 @@
 package mock_event
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	event "github.com/kapiw04/convenly/internal/domain/event"
 	gomock "go.uber.org/mock/gomock"
@@ -40,192 +42,470 @@ func (m *MockEventRepo) EXPECT() *MockEventRepoMockRecorder {
 	return m.recorder
 }
 
+// CountAttendeeProfiles mocks base method.
+func (m *MockEventRepo) CountAttendeeProfiles(ctx context.Context, eventID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttendeeProfiles", ctx, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAttendeeProfiles indicates an expected call of CountAttendeeProfiles.
+func (mr *MockEventRepoMockRecorder) CountAttendeeProfiles(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttendeeProfiles", reflect.TypeOf((*MockEventRepo)(nil).CountAttendeeProfiles), ctx, eventID)
+}
+
+// CountAttendingEvents mocks base method.
+func (m *MockEventRepo) CountAttendingEvents(ctx context.Context, userID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttendingEvents", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAttendingEvents indicates an expected call of CountAttendingEvents.
+func (mr *MockEventRepoMockRecorder) CountAttendingEvents(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttendingEvents", reflect.TypeOf((*MockEventRepo)(nil).CountAttendingEvents), ctx, userID)
+}
+
+// CountByOrganizer mocks base method.
+func (m *MockEventRepo) CountByOrganizer(ctx context.Context, userID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByOrganizer", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByOrganizer indicates an expected call of CountByOrganizer.
+func (mr *MockEventRepoMockRecorder) CountByOrganizer(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByOrganizer", reflect.TypeOf((*MockEventRepo)(nil).CountByOrganizer), ctx, userID)
+}
+
+// CountWithFilters mocks base method.
+func (m *MockEventRepo) CountWithFilters(ctx context.Context, filter *event.EventFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithFilters", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithFilters indicates an expected call of CountWithFilters.
+func (mr *MockEventRepoMockRecorder) CountWithFilters(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithFilters", reflect.TypeOf((*MockEventRepo)(nil).CountWithFilters), ctx, filter)
+}
+
 // Delete mocks base method.
-func (m *MockEventRepo) Delete(eventID string) error {
+func (m *MockEventRepo) Delete(ctx context.Context, eventID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", eventID)
+	ret := m.ctrl.Call(m, "Delete", ctx, eventID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockEventRepoMockRecorder) Delete(eventID any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) Delete(ctx, eventID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEventRepo)(nil).Delete), eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEventRepo)(nil).Delete), ctx, eventID)
 }
 
 // FindAll mocks base method.
-func (m *MockEventRepo) FindAll() ([]*event.Event, error) {
+func (m *MockEventRepo) FindAll(ctx context.Context) ([]*event.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll")
+	ret := m.ctrl.Call(m, "FindAll", ctx)
 	ret0, _ := ret[0].([]*event.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockEventRepoMockRecorder) FindAll() *gomock.Call {
+func (mr *MockEventRepoMockRecorder) FindAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockEventRepo)(nil).FindAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockEventRepo)(nil).FindAll), ctx)
 }
 
 // FindAllByTags mocks base method.
-func (m *MockEventRepo) FindAllByTags(tagNames []string) ([]*event.Event, error) {
+func (m *MockEventRepo) FindAllByTags(ctx context.Context, tagNames []string) ([]*event.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllByTags", tagNames)
+	ret := m.ctrl.Call(m, "FindAllByTags", ctx, tagNames)
 	ret0, _ := ret[0].([]*event.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAllByTags indicates an expected call of FindAllByTags.
-func (mr *MockEventRepoMockRecorder) FindAllByTags(tagNames any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) FindAllByTags(ctx, tagNames any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByTags", reflect.TypeOf((*MockEventRepo)(nil).FindAllByTags), tagNames)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByTags", reflect.TypeOf((*MockEventRepo)(nil).FindAllByTags), ctx, tagNames)
 }
 
 // FindAllWithFilters mocks base method.
-func (m *MockEventRepo) FindAllWithFilters(filter *event.EventFilter) ([]*event.Event, error) {
+func (m *MockEventRepo) FindAllWithFilters(ctx context.Context, filter *event.EventFilter) ([]*event.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllWithFilters", filter)
+	ret := m.ctrl.Call(m, "FindAllWithFilters", ctx, filter)
 	ret0, _ := ret[0].([]*event.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAllWithFilters indicates an expected call of FindAllWithFilters.
-func (mr *MockEventRepoMockRecorder) FindAllWithFilters(filter any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) FindAllWithFilters(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllWithFilters", reflect.TypeOf((*MockEventRepo)(nil).FindAllWithFilters), filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllWithFilters", reflect.TypeOf((*MockEventRepo)(nil).FindAllWithFilters), ctx, filter)
 }
 
 // FindAttendingEvents mocks base method.
-func (m *MockEventRepo) FindAttendingEvents(userID string, pagination *event.Pagination) ([]*event.Event, error) {
+func (m *MockEventRepo) FindAttendingEvents(ctx context.Context, userID string, pagination *event.Pagination) ([]*event.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAttendingEvents", userID, pagination)
+	ret := m.ctrl.Call(m, "FindAttendingEvents", ctx, userID, pagination)
 	ret0, _ := ret[0].([]*event.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAttendingEvents indicates an expected call of FindAttendingEvents.
-func (mr *MockEventRepoMockRecorder) FindAttendingEvents(userID, pagination any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) FindAttendingEvents(ctx, userID, pagination any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAttendingEvents", reflect.TypeOf((*MockEventRepo)(nil).FindAttendingEvents), userID, pagination)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAttendingEvents", reflect.TypeOf((*MockEventRepo)(nil).FindAttendingEvents), ctx, userID, pagination)
 }
 
 // FindByID mocks base method.
-func (m *MockEventRepo) FindByID(arg0 string) (*event.Event, error) {
+func (m *MockEventRepo) FindByID(ctx context.Context, eventID string) (*event.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret := m.ctrl.Call(m, "FindByID", ctx, eventID)
 	ret0, _ := ret[0].(*event.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID.
-func (mr *MockEventRepoMockRecorder) FindByID(arg0 any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) FindByID(ctx, eventID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockEventRepo)(nil).FindByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockEventRepo)(nil).FindByID), ctx, eventID)
 }
 
 // FindByOrganizer mocks base method.
-func (m *MockEventRepo) FindByOrganizer(userID string, pagination *event.Pagination) ([]*event.Event, error) {
+func (m *MockEventRepo) FindByOrganizer(ctx context.Context, userID string, pagination *event.Pagination) ([]*event.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByOrganizer", userID, pagination)
+	ret := m.ctrl.Call(m, "FindByOrganizer", ctx, userID, pagination)
 	ret0, _ := ret[0].([]*event.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByOrganizer indicates an expected call of FindByOrganizer.
-func (mr *MockEventRepoMockRecorder) FindByOrganizer(userID, pagination any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) FindByOrganizer(ctx, userID, pagination any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOrganizer", reflect.TypeOf((*MockEventRepo)(nil).FindByOrganizer), userID, pagination)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOrganizer", reflect.TypeOf((*MockEventRepo)(nil).FindByOrganizer), ctx, userID, pagination)
+}
+
+// FindDeletedByID mocks base method.
+func (m *MockEventRepo) FindDeletedByID(ctx context.Context, eventID string) (*event.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, eventID)
+	ret0, _ := ret[0].(*event.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockEventRepoMockRecorder) FindDeletedByID(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockEventRepo)(nil).FindDeletedByID), ctx, eventID)
+}
+
+// FindOccurrenceExceptions mocks base method.
+func (m *MockEventRepo) FindOccurrenceExceptions(ctx context.Context, eventID string) ([]event.OccurrenceException, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOccurrenceExceptions", ctx, eventID)
+	ret0, _ := ret[0].([]event.OccurrenceException)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOccurrenceExceptions indicates an expected call of FindOccurrenceExceptions.
+func (mr *MockEventRepoMockRecorder) FindOccurrenceExceptions(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOccurrenceExceptions", reflect.TypeOf((*MockEventRepo)(nil).FindOccurrenceExceptions), ctx, eventID)
+}
+
+// GetAttendeeProfiles mocks base method.
+func (m *MockEventRepo) GetAttendeeProfiles(ctx context.Context, eventID string, pagination *event.Pagination) ([]event.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttendeeProfiles", ctx, eventID, pagination)
+	ret0, _ := ret[0].([]event.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttendeeProfiles indicates an expected call of GetAttendeeProfiles.
+func (mr *MockEventRepoMockRecorder) GetAttendeeProfiles(ctx, eventID, pagination any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendeeProfiles", reflect.TypeOf((*MockEventRepo)(nil).GetAttendeeProfiles), ctx, eventID, pagination)
 }
 
 // GetAttendees mocks base method.
-func (m *MockEventRepo) GetAttendees(eventID string) ([]string, error) {
+func (m *MockEventRepo) GetAttendees(ctx context.Context, eventID string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAttendees", eventID)
+	ret := m.ctrl.Call(m, "GetAttendees", ctx, eventID)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAttendees indicates an expected call of GetAttendees.
-func (mr *MockEventRepoMockRecorder) GetAttendees(eventID any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) GetAttendees(ctx, eventID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendees", reflect.TypeOf((*MockEventRepo)(nil).GetAttendees), eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendees", reflect.TypeOf((*MockEventRepo)(nil).GetAttendees), ctx, eventID)
 }
 
 // GetAttendeesCount mocks base method.
-func (m *MockEventRepo) GetAttendeesCount(eventID string) (int, error) {
+func (m *MockEventRepo) GetAttendeesCount(ctx context.Context, eventID string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAttendeesCount", eventID)
+	ret := m.ctrl.Call(m, "GetAttendeesCount", ctx, eventID)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAttendeesCount indicates an expected call of GetAttendeesCount.
-func (mr *MockEventRepoMockRecorder) GetAttendeesCount(eventID any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) GetAttendeesCount(ctx, eventID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendeesCount", reflect.TypeOf((*MockEventRepo)(nil).GetAttendeesCount), eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendeesCount", reflect.TypeOf((*MockEventRepo)(nil).GetAttendeesCount), ctx, eventID)
+}
+
+// GetSeatsTaken mocks base method.
+func (m *MockEventRepo) GetSeatsTaken(ctx context.Context, eventID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeatsTaken", ctx, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeatsTaken indicates an expected call of GetSeatsTaken.
+func (mr *MockEventRepoMockRecorder) GetSeatsTaken(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatsTaken", reflect.TypeOf((*MockEventRepo)(nil).GetSeatsTaken), ctx, eventID)
+}
+
+// GetWaitlist mocks base method.
+func (m *MockEventRepo) GetWaitlist(ctx context.Context, eventID string) ([]event.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitlist", ctx, eventID)
+	ret0, _ := ret[0].([]event.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitlist indicates an expected call of GetWaitlist.
+func (mr *MockEventRepoMockRecorder) GetWaitlist(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitlist", reflect.TypeOf((*MockEventRepo)(nil).GetWaitlist), ctx, eventID)
+}
+
+// GetWaitlistPosition mocks base method.
+func (m *MockEventRepo) GetWaitlistPosition(ctx context.Context, userID, eventID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitlistPosition", ctx, userID, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitlistPosition indicates an expected call of GetWaitlistPosition.
+func (mr *MockEventRepoMockRecorder) GetWaitlistPosition(ctx, userID, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitlistPosition", reflect.TypeOf((*MockEventRepo)(nil).GetWaitlistPosition), ctx, userID, eventID)
 }
 
 // IsUserAttending mocks base method.
-func (m *MockEventRepo) IsUserAttending(userID, eventID string) bool {
+func (m *MockEventRepo) IsUserAttending(ctx context.Context, userID, eventID string) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsUserAttending", userID, eventID)
+	ret := m.ctrl.Call(m, "IsUserAttending", ctx, userID, eventID)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // IsUserAttending indicates an expected call of IsUserAttending.
-func (mr *MockEventRepoMockRecorder) IsUserAttending(userID, eventID any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) IsUserAttending(ctx, userID, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUserAttending", reflect.TypeOf((*MockEventRepo)(nil).IsUserAttending), ctx, userID, eventID)
+}
+
+// JoinWaitlist mocks base method.
+func (m *MockEventRepo) JoinWaitlist(ctx context.Context, userID, eventID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinWaitlist", ctx, userID, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinWaitlist indicates an expected call of JoinWaitlist.
+func (mr *MockEventRepoMockRecorder) JoinWaitlist(ctx, userID, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinWaitlist", reflect.TypeOf((*MockEventRepo)(nil).JoinWaitlist), ctx, userID, eventID)
+}
+
+// LeaveWaitlist mocks base method.
+func (m *MockEventRepo) LeaveWaitlist(ctx context.Context, userID, eventID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveWaitlist", ctx, userID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaveWaitlist indicates an expected call of LeaveWaitlist.
+func (mr *MockEventRepoMockRecorder) LeaveWaitlist(ctx, userID, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveWaitlist", reflect.TypeOf((*MockEventRepo)(nil).LeaveWaitlist), ctx, userID, eventID)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockEventRepo) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockEventRepoMockRecorder) PurgeDeleted(ctx, before any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUserAttending", reflect.TypeOf((*MockEventRepo)(nil).IsUserAttending), userID, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockEventRepo)(nil).PurgeDeleted), ctx, before)
 }
 
 // RegisterAttendance mocks base method.
-func (m *MockEventRepo) RegisterAttendance(userID, eventID string) error {
+func (m *MockEventRepo) RegisterAttendance(ctx context.Context, userID, eventID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterAttendance", userID, eventID)
+	ret := m.ctrl.Call(m, "RegisterAttendance", ctx, userID, eventID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RegisterAttendance indicates an expected call of RegisterAttendance.
-func (mr *MockEventRepoMockRecorder) RegisterAttendance(userID, eventID any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) RegisterAttendance(ctx, userID, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAttendance", reflect.TypeOf((*MockEventRepo)(nil).RegisterAttendance), ctx, userID, eventID)
+}
+
+// RegisterOccurrence mocks base method.
+func (m *MockEventRepo) RegisterOccurrence(ctx context.Context, userID, eventID string, recurrenceID time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterOccurrence", ctx, userID, eventID, recurrenceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterOccurrence indicates an expected call of RegisterOccurrence.
+func (mr *MockEventRepoMockRecorder) RegisterOccurrence(ctx, userID, eventID, recurrenceID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAttendance", reflect.TypeOf((*MockEventRepo)(nil).RegisterAttendance), userID, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOccurrence", reflect.TypeOf((*MockEventRepo)(nil).RegisterOccurrence), ctx, userID, eventID, recurrenceID)
 }
 
 // RemoveAttendance mocks base method.
-func (m *MockEventRepo) RemoveAttendance(userID, eventID string) error {
+func (m *MockEventRepo) RemoveAttendance(ctx context.Context, userID, eventID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveAttendance", userID, eventID)
+	ret := m.ctrl.Call(m, "RemoveAttendance", ctx, userID, eventID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveAttendance indicates an expected call of RemoveAttendance.
-func (mr *MockEventRepoMockRecorder) RemoveAttendance(userID, eventID any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) RemoveAttendance(ctx, userID, eventID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAttendance", reflect.TypeOf((*MockEventRepo)(nil).RemoveAttendance), userID, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAttendance", reflect.TypeOf((*MockEventRepo)(nil).RemoveAttendance), ctx, userID, eventID)
+}
+
+// RemoveOccurrenceAttendance mocks base method.
+func (m *MockEventRepo) RemoveOccurrenceAttendance(ctx context.Context, userID, eventID string, recurrenceID time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveOccurrenceAttendance", ctx, userID, eventID, recurrenceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveOccurrenceAttendance indicates an expected call of RemoveOccurrenceAttendance.
+func (mr *MockEventRepoMockRecorder) RemoveOccurrenceAttendance(ctx, userID, eventID, recurrenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveOccurrenceAttendance", reflect.TypeOf((*MockEventRepo)(nil).RemoveOccurrenceAttendance), ctx, userID, eventID, recurrenceID)
+}
+
+// Restore mocks base method.
+func (m *MockEventRepo) Restore(ctx context.Context, eventID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEventRepoMockRecorder) Restore(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEventRepo)(nil).Restore), ctx, eventID)
 }
 
 // Save mocks base method.
-func (m *MockEventRepo) Save(arg0 *event.Event) error {
+func (m *MockEventRepo) Save(ctx context.Context, e *event.Event) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", arg0)
+	ret := m.ctrl.Call(m, "Save", ctx, e)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockEventRepoMockRecorder) Save(arg0 any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) Save(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockEventRepo)(nil).Save), ctx, e)
+}
+
+// SaveOccurrenceException mocks base method.
+func (m *MockEventRepo) SaveOccurrenceException(ctx context.Context, ex *event.OccurrenceException) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOccurrenceException", ctx, ex)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOccurrenceException indicates an expected call of SaveOccurrenceException.
+func (mr *MockEventRepoMockRecorder) SaveOccurrenceException(ctx, ex any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOccurrenceException", reflect.TypeOf((*MockEventRepo)(nil).SaveOccurrenceException), ctx, ex)
+}
+
+// Update mocks base method.
+func (m *MockEventRepo) Update(ctx context.Context, e *event.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockEventRepoMockRecorder) Update(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEventRepo)(nil).Update), ctx, e)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockEventRepo) UpdateStatus(ctx context.Context, eventID string, status event.Status) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, eventID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockEventRepoMockRecorder) UpdateStatus(ctx, eventID, status any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockEventRepo)(nil).Save), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockEventRepo)(nil).UpdateStatus), ctx, eventID, status)
 }
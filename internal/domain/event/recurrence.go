@@ -0,0 +1,462 @@
+package event
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is how often a recurring event repeats.
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+)
+
+// MaxOccurrences bounds the COUNT of a rule and the number of occurrences of
+// one series expanded for a single listing.
+const MaxOccurrences = 1000
+
+// MaxExpansionWindow is how far past its start a listing window without an
+// end expands recurring events.
+const MaxExpansionWindow = 366 * 24 * time.Hour
+
+// MaxRecurrenceHorizon is how far past its start a series generates
+// occurrences. Finding an occurrence walks the rule from the start, so this
+// also bounds the work done for a recurrence id taken from a request.
+const MaxRecurrenceHorizon = 10 * 366 * 24 * time.Hour
+
+// maxIdlePeriods stops the expansion of a rule that has not generated an
+// occurrence for that many periods in a row.
+const maxIdlePeriods = 1000
+
+// untilFormat is the RFC 5545 UTC date-time form used by UNTIL.
+const untilFormat = "20060102T150405Z"
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// WeekdayNum is a BYDAY entry. N is the position of the weekday within the
+// month for monthly rules (1 for the first, -1 for the last) and 0 for every
+// such weekday.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+func (w WeekdayNum) String() string {
+	code := strings.ToUpper(w.Weekday.String()[:2])
+	if w.N == 0 {
+		return code
+	}
+	return strconv.Itoa(w.N) + code
+}
+
+// RecurrenceRule is the supported subset of an RFC 5545 RRULE: DAILY, WEEKLY
+// or MONTHLY repetition with INTERVAL, COUNT, UNTIL and BYDAY. Weeks start on
+// Monday.
+type RecurrenceRule struct {
+	Freq     Frequency
+	Interval int
+	// Count and Until end the series; at most one of them is set.
+	Count int
+	Until time.Time
+	ByDay []WeekdayNum
+}
+
+// ParseRecurrenceRule parses an RRULE value such as
+// "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=10", with or without the "RRULE:" prefix.
+func ParseRecurrenceRule(s string) (*RecurrenceRule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, ErrInvalidRecurrence
+	}
+
+	r := &RecurrenceRule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+		if !ok || value == "" || seen[key] {
+			return nil, ErrInvalidRecurrence
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(value))
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count < 1 {
+				return nil, ErrInvalidRecurrence
+			}
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		default:
+			return nil, ErrInvalidRecurrence
+		}
+		if err != nil {
+			return nil, ErrInvalidRecurrence
+		}
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func parseUntil(s string) (time.Time, error) {
+	if t, err := time.Parse(untilFormat, s); err == nil {
+		return t, nil
+	}
+	// a plain date includes the whole day
+	t, err := time.Parse("20060102", s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+func parseByDay(s string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(strings.ToUpper(s), ",") {
+		if len(item) < 2 {
+			return nil, ErrInvalidRecurrence
+		}
+		weekday, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, ErrInvalidRecurrence
+		}
+		day := WeekdayNum{Weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, ErrInvalidRecurrence
+			}
+			day.N = n
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func (r *RecurrenceRule) Validate() error {
+	switch r.Freq {
+	case FreqDaily, FreqWeekly, FreqMonthly:
+	default:
+		return ErrInvalidRecurrence
+	}
+	if r.Interval < 1 || r.Count < 0 || r.Count > MaxOccurrences {
+		return ErrInvalidRecurrence
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return ErrInvalidRecurrence
+	}
+	for _, day := range r.ByDay {
+		// only monthly rules can pick the nth weekday of a period
+		if day.N != 0 && r.Freq != FreqMonthly {
+			return ErrInvalidRecurrence
+		}
+	}
+	return nil
+}
+
+// String returns the rule in RRULE form, without the "RRULE:" prefix.
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilFormat))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// each calls yield with the start of every occurrence of a series beginning
+// at dtstart, in order, until yield returns false or the series ends. Dates
+// are computed in loc so occurrences keep their local time of day across
+// daylight saving changes.
+func (r *RecurrenceRule) each(dtstart time.Time, loc *time.Location, yield func(time.Time) bool) {
+	local := dtstart.In(loc)
+	horizon := dtstart.Add(MaxRecurrenceHorizon)
+	emitted, idle := 0, 0
+	for period := 0; idle < maxIdlePeriods; period++ {
+		idle++
+		for _, day := range r.periodDays(local, period*r.Interval) {
+			t := time.Date(day.Year(), day.Month(), day.Day(),
+				local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), loc)
+			if t.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) || t.After(horizon) {
+				return
+			}
+			if !yield(t) {
+				return
+			}
+			emitted, idle = emitted+1, 0
+			if r.Count > 0 && emitted == r.Count {
+				return
+			}
+		}
+	}
+}
+
+// periodDays returns the candidate days, at midnight UTC, of the period
+// offset periods after the one containing start.
+func (r *RecurrenceRule) periodDays(start time.Time, offset int) []time.Time {
+	y, m, d := start.Date()
+	switch r.Freq {
+	case FreqDaily:
+		day := time.Date(y, m, d+offset, 0, 0, 0, 0, time.UTC)
+		if len(r.ByDay) > 0 && !r.hasWeekday(day.Weekday()) {
+			return nil
+		}
+		return []time.Time{day}
+
+	case FreqWeekly:
+		sinceMonday := (int(start.Weekday()) + 6) % 7
+		monday := time.Date(y, m, d-sinceMonday+7*offset, 0, 0, 0, 0, time.UTC)
+		if len(r.ByDay) == 0 {
+			return []time.Time{monday.AddDate(0, 0, sinceMonday)}
+		}
+		var days []time.Time
+		for i := range 7 {
+			if day := monday.AddDate(0, 0, i); r.hasWeekday(day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+		return days
+
+	default: // FreqMonthly
+		first := time.Date(y, m+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		length := first.AddDate(0, 1, -1).Day()
+		if len(r.ByDay) == 0 {
+			// months without that day are skipped, as in RFC 5545
+			if d > length {
+				return nil
+			}
+			return []time.Time{first.AddDate(0, 0, d-1)}
+		}
+		var days []time.Time
+		for i := range length {
+			day := first.AddDate(0, 0, i)
+			if r.matchesMonthDay(day, length) {
+				days = append(days, day)
+			}
+		}
+		return days
+	}
+}
+
+func (r *RecurrenceRule) hasWeekday(w time.Weekday) bool {
+	return slices.ContainsFunc(r.ByDay, func(day WeekdayNum) bool { return day.Weekday == w })
+}
+
+func (r *RecurrenceRule) matchesMonthDay(day time.Time, length int) bool {
+	nth := (day.Day()-1)/7 + 1
+	nthFromEnd := -((length-day.Day())/7 + 1)
+	for _, byDay := range r.ByDay {
+		if byDay.Weekday == day.Weekday() && (byDay.N == 0 || byDay.N == nth || byDay.N == nthFromEnd) {
+			return true
+		}
+	}
+	return false
+}
+
+// Occurrence is a single instance of a recurring event. RecurrenceID is the
+// start the rule generated it at; it keeps identifying the occurrence after
+// it is moved.
+type Occurrence struct {
+	RecurrenceID time.Time `json:"recurrence_id"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+}
+
+// OccurrenceException cancels or reschedules one occurrence of a series
+// without touching the others.
+type OccurrenceException struct {
+	EventID      string
+	RecurrenceID time.Time
+	Cancelled    bool
+	StartsAt     *time.Time
+	EndsAt       *time.Time
+}
+
+// ParseRecurrenceID parses the RFC 3339 start identifying an occurrence.
+func ParseRecurrenceID(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, ErrOccurrenceNotFound
+	}
+	return t, nil
+}
+
+func (e *Event) IsRecurring() bool {
+	return e.Recurrence != ""
+}
+
+// Rule returns the parsed recurrence rule of the event.
+func (e *Event) Rule() (*RecurrenceRule, error) {
+	if !e.IsRecurring() {
+		return nil, ErrNotRecurring
+	}
+	return ParseRecurrenceRule(e.Recurrence)
+}
+
+func (e *Event) validateRecurrence() error {
+	rule, err := e.Rule()
+	if err != nil {
+		return err
+	}
+	loc, err := LoadTimezone(e.Timezone)
+	if err != nil {
+		return err
+	}
+	// The start of the event is the first occurrence, so the rule has to
+	// generate it.
+	first := false
+	rule.each(e.StartsAt, loc, func(t time.Time) bool {
+		first = t.Equal(e.StartsAt)
+		return false
+	})
+	if !first {
+		return ErrRecurrenceStart
+	}
+	return nil
+}
+
+// Occurrences expands the series into the occurrences overlapping the window
+// from..to, after applying the exceptions. At most MaxOccurrences are
+// returned.
+func (e *Event) Occurrences(from, to time.Time, exceptions []OccurrenceException) ([]Occurrence, error) {
+	rule, err := e.Rule()
+	if err != nil {
+		return nil, err
+	}
+	loc, err := LoadTimezone(e.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[time.Time]OccurrenceException, len(exceptions))
+	for _, ex := range exceptions {
+		overrides[ex.RecurrenceID.UTC()] = ex
+	}
+
+	duration := e.EndsAt.Sub(e.StartsAt)
+	var occurrences []Occurrence
+	add := func(o Occurrence) {
+		if o.EndsAt.After(from) && !o.StartsAt.After(to) {
+			occurrences = append(occurrences, o)
+		}
+	}
+
+	rule.each(e.StartsAt, loc, func(t time.Time) bool {
+		if t.After(to) || len(occurrences) >= MaxOccurrences {
+			return false
+		}
+		o := Occurrence{RecurrenceID: t, StartsAt: t, EndsAt: t.Add(duration)}
+		if ex, ok := overrides[t.UTC()]; ok {
+			delete(overrides, t.UTC())
+			if ex.Cancelled {
+				return true
+			}
+			o = ex.apply(o)
+		}
+		add(o)
+		return true
+	})
+
+	// occurrences moved into the window from outside of it
+	for _, ex := range overrides {
+		if ex.Cancelled || ex.RecurrenceID.After(to) || len(occurrences) >= MaxOccurrences {
+			continue
+		}
+		if _, err := e.Occurrence(ex.RecurrenceID, nil); err != nil {
+			continue
+		}
+		add(ex.apply(Occurrence{RecurrenceID: ex.RecurrenceID, StartsAt: ex.RecurrenceID, EndsAt: ex.RecurrenceID.Add(duration)}))
+	}
+
+	slices.SortFunc(occurrences, func(a, b Occurrence) int { return a.StartsAt.Compare(b.StartsAt) })
+	return occurrences, nil
+}
+
+// Occurrence returns the occurrence the rule generates at recurrenceID, with
+// the exceptions applied. It fails with ErrOccurrenceNotFound if the rule
+// has no such occurrence or it was cancelled.
+func (e *Event) Occurrence(recurrenceID time.Time, exceptions []OccurrenceException) (*Occurrence, error) {
+	rule, err := e.Rule()
+	if err != nil {
+		return nil, err
+	}
+	loc, err := LoadTimezone(e.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurrenceID.Before(e.StartsAt) || recurrenceID.After(e.StartsAt.Add(MaxRecurrenceHorizon)) {
+		return nil, ErrOccurrenceNotFound
+	}
+
+	found := false
+	rule.each(e.StartsAt, loc, func(t time.Time) bool {
+		found = t.Equal(recurrenceID)
+		return t.Before(recurrenceID)
+	})
+	if !found {
+		return nil, ErrOccurrenceNotFound
+	}
+
+	o := Occurrence{RecurrenceID: recurrenceID, StartsAt: recurrenceID, EndsAt: recurrenceID.Add(e.EndsAt.Sub(e.StartsAt))}
+	for _, ex := range exceptions {
+		if ex.RecurrenceID.Equal(recurrenceID) {
+			if ex.Cancelled {
+				return nil, ErrOccurrenceNotFound
+			}
+			o = ex.apply(o)
+		}
+	}
+	return &o, nil
+}
+
+func (ex OccurrenceException) apply(o Occurrence) Occurrence {
+	if ex.StartsAt != nil {
+		o.StartsAt = *ex.StartsAt
+	}
+	if ex.EndsAt != nil {
+		o.EndsAt = *ex.EndsAt
+	}
+	return o
+}
+
+// Validate checks a rescheduled occurrence still ends after it starts.
+func (o *Occurrence) Validate() error {
+	if !o.EndsAt.After(o.StartsAt) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
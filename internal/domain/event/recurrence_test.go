@@ -0,0 +1,218 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	r, err := ParseRecurrenceRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=10")
+	require.NoError(t, err)
+	require.Equal(t, FreqWeekly, r.Freq)
+	require.Equal(t, 2, r.Interval)
+	require.Equal(t, 10, r.Count)
+	require.Equal(t, []WeekdayNum{{Weekday: time.Tuesday}, {Weekday: time.Thursday}}, r.ByDay)
+	require.Equal(t, "FREQ=WEEKLY;INTERVAL=2;COUNT=10;BYDAY=TU,TH", r.String())
+
+	r, err = ParseRecurrenceRule("FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231")
+	require.NoError(t, err)
+	require.Equal(t, []WeekdayNum{{N: -1, Weekday: time.Friday}}, r.ByDay)
+	require.Equal(t, time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), r.Until)
+}
+
+func TestParseRecurrenceRule_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"FREQ=YEARLY",
+		"FREQ=HOURLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=1001",
+		"FREQ=DAILY;COUNT=5;UNTIL=20261231T000000Z",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYMONTHDAY=15",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;UNTIL=tomorrow",
+	} {
+		_, err := ParseRecurrenceRule(s)
+		require.Equal(t, ErrInvalidRecurrence, err, s)
+	}
+}
+
+func occurrenceStarts(t *testing.T, e *Event, from, to time.Time) []time.Time {
+	t.Helper()
+	occurrences, err := e.Occurrences(from, to, nil)
+	require.NoError(t, err)
+	starts := make([]time.Time, len(occurrences))
+	for i, o := range occurrences {
+		starts[i] = o.StartsAt.UTC()
+	}
+	return starts
+}
+
+func recurringEvent(rule string, startsAt time.Time, timezone string) *Event {
+	return &Event{
+		Name:       "Meetup",
+		StartsAt:   startsAt,
+		EndsAt:     startsAt.Add(2 * time.Hour),
+		Timezone:   timezone,
+		Recurrence: rule,
+		Status:     StatusPublished,
+	}
+}
+
+func TestOccurrences_WeeklyByDay(t *testing.T) {
+	// Monday 2025-03-03
+	e := recurringEvent("FREQ=WEEKLY;BYDAY=MO,TH;COUNT=5", time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC), "UTC")
+	require.NoError(t, e.Validate())
+
+	starts := occurrenceStarts(t, e, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, []time.Time{
+		time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 6, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 13, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 17, 18, 0, 0, 0, time.UTC),
+	}, starts)
+}
+
+func TestOccurrences_Window(t *testing.T) {
+	e := recurringEvent("FREQ=DAILY;INTERVAL=2", time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), "UTC")
+
+	// the occurrence on the 9th is still running at the start of the window
+	starts := occurrenceStarts(t, e, time.Date(2025, 3, 9, 11, 0, 0, 0, time.UTC), time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC))
+	require.Equal(t, []time.Time{
+		time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 11, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC),
+	}, starts)
+}
+
+func TestOccurrences_MonthlyNthWeekday(t *testing.T) {
+	// second Tuesday of every month, starting on 2025-01-14
+	e := recurringEvent("FREQ=MONTHLY;BYDAY=2TU;UNTIL=20250430", time.Date(2025, 1, 14, 18, 0, 0, 0, time.UTC), "UTC")
+	require.NoError(t, e.Validate())
+
+	starts := occurrenceStarts(t, e, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, []time.Time{
+		time.Date(2025, 1, 14, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 11, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 11, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 8, 18, 0, 0, 0, time.UTC),
+	}, starts)
+
+	last := recurringEvent("FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC), "UTC")
+	starts = occurrenceStarts(t, last, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, []time.Time{
+		time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 28, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 28, 18, 0, 0, 0, time.UTC),
+	}, starts)
+}
+
+func TestOccurrences_MonthlySkipsShortMonths(t *testing.T) {
+	e := recurringEvent("FREQ=MONTHLY;COUNT=3", time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC), "UTC")
+
+	starts := occurrenceStarts(t, e, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, []time.Time{
+		time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 5, 31, 18, 0, 0, 0, time.UTC),
+	}, starts)
+}
+
+func TestOccurrences_KeepLocalTimeAcrossDST(t *testing.T) {
+	// 18:00 in Warsaw is 17:00 UTC in winter and 16:00 UTC in summer
+	e := recurringEvent("FREQ=WEEKLY;COUNT=3", time.Date(2025, 3, 23, 17, 0, 0, 0, time.UTC), "Europe/Warsaw")
+	require.NoError(t, e.Validate())
+
+	starts := occurrenceStarts(t, e, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, []time.Time{
+		time.Date(2025, 3, 23, 17, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 30, 16, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 6, 16, 0, 0, 0, time.UTC),
+	}, starts)
+}
+
+func TestOccurrences_Exceptions(t *testing.T) {
+	e := recurringEvent("FREQ=WEEKLY;COUNT=4", time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC), "UTC")
+	moved := time.Date(2025, 3, 11, 19, 0, 0, 0, time.UTC)
+	movedEnd := moved.Add(90 * time.Minute)
+	exceptions := []OccurrenceException{
+		{RecurrenceID: time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC), StartsAt: &moved, EndsAt: &movedEnd},
+		{RecurrenceID: time.Date(2025, 3, 17, 18, 0, 0, 0, time.UTC), Cancelled: true},
+	}
+
+	occurrences, err := e.Occurrences(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), exceptions)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 3)
+	require.Equal(t, time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC), occurrences[1].RecurrenceID.UTC())
+	require.Equal(t, moved, occurrences[1].StartsAt)
+	require.Equal(t, movedEnd, occurrences[1].EndsAt)
+	require.Equal(t, time.Date(2025, 3, 24, 18, 0, 0, 0, time.UTC), occurrences[2].StartsAt.UTC())
+
+	// moved out of a window that only covers its original time
+	occurrences, err = e.Occurrences(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 23, 0, 0, 0, time.UTC), exceptions)
+	require.NoError(t, err)
+	require.Empty(t, occurrences)
+
+	// and into one that only covers its new time
+	occurrences, err = e.Occurrences(time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 11, 23, 0, 0, 0, time.UTC), exceptions)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 1)
+	require.Equal(t, moved, occurrences[0].StartsAt)
+}
+
+func TestEvent_Occurrence(t *testing.T) {
+	e := recurringEvent("FREQ=WEEKLY;COUNT=4", time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC), "UTC")
+	cancelled := []OccurrenceException{{RecurrenceID: time.Date(2025, 3, 17, 18, 0, 0, 0, time.UTC), Cancelled: true}}
+
+	o, err := e.Occurrence(time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC), cancelled)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC), o.EndsAt.UTC())
+
+	_, err = e.Occurrence(time.Date(2025, 3, 17, 18, 0, 0, 0, time.UTC), cancelled)
+	require.Equal(t, ErrOccurrenceNotFound, err)
+
+	_, err = e.Occurrence(time.Date(2025, 3, 11, 18, 0, 0, 0, time.UTC), nil)
+	require.Equal(t, ErrOccurrenceNotFound, err)
+
+	// past the COUNT
+	_, err = e.Occurrence(time.Date(2025, 3, 31, 18, 0, 0, 0, time.UTC), nil)
+	require.Equal(t, ErrOccurrenceNotFound, err)
+
+	single := newMeetup()
+	_, err = single.Occurrence(single.StartsAt, nil)
+	require.Equal(t, ErrNotRecurring, err)
+}
+
+func TestEvent_Occurrence_Horizon(t *testing.T) {
+	e := recurringEvent("FREQ=DAILY", time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC), "UTC")
+
+	_, err := e.Occurrence(time.Date(2034, 3, 3, 18, 0, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+
+	_, err = e.Occurrence(time.Date(9999, 3, 3, 18, 0, 0, 0, time.UTC), nil)
+	require.Equal(t, ErrOccurrenceNotFound, err)
+
+	occurrences, err := e.Occurrences(time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+	require.Empty(t, occurrences)
+}
+
+func TestEvent_Validate_Recurrence(t *testing.T) {
+	// 2025-03-03 is a Monday
+	e := recurringEvent("FREQ=WEEKLY;BYDAY=TU", time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC), "UTC")
+	require.Equal(t, ErrRecurrenceStart, e.Validate())
+
+	e.Recurrence = "FREQ=WEEKLY;BYDAY=MO,TU"
+	require.NoError(t, e.Validate())
+
+	e.Recurrence = "FREQ=FORTNIGHTLY"
+	require.Equal(t, ErrInvalidRecurrence, e.Validate())
+}
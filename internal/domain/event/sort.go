@@ -0,0 +1,35 @@
+package event
+
+// SortOrder is the ordering of an event listing.
+type SortOrder string
+
+const (
+	SortDateAsc    SortOrder = "date_asc"
+	SortDateDesc   SortOrder = "date_desc"
+	SortFeeAsc     SortOrder = "fee_asc"
+	SortFeeDesc    SortOrder = "fee_desc"
+	SortPopularity SortOrder = "popularity"
+	SortNewest     SortOrder = "newest"
+	SortDistance   SortOrder = "distance"
+)
+
+// SortOrders lists every accepted sort order.
+var SortOrders = []SortOrder{
+	SortDateAsc, SortDateDesc, SortFeeAsc, SortFeeDesc, SortPopularity, SortNewest, SortDistance,
+}
+
+func ParseSortOrder(s string) (SortOrder, error) {
+	for _, o := range SortOrders {
+		if string(o) == s {
+			return o, nil
+		}
+	}
+	return "", ErrInvalidSort
+}
+
+// SupportsKeyset reports whether a listing in this order can be paginated
+// with a (starts_at, event_id) cursor. The zero value sorts by start time
+// ascending.
+func (o SortOrder) SupportsKeyset() bool {
+	return o == "" || o == SortDateAsc || o == SortDateDesc
+}
@@ -0,0 +1,37 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	for _, o := range SortOrders {
+		parsed, err := ParseSortOrder(string(o))
+		require.NoError(t, err)
+		require.Equal(t, o, parsed)
+	}
+
+	_, err := ParseSortOrder("name; DROP TABLE events")
+	require.Equal(t, ErrInvalidSort, err)
+}
+
+func TestEventFilter_Validate_Sort(t *testing.T) {
+	f := &EventFilter{Sort: "random"}
+	require.Equal(t, ErrInvalidSort, f.Validate())
+
+	f = &EventFilter{Sort: SortPopularity, Pagination: &Pagination{Page: 2, PageSize: 10}}
+	require.NoError(t, f.Validate())
+}
+
+func TestEventFilter_Validate_KeysetSort(t *testing.T) {
+	f := &EventFilter{Sort: SortDateDesc, Keyset: &Keyset{Limit: 10}}
+	require.NoError(t, f.Validate())
+
+	f = &EventFilter{Sort: SortFeeAsc, Keyset: &Keyset{Limit: 10}}
+	require.Equal(t, ErrCursorNotSupported, f.Validate())
+
+	f = &EventFilter{Sort: SortDateAsc, Query: "jazz", Keyset: &Keyset{Limit: 10}}
+	require.NoError(t, f.Validate())
+}
@@ -0,0 +1,75 @@
+package event
+
+import "slices"
+
+// Status is the stage of an event's lifecycle. Events start as drafts or
+// published, are published before they can complete, and can be cancelled
+// until they complete.
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusPublished Status = "published"
+	StatusCancelled Status = "cancelled"
+	StatusCompleted Status = "completed"
+)
+
+// Statuses lists every event status.
+var Statuses = []Status{StatusDraft, StatusPublished, StatusCancelled, StatusCompleted}
+
+// statusTransitions lists the statuses each status can move to. Cancelled
+// and completed events are final.
+var statusTransitions = map[Status][]Status{
+	StatusDraft:     {StatusPublished, StatusCancelled},
+	StatusPublished: {StatusCompleted, StatusCancelled},
+}
+
+func ParseStatus(s string) (Status, error) {
+	for _, st := range Statuses {
+		if string(st) == s {
+			return st, nil
+		}
+	}
+	return "", ErrInvalidStatus
+}
+
+// IsInitial reports whether events can be created with the status.
+func (s Status) IsInitial() bool {
+	return s == StatusDraft || s == StatusPublished
+}
+
+func (s Status) CanTransitionTo(next Status) bool {
+	return slices.Contains(statusTransitions[s], next)
+}
+
+// TransitionTo moves the event to the next status, failing with
+// ErrInvalidStatusTransition if its current status does not allow it.
+func (e *Event) TransitionTo(next Status) error {
+	if _, err := ParseStatus(string(next)); err != nil {
+		return err
+	}
+	if !e.Status.CanTransitionTo(next) {
+		return ErrInvalidStatusTransition
+	}
+	e.Status = next
+	return nil
+}
+
+// AcceptsRegistrations reports why users cannot register for the event, or
+// returns nil if they can. Only published events take registrations.
+func (s Status) AcceptsRegistrations() error {
+	switch s {
+	case StatusPublished:
+		return nil
+	case StatusCancelled:
+		return ErrEventCancelled
+	default:
+		return ErrRegistrationClosed
+	}
+}
+
+// VisibleTo reports whether the user can see the event. Drafts are only
+// visible to their organizer.
+func (e *Event) VisibleTo(userID string) bool {
+	return e.Status != StatusDraft || (userID != "" && e.OrganizerID == userID)
+}
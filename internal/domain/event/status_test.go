@@ -0,0 +1,81 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatus(t *testing.T) {
+	for _, s := range Statuses {
+		parsed, err := ParseStatus(string(s))
+		require.NoError(t, err)
+		require.Equal(t, s, parsed)
+	}
+
+	_, err := ParseStatus("archived")
+	require.Equal(t, ErrInvalidStatus, err)
+}
+
+func TestStatus_IsInitial(t *testing.T) {
+	require.True(t, StatusDraft.IsInitial())
+	require.True(t, StatusPublished.IsInitial())
+	require.False(t, StatusCancelled.IsInitial())
+	require.False(t, StatusCompleted.IsInitial())
+}
+
+func TestEvent_TransitionTo(t *testing.T) {
+	allowed := []struct{ from, to Status }{
+		{StatusDraft, StatusPublished},
+		{StatusDraft, StatusCancelled},
+		{StatusPublished, StatusCompleted},
+		{StatusPublished, StatusCancelled},
+	}
+	for _, tc := range allowed {
+		e := &Event{Status: tc.from}
+		require.NoError(t, e.TransitionTo(tc.to), "%s -> %s", tc.from, tc.to)
+		require.Equal(t, tc.to, e.Status)
+	}
+
+	refused := []struct{ from, to Status }{
+		{StatusDraft, StatusCompleted},
+		{StatusPublished, StatusDraft},
+		{StatusPublished, StatusPublished},
+		{StatusCancelled, StatusPublished},
+		{StatusCompleted, StatusCancelled},
+	}
+	for _, tc := range refused {
+		e := &Event{Status: tc.from}
+		require.Equal(t, ErrInvalidStatusTransition, e.TransitionTo(tc.to), "%s -> %s", tc.from, tc.to)
+		require.Equal(t, tc.from, e.Status)
+	}
+
+	e := &Event{Status: StatusDraft}
+	require.Equal(t, ErrInvalidStatus, e.TransitionTo("archived"))
+}
+
+func TestStatus_AcceptsRegistrations(t *testing.T) {
+	require.NoError(t, StatusPublished.AcceptsRegistrations())
+	require.Equal(t, ErrEventCancelled, StatusCancelled.AcceptsRegistrations())
+	require.Equal(t, ErrRegistrationClosed, StatusDraft.AcceptsRegistrations())
+	require.Equal(t, ErrRegistrationClosed, StatusCompleted.AcceptsRegistrations())
+}
+
+func TestEvent_VisibleTo(t *testing.T) {
+	draft := &Event{OrganizerID: "organizer-1", Status: StatusDraft}
+	require.True(t, draft.VisibleTo("organizer-1"))
+	require.False(t, draft.VisibleTo("user-2"))
+	require.False(t, draft.VisibleTo(""))
+
+	cancelled := &Event{OrganizerID: "organizer-1", Status: StatusCancelled}
+	require.True(t, cancelled.VisibleTo(""))
+}
+
+func TestEvent_Validate_Status(t *testing.T) {
+	e := newMeetup()
+	e.Status = ""
+	require.Equal(t, ErrInvalidStatus, e.Validate())
+
+	e.Status = StatusDraft
+	require.NoError(t, e.Validate())
+}
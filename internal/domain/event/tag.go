@@ -1,15 +1,17 @@
 package event
 
+import "context"
+
 type Tag struct {
 	TagID int64  `json:"tag_id"`
 	Name  string `json:"name"`
 }
 
 type TagRepo interface {
-	FindAll() ([]Tag, error)
-	FindByName(name string) (*Tag, error)
-	CreateIfNotExists(name string) (*Tag, error)
-	SeedDefaults() error
+	FindAll(ctx context.Context) ([]Tag, error)
+	FindByName(ctx context.Context, name string) (*Tag, error)
+	CreateIfNotExists(ctx context.Context, name string) (*Tag, error)
+	SeedDefaults(ctx context.Context) error
 }
 
 var DefaultTagNames = []string{
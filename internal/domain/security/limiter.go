@@ -0,0 +1,86 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTooManyAttempts = errors.New("too many attempts")
+
+// LockedOutError is returned instead of checking credentials while a client
+// or an account is locked out. It matches ErrTooManyAttempts.
+type LockedOutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedOutError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockedOutError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// LockoutPolicy decides how long a key is locked out after failed attempts.
+type LockoutPolicy struct {
+	// MaxFailures is how many failures are let through before the first
+	// lockout.
+	MaxFailures int
+	// BaseDelay is the first lockout. Every further failure doubles it, up to
+	// MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Window is how long failures are remembered after the last one. It
+	// should be longer than MaxDelay, or the backoff never reaches it.
+	Window time.Duration
+}
+
+// DefaultEmailLockoutPolicy locks an account out after a few failed logins.
+var DefaultEmailLockoutPolicy = LockoutPolicy{
+	MaxFailures: 5,
+	BaseDelay:   time.Minute,
+	MaxDelay:    time.Hour,
+	Window:      24 * time.Hour,
+}
+
+// DefaultIPLockoutPolicy is looser than DefaultEmailLockoutPolicy, since many
+// users can share an address.
+var DefaultIPLockoutPolicy = LockoutPolicy{
+	MaxFailures: 20,
+	BaseDelay:   time.Minute,
+	MaxDelay:    time.Hour,
+	Window:      6 * time.Hour,
+}
+
+// Lockout returns how long a key is locked out after its failures-th failure
+// in a row.
+func (p LockoutPolicy) Lockout(failures int) time.Duration {
+	if failures < p.MaxFailures {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.MaxFailures; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+//go:generate mockgen -destination=./mocks/mock_loginlimiter.go . LoginLimiter
+
+// LoginLimiter counts failed logins per key, such as a client IP or an
+// email address, and locks the key out when there are too many.
+type LoginLimiter interface {
+	// Wait returns how long the key is still locked out at now, zero if it
+	// may try.
+	Wait(ctx context.Context, key string, now time.Time) (time.Duration, error)
+	// Fail records a failed attempt of the key and returns the lockout it
+	// starts, zero if none.
+	Fail(ctx context.Context, key string, now time.Time) (time.Duration, error)
+	// Reset forgets the failures of the key.
+	Reset(ctx context.Context, key string) error
+	// Purge forgets the keys that are neither locked out nor failed recently
+	// at now, and returns how many there were.
+	Purge(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,39 @@
+package security
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit allows Requests requests every Per, in bursts of up to Requests.
+// The zero value allows everything.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (l RateLimit) Unlimited() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+// RateLimitResult is the state of a bucket after a request has been counted.
+type RateLimitResult struct {
+	Allowed bool
+	// Remaining is how many more requests are allowed right away.
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed when this one
+	// was not.
+	RetryAfter time.Duration
+}
+
+//go:generate mockgen -destination=./mocks/mock_ratelimiter.go . RateLimiter
+
+// RateLimiter keeps a token bucket per key, such as a user or a client IP.
+// The bucket holds limit.Requests tokens, refills continuously at
+// limit.Requests per limit.Per, and every allowed request takes a token.
+type RateLimiter interface {
+	// Take counts a request of the key at now.
+	Take(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
@@ -0,0 +1,31 @@
+package security
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// Claims are what an access token states about its bearer.
+type Claims struct {
+	// Subject is the UUID of the user the token was issued to.
+	Subject   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+//go:generate mockgen -destination=./mocks/mock_tokensigner.go . TokenSigner
+
+// TokenSigner issues and checks signed access tokens. A token carries its
+// own claims, so checking it needs no storage.
+type TokenSigner interface {
+	Sign(claims Claims) (string, error)
+	// Verify returns the claims of a token signed by this signer. It reports
+	// ErrInvalidToken for anything else, and ErrTokenExpired once the token
+	// has expired at now.
+	Verify(token string, now time.Time) (*Claims, error)
+}
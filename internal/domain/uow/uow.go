@@ -0,0 +1,24 @@
+package uow
+
+import (
+	"context"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+// Repos are the repositories bound to a single unit of work.
+type Repos struct {
+	Events         event.EventRepo
+	Users          user.UserRepo
+	Sessions       user.SessionRepo
+	PasswordResets user.PasswordResetRepo
+	RefreshTokens  user.RefreshTokenRepo
+}
+
+//go:generate mockgen -destination=./mocks/mock_txmanager.go . TxManager
+type TxManager interface {
+	// WithinTx calls fn with repositories sharing one transaction. It commits
+	// when fn returns nil and rolls back otherwise, returning fn's error.
+	WithinTx(ctx context.Context, fn func(Repos) error) error
+}
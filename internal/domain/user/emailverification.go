@@ -0,0 +1,29 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+// EmailVerification is what a verification token proves: that the user
+// can read mail sent to Email.
+type EmailVerification struct {
+	UserID string
+	Email  string
+}
+
+//go:generate mockgen -destination=./mocks/mock_emailverificationrepo.go . EmailVerificationRepo
+
+// EmailVerificationRepo stores the tokens of the verification links mailed to
+// users. Each token can be used once.
+type EmailVerificationRepo interface {
+	// Create stores a new token verifying that the user owns email and
+	// returns it. Earlier tokens of the user stop working.
+	Create(ctx context.Context, userID string, email string, expiresAt time.Time) (string, error)
+	// Consume removes the token and returns what it verifies. Unknown, used
+	// and expired tokens are reported as ErrInvalidVerificationToken.
+	Consume(ctx context.Context, token string, now time.Time) (*EmailVerification, error)
+	// LastSentAt returns when the latest token of the user was created, or
+	// the zero time if there is none.
+	LastSentAt(ctx context.Context, userID string) (time.Time, error)
+}
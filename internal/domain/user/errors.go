@@ -3,11 +3,19 @@ package user
 import "errors"
 
 var (
-	ErrInvalidEmailFormat = errors.New("email is in invalid format")
-	ErrUsernameTooShort   = errors.New("username has to have at least 5 characters")
-	ErrPasswordTooShort   = errors.New("password is too short")
-	ErrPasswordTooLong    = errors.New("password is too long")
-	ErrPasswordTooWeak    = errors.New("password should contain at least one uppercase letter, one lowercase letter, one digit, and one special character")
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrUserExists         = errors.New("user already exsits")
+	ErrInvalidEmailFormat       = errors.New("email is in invalid format")
+	ErrUsernameTooShort         = errors.New("username has to have at least 5 characters")
+	ErrPasswordTooShort         = errors.New("password is too short")
+	ErrPasswordTooLong          = errors.New("password is too long")
+	ErrPasswordTooWeak          = errors.New("password should contain at least one uppercase letter, one lowercase letter, one digit, and one special character")
+	ErrInvalidCredentials       = errors.New("invalid email or password")
+	ErrUserExists               = errors.New("user already exsits")
+	ErrSessionNotFound          = errors.New("session not found")
+	ErrSessionExpired           = errors.New("session expired")
+	ErrInvalidRefreshToken      = errors.New("invalid refresh token")
+	ErrInvalidResetToken        = errors.New("invalid or expired reset token")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrEmailNotVerified         = errors.New("email is not verified")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrEmailUnchanged           = errors.New("new email is the same as the current one")
 )
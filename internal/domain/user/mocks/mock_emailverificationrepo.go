@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kapiw04/convenly/internal/domain/user (interfaces: EmailVerificationRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_emailverificationrepo.go . EmailVerificationRepo
+//
+
+// Package mock_user is a generated GoMock package.
+package mock_user
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	user "github.com/kapiw04/convenly/internal/domain/user"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEmailVerificationRepo is a mock of EmailVerificationRepo interface.
+type MockEmailVerificationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailVerificationRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailVerificationRepoMockRecorder is the mock recorder for MockEmailVerificationRepo.
+type MockEmailVerificationRepoMockRecorder struct {
+	mock *MockEmailVerificationRepo
+}
+
+// NewMockEmailVerificationRepo creates a new mock instance.
+func NewMockEmailVerificationRepo(ctrl *gomock.Controller) *MockEmailVerificationRepo {
+	mock := &MockEmailVerificationRepo{ctrl: ctrl}
+	mock.recorder = &MockEmailVerificationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailVerificationRepo) EXPECT() *MockEmailVerificationRepoMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockEmailVerificationRepo) Consume(ctx context.Context, token string, now time.Time) (*user.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, token, now)
+	ret0, _ := ret[0].(*user.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockEmailVerificationRepoMockRecorder) Consume(ctx, token, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockEmailVerificationRepo)(nil).Consume), ctx, token, now)
+}
+
+// Create mocks base method.
+func (m *MockEmailVerificationRepo) Create(ctx context.Context, userID, email string, expiresAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, email, expiresAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEmailVerificationRepoMockRecorder) Create(ctx, userID, email, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEmailVerificationRepo)(nil).Create), ctx, userID, email, expiresAt)
+}
+
+// LastSentAt mocks base method.
+func (m *MockEmailVerificationRepo) LastSentAt(ctx context.Context, userID string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastSentAt", ctx, userID)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastSentAt indicates an expected call of LastSentAt.
+func (mr *MockEmailVerificationRepoMockRecorder) LastSentAt(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastSentAt", reflect.TypeOf((*MockEmailVerificationRepo)(nil).LastSentAt), ctx, userID)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kapiw04/convenly/internal/domain/user (interfaces: PasswordResetRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_passwordresetrepo.go . PasswordResetRepo
+//
+
+// Package mock_user is a generated GoMock package.
+package mock_user
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPasswordResetRepo is a mock of PasswordResetRepo interface.
+type MockPasswordResetRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockPasswordResetRepoMockRecorder is the mock recorder for MockPasswordResetRepo.
+type MockPasswordResetRepoMockRecorder struct {
+	mock *MockPasswordResetRepo
+}
+
+// NewMockPasswordResetRepo creates a new mock instance.
+func NewMockPasswordResetRepo(ctrl *gomock.Controller) *MockPasswordResetRepo {
+	mock := &MockPasswordResetRepo{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetRepo) EXPECT() *MockPasswordResetRepoMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockPasswordResetRepo) Consume(ctx context.Context, token string, now time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, token, now)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockPasswordResetRepoMockRecorder) Consume(ctx, token, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockPasswordResetRepo)(nil).Consume), ctx, token, now)
+}
+
+// Create mocks base method.
+func (m *MockPasswordResetRepo) Create(ctx context.Context, userID string, expiresAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, expiresAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPasswordResetRepoMockRecorder) Create(ctx, userID, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPasswordResetRepo)(nil).Create), ctx, userID, expiresAt)
+}
+
+// LastSentAt mocks base method.
+func (m *MockPasswordResetRepo) LastSentAt(ctx context.Context, userID string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastSentAt", ctx, userID)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastSentAt indicates an expected call of LastSentAt.
+func (mr *MockPasswordResetRepoMockRecorder) LastSentAt(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastSentAt", reflect.TypeOf((*MockPasswordResetRepo)(nil).LastSentAt), ctx, userID)
+}
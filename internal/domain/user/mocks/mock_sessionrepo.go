@@ -10,7 +10,9 @@
 package mock_user
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	user "github.com/kapiw04/convenly/internal/domain/user"
 	gomock "go.uber.org/mock/gomock"
@@ -41,45 +43,103 @@ func (m *MockSessionRepo) EXPECT() *MockSessionRepoMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockSessionRepo) Create(email string) (string, error) {
+func (m *MockSessionRepo) Create(ctx context.Context, email string, device user.Device) (*user.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", email)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Create", ctx, email, device)
+	ret0, _ := ret[0].(*user.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockSessionRepoMockRecorder) Create(email any) *gomock.Call {
+func (mr *MockSessionRepoMockRecorder) Create(ctx, email, device any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionRepo)(nil).Create), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionRepo)(nil).Create), ctx, email, device)
 }
 
 // Delete mocks base method.
-func (m *MockSessionRepo) Delete(id string) error {
+func (m *MockSessionRepo) Delete(ctx context.Context, token string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, token)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockSessionRepoMockRecorder) Delete(id any) *gomock.Call {
+func (mr *MockSessionRepoMockRecorder) Delete(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSessionRepo)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSessionRepo)(nil).Delete), ctx, token)
+}
+
+// DeleteByID mocks base method.
+func (m *MockSessionRepo) DeleteByID(ctx context.Context, userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID.
+func (mr *MockSessionRepoMockRecorder) DeleteByID(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockSessionRepo)(nil).DeleteByID), ctx, userID, id)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockSessionRepo) DeleteByUser(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockSessionRepoMockRecorder) DeleteByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockSessionRepo)(nil).DeleteByUser), ctx, userID)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockSessionRepo) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockSessionRepoMockRecorder) DeleteExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockSessionRepo)(nil).DeleteExpired), ctx, now)
 }
 
 // Get mocks base method.
-func (m *MockSessionRepo) Get(id string) (user.User, error) {
+func (m *MockSessionRepo) Get(ctx context.Context, token string) (*user.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", id)
-	ret0, _ := ret[0].(user.User)
+	ret := m.ctrl.Call(m, "Get", ctx, token)
+	ret0, _ := ret[0].(*user.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockSessionRepoMockRecorder) Get(id any) *gomock.Call {
+func (mr *MockSessionRepoMockRecorder) Get(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSessionRepo)(nil).Get), ctx, token)
+}
+
+// ListByUser mocks base method.
+func (m *MockSessionRepo) ListByUser(ctx context.Context, userID string) ([]*user.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]*user.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockSessionRepoMockRecorder) ListByUser(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSessionRepo)(nil).Get), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockSessionRepo)(nil).ListByUser), ctx, userID)
 }
@@ -10,7 +10,9 @@
 package mock_user
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	user "github.com/kapiw04/convenly/internal/domain/user"
 	gomock "go.uber.org/mock/gomock"
@@ -40,104 +42,205 @@ func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
 	return m.recorder
 }
 
+// CalendarToken mocks base method.
+func (m *MockUserRepo) CalendarToken(ctx context.Context, userID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalendarToken", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalendarToken indicates an expected call of CalendarToken.
+func (mr *MockUserRepoMockRecorder) CalendarToken(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalendarToken", reflect.TypeOf((*MockUserRepo)(nil).CalendarToken), ctx, userID)
+}
+
 // Count mocks base method.
-func (m *MockUserRepo) Count() (int, error) {
+func (m *MockUserRepo) Count(ctx context.Context) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Count")
+	ret := m.ctrl.Call(m, "Count", ctx)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Count indicates an expected call of Count.
-func (mr *MockUserRepoMockRecorder) Count() *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Count(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUserRepo)(nil).Count))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUserRepo)(nil).Count), ctx)
 }
 
 // DeleteByUUID mocks base method.
-func (m *MockUserRepo) DeleteByUUID(uuid string) error {
+func (m *MockUserRepo) DeleteByUUID(ctx context.Context, uuid string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByUUID", uuid)
+	ret := m.ctrl.Call(m, "DeleteByUUID", ctx, uuid)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByUUID indicates an expected call of DeleteByUUID.
-func (mr *MockUserRepoMockRecorder) DeleteByUUID(uuid any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) DeleteByUUID(ctx, uuid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUUID", reflect.TypeOf((*MockUserRepo)(nil).DeleteByUUID), uuid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUUID", reflect.TypeOf((*MockUserRepo)(nil).DeleteByUUID), ctx, uuid)
 }
 
 // FindAll mocks base method.
-func (m *MockUserRepo) FindAll() ([]*user.User, error) {
+func (m *MockUserRepo) FindAll(ctx context.Context) ([]*user.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll")
+	ret := m.ctrl.Call(m, "FindAll", ctx)
 	ret0, _ := ret[0].([]*user.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockUserRepoMockRecorder) FindAll() *gomock.Call {
+func (mr *MockUserRepoMockRecorder) FindAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepo)(nil).FindAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepo)(nil).FindAll), ctx)
+}
+
+// FindByCalendarToken mocks base method.
+func (m *MockUserRepo) FindByCalendarToken(ctx context.Context, token string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCalendarToken", ctx, token)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCalendarToken indicates an expected call of FindByCalendarToken.
+func (mr *MockUserRepoMockRecorder) FindByCalendarToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCalendarToken", reflect.TypeOf((*MockUserRepo)(nil).FindByCalendarToken), ctx, token)
 }
 
 // FindByEmail mocks base method.
-func (m *MockUserRepo) FindByEmail(email string) (*user.User, error) {
+func (m *MockUserRepo) FindByEmail(ctx context.Context, email string) (*user.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByEmail", email)
+	ret := m.ctrl.Call(m, "FindByEmail", ctx, email)
 	ret0, _ := ret[0].(*user.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByEmail indicates an expected call of FindByEmail.
-func (mr *MockUserRepoMockRecorder) FindByEmail(email any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) FindByEmail(ctx, email any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserRepo)(nil).FindByEmail), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserRepo)(nil).FindByEmail), ctx, email)
 }
 
 // FindByUUID mocks base method.
-func (m *MockUserRepo) FindByUUID(uuid string) (*user.User, error) {
+func (m *MockUserRepo) FindByUUID(ctx context.Context, uuid string) (*user.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByUUID", uuid)
+	ret := m.ctrl.Call(m, "FindByUUID", ctx, uuid)
 	ret0, _ := ret[0].(*user.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByUUID indicates an expected call of FindByUUID.
-func (mr *MockUserRepoMockRecorder) FindByUUID(uuid any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) FindByUUID(ctx, uuid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUUID", reflect.TypeOf((*MockUserRepo)(nil).FindByUUID), ctx, uuid)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserRepo) MarkEmailVerified(ctx context.Context, userID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, userID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserRepoMockRecorder) MarkEmailVerified(ctx, userID, email any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUUID", reflect.TypeOf((*MockUserRepo)(nil).FindByUUID), uuid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserRepo)(nil).MarkEmailVerified), ctx, userID, email)
+}
+
+// RevokeAccessTokens mocks base method.
+func (m *MockUserRepo) RevokeAccessTokens(ctx context.Context, userID string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessTokens", ctx, userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessTokens indicates an expected call of RevokeAccessTokens.
+func (mr *MockUserRepoMockRecorder) RevokeAccessTokens(ctx, userID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessTokens", reflect.TypeOf((*MockUserRepo)(nil).RevokeAccessTokens), ctx, userID, at)
+}
+
+// RotateCalendarToken mocks base method.
+func (m *MockUserRepo) RotateCalendarToken(ctx context.Context, userID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateCalendarToken", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateCalendarToken indicates an expected call of RotateCalendarToken.
+func (mr *MockUserRepoMockRecorder) RotateCalendarToken(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateCalendarToken", reflect.TypeOf((*MockUserRepo)(nil).RotateCalendarToken), ctx, userID)
 }
 
 // Save mocks base method.
-func (m *MockUserRepo) Save(arg0 *user.User) error {
+func (m *MockUserRepo) Save(ctx context.Context, arg1 *user.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", arg0)
+	ret := m.ctrl.Call(m, "Save", ctx, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockUserRepoMockRecorder) Save(arg0 any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Save(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserRepo)(nil).Save), ctx, arg1)
+}
+
+// SetPasswordHash mocks base method.
+func (m *MockUserRepo) SetPasswordHash(ctx context.Context, userID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPasswordHash", ctx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPasswordHash indicates an expected call of SetPasswordHash.
+func (mr *MockUserRepoMockRecorder) SetPasswordHash(ctx, userID, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPasswordHash", reflect.TypeOf((*MockUserRepo)(nil).SetPasswordHash), ctx, userID, passwordHash)
+}
+
+// SetRole mocks base method.
+func (m *MockUserRepo) SetRole(ctx context.Context, userID string, role user.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockUserRepoMockRecorder) SetRole(ctx, userID, role any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserRepo)(nil).Save), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockUserRepo)(nil).SetRole), ctx, userID, role)
 }
 
-// Update mocks base method.
-func (m *MockUserRepo) Update(arg0 *user.User) error {
+// UpdateName mocks base method.
+func (m *MockUserRepo) UpdateName(ctx context.Context, userID, name string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "UpdateName", ctx, userID, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockUserRepoMockRecorder) Update(arg0 any) *gomock.Call {
+// UpdateName indicates an expected call of UpdateName.
+func (mr *MockUserRepoMockRecorder) UpdateName(ctx, userID, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepo)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateName", reflect.TypeOf((*MockUserRepo)(nil).UpdateName), ctx, userID, name)
 }
@@ -0,0 +1,22 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=./mocks/mock_passwordresetrepo.go . PasswordResetRepo
+
+// PasswordResetRepo stores the tokens mailed to users who forgot their
+// password. Each token can be used once.
+type PasswordResetRepo interface {
+	// Create stores a new reset token of the user and returns it. Earlier
+	// tokens of the user stop working.
+	Create(ctx context.Context, userID string, expiresAt time.Time) (string, error)
+	// Consume removes the token and returns its user. Unknown, used and
+	// expired tokens are reported as ErrInvalidResetToken.
+	Consume(ctx context.Context, token string, now time.Time) (userID string, err error)
+	// LastSentAt returns when the latest token of the user was created, or
+	// the zero time if there is none.
+	LastSentAt(ctx context.Context, userID string) (time.Time, error)
+}
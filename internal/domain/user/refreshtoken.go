@@ -0,0 +1,21 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=./mocks/mock_refreshtokenrepo.go . RefreshTokenRepo
+
+// RefreshTokenRepo stores the refresh tokens clients exchange for new access
+// tokens. Each token can be used once.
+type RefreshTokenRepo interface {
+	// Create stores a new refresh token of the user and returns it.
+	Create(ctx context.Context, userID string, expiresAt time.Time) (string, error)
+	// Consume removes the token and returns its user. Unknown, used and
+	// expired tokens are reported as ErrInvalidRefreshToken.
+	Consume(ctx context.Context, token string, now time.Time) (userID string, err error)
+	Delete(ctx context.Context, token string) error
+	// DeleteByUser revokes all refresh tokens of the user.
+	DeleteByUser(ctx context.Context, userID string) error
+}
@@ -1,9 +1,98 @@
 package user
 
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a logged-in browser of a user. It expires when it has not been
+// used for a while, and in any case some time after the login.
+type Session struct {
+	// ID names the session to its user, for example to revoke it. Unlike
+	// Token it grants no access.
+	ID uuid.UUID `json:"id"`
+	// Token is the secret the client authenticates with. It is only known
+	// when the session is created or presented by the client.
+	Token      string    `json:"-"`
+	UserID     uuid.UUID `json:"-"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Device is the client a session is started from.
+type Device struct {
+	UserAgent string
+	IP        string
+}
+
+// SessionPolicy decides how long sessions last.
+type SessionPolicy struct {
+	// TTL is how long a session lasts without being used. Each use renews it.
+	TTL time.Duration
+	// MaxAge is how long a session lasts after login, however often it is used.
+	MaxAge time.Duration
+}
+
+var DefaultSessionPolicy = SessionPolicy{
+	TTL:    7 * 24 * time.Hour,
+	MaxAge: 30 * 24 * time.Hour,
+}
+
+// NewSession starts a session of the user on the device at now.
+func (p SessionPolicy) NewSession(token string, userID uuid.UUID, device Device, now time.Time) *Session {
+	return &Session{
+		ID:         uuid.New(),
+		Token:      token,
+		UserID:     userID,
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  p.expiresAt(now, now),
+	}
+}
+
+// Renew records a use of the session at now and pushes its expiry back,
+// unless it has already expired.
+func (p SessionPolicy) Renew(s *Session, now time.Time) error {
+	if !now.Before(s.ExpiresAt) {
+		return ErrSessionExpired
+	}
+	s.LastSeenAt = now
+	s.ExpiresAt = p.expiresAt(s.CreatedAt, now)
+	return nil
+}
+
+func (p SessionPolicy) expiresAt(createdAt, now time.Time) time.Time {
+	expiresAt := now.Add(p.TTL)
+	if limit := createdAt.Add(p.MaxAge); expiresAt.After(limit) {
+		return limit
+	}
+	return expiresAt
+}
+
 //go:generate mockgen -destination=./mocks/mock_sessionrepo.go . SessionRepo
 
 type SessionRepo interface {
-	Create(email string) (id string, err error)
-	Get(id string) (User, error)
-	Delete(id string) error
+	Create(ctx context.Context, email string, device Device) (*Session, error)
+	// Get returns the session with the token and renews it. Expired sessions
+	// are reported as ErrSessionExpired.
+	Get(ctx context.Context, token string) (*Session, error)
+	// ListByUser returns the user's unexpired sessions, most recently used
+	// first.
+	ListByUser(ctx context.Context, userID string) ([]*Session, error)
+	Delete(ctx context.Context, token string) error
+	// DeleteByID ends one session of the user. A session of someone else is
+	// reported as ErrSessionNotFound.
+	DeleteByID(ctx context.Context, userID string, id string) error
+	// DeleteByUser ends all sessions of the user.
+	DeleteByUser(ctx context.Context, userID string) error
+	// DeleteExpired removes sessions that expired before now and returns how
+	// many it removed.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
 }
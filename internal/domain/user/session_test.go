@@ -0,0 +1,50 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var testPolicy = SessionPolicy{TTL: 24 * time.Hour, MaxAge: 72 * time.Hour}
+
+func TestSessionPolicy_NewSession(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := testPolicy.NewSession("token", uuid.New(), Device{}, now)
+
+	require.NotEqual(t, uuid.Nil, s.ID)
+	require.Equal(t, "token", s.Token)
+	require.Equal(t, now, s.CreatedAt)
+	require.Equal(t, now, s.LastSeenAt)
+	require.Equal(t, now.Add(24*time.Hour), s.ExpiresAt)
+}
+
+func TestSessionPolicy_Renew_Slides(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := testPolicy.NewSession("token", uuid.New(), Device{}, created)
+
+	used := created.Add(20 * time.Hour)
+	require.NoError(t, testPolicy.Renew(s, used))
+	require.Equal(t, used, s.LastSeenAt)
+	require.Equal(t, used.Add(24*time.Hour), s.ExpiresAt)
+}
+
+func TestSessionPolicy_Renew_CappedByMaxAge(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := testPolicy.NewSession("token", uuid.New(), Device{}, created)
+	s.ExpiresAt = created.Add(71 * time.Hour)
+
+	require.NoError(t, testPolicy.Renew(s, created.Add(60*time.Hour)))
+	require.Equal(t, created.Add(72*time.Hour), s.ExpiresAt)
+}
+
+func TestSessionPolicy_Renew_Expired(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := testPolicy.NewSession("token", uuid.New(), Device{}, created)
+
+	err := testPolicy.Renew(s, created.Add(24*time.Hour))
+	require.ErrorIs(t, err, ErrSessionExpired)
+	require.Equal(t, created, s.LastSeenAt)
+}
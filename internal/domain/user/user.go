@@ -1,7 +1,9 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,21 +18,56 @@ const (
 )
 
 type User struct {
-	UUID         uuid.UUID `json:"uuid"`
-	Name         string    `json:"name"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	Role         Role      `json:"role"`
+	UUID          uuid.UUID `json:"uuid"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	PasswordHash  string    `json:"-"`
+	Role          Role      `json:"role"`
+	EmailVerified bool      `json:"email_verified"`
+	// TokensValidAfter is when the user's access tokens were last revoked;
+	// tokens issued until then are rejected. Zero if they never were.
+	TokensValidAfter time.Time `json:"-"`
+}
+
+// ProfileUpdate holds the profile fields a user changes; nil fields are kept.
+type ProfileUpdate struct {
+	Name *string
+}
+
+func (p *ProfileUpdate) Apply(u *User) {
+	if p == nil {
+		return
+	}
+	if p.Name != nil {
+		u.Name = *p.Name
+	}
 }
 
 type UserRepo interface {
-	Save(user *User) error
-	FindByUUID(uuid string) (*User, error)
-	FindByEmail(email string) (*User, error)
-	FindAll() ([]*User, error)
-	DeleteByUUID(uuid string) error
-	Update(user *User) error
-	Count() (int, error)
+	Save(ctx context.Context, user *User) error
+	FindByUUID(ctx context.Context, uuid string) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindAll(ctx context.Context) ([]*User, error)
+	// DeleteByUUID deletes the user with their sessions and the events they
+	// host. Seats they held are given to the waitlists.
+	DeleteByUUID(ctx context.Context, uuid string) error
+	// UpdateName, SetPasswordHash, SetRole and MarkEmailVerified change only
+	// their own columns, so concurrent changes to the same user do not undo
+	// each other. They fail with ErrUserNotFound for unknown users.
+	UpdateName(ctx context.Context, userID string, name string) error
+	SetPasswordHash(ctx context.Context, userID string, passwordHash string) error
+	SetRole(ctx context.Context, userID string, role Role) error
+	// MarkEmailVerified sets the user's email, which has been verified.
+	MarkEmailVerified(ctx context.Context, userID string, email string) error
+	// RevokeAccessTokens rejects the user's access tokens issued until at.
+	RevokeAccessTokens(ctx context.Context, userID string, at time.Time) error
+	Count(ctx context.Context) (int, error)
+	// CalendarToken generates the user's calendar feed token if they have
+	// none and returns it. Only a digest of the token is stored, so it returns
+	// "" if they already have one.
+	CalendarToken(ctx context.Context, userID string) (string, error)
+	RotateCalendarToken(ctx context.Context, userID string) (string, error)
+	FindByCalendarToken(ctx context.Context, token string) (*User, error)
 }
 
 var (
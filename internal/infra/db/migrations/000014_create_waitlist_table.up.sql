@@ -0,0 +1,11 @@
+CREATE TABLE waitlist (
+    seq BIGINT GENERATED ALWAYS AS IDENTITY,
+    user_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, event_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (event_id) REFERENCES events(event_id) ON DELETE CASCADE
+);
+
+CREATE INDEX waitlist_event_seq_idx ON waitlist (event_id, seq);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS attendance_event_registered_at_idx;
+
+ALTER TABLE attendance DROP COLUMN IF EXISTS registered_at;
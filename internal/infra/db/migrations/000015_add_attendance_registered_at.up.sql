@@ -0,0 +1,4 @@
+ALTER TABLE attendance
+ADD COLUMN registered_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX attendance_event_registered_at_idx ON attendance (event_id, registered_at);
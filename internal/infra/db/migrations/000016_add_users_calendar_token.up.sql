@@ -0,0 +1,4 @@
+ALTER TABLE users
+ADD COLUMN calendar_token TEXT;
+
+CREATE UNIQUE INDEX users_calendar_token_key ON users (calendar_token);
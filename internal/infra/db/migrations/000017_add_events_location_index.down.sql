@@ -0,0 +1 @@
+DROP INDEX IF EXISTS events_location_idx;
@@ -0,0 +1 @@
+CREATE INDEX events_location_idx ON events (latitude, longitude);
@@ -0,0 +1,26 @@
+DROP VIEW IF EXISTS find_event_with_tags;
+
+CREATE VIEW find_event_with_tags AS
+SELECT e.event_id, e.name, e.description, e.date, e.latitude, e.longitude, e.fee, e.organizer_id,
+  COALESCE(
+    ARRAY_AGG(DISTINCT t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL),
+    ARRAY[]::text[]
+  ) AS tags,
+  e.capacity
+FROM events e
+LEFT JOIN event_tag et ON et.event_id = e.event_id
+LEFT JOIN tags t ON t.tag_id = et.tag_id
+GROUP BY
+  e.event_id,
+  e.name,
+  e.description,
+  e.date,
+  e.latitude,
+  e.longitude,
+  e.fee,
+  e.organizer_id,
+  e.capacity;
+
+DROP INDEX IF EXISTS events_search_vector_idx;
+
+ALTER TABLE events DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS events_date_event_id_idx;
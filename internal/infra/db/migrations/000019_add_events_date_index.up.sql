@@ -0,0 +1 @@
+CREATE INDEX events_date_event_id_idx ON events (date, event_id);
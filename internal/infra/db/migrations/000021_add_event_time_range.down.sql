@@ -0,0 +1,61 @@
+DROP VIEW IF EXISTS popular_events;
+DROP VIEW IF EXISTS find_event_with_tags;
+
+DROP INDEX IF EXISTS events_ends_at_idx;
+DROP INDEX IF EXISTS events_starts_at_event_id_idx;
+
+ALTER TABLE events ADD COLUMN date DATE;
+
+UPDATE events SET date = (starts_at AT TIME ZONE timezone)::date;
+
+ALTER TABLE events
+DROP CONSTRAINT IF EXISTS events_time_range_check,
+DROP COLUMN IF EXISTS starts_at,
+DROP COLUMN IF EXISTS ends_at,
+DROP COLUMN IF EXISTS timezone;
+
+CREATE INDEX events_date_event_id_idx ON events (date, event_id);
+
+CREATE VIEW find_event_with_tags AS
+SELECT e.event_id, e.name, e.description, e.date, e.latitude, e.longitude, e.fee, e.organizer_id,
+  COALESCE(
+    ARRAY_AGG(DISTINCT t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL),
+    ARRAY[]::text[]
+  ) AS tags,
+  e.capacity,
+  e.search_vector,
+  e.created_at
+FROM events e
+LEFT JOIN event_tag et ON et.event_id = e.event_id
+LEFT JOIN tags t ON t.tag_id = et.tag_id
+GROUP BY
+  e.event_id,
+  e.name,
+  e.description,
+  e.date,
+  e.latitude,
+  e.longitude,
+  e.fee,
+  e.organizer_id,
+  e.capacity,
+  e.search_vector,
+  e.created_at;
+
+CREATE VIEW popular_events AS
+SELECT e.event_id, e.name, e.description, e.date, e.latitude, e.longitude, e.fee, e.organizer_id, ac.count, COALESCE(ARRAY_AGG(DISTINCT t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL), ARRAY[]::text[]) AS tags
+FROM events e
+LEFT JOIN event_tag et ON et.event_id = e.event_id
+LEFT JOIN tags t ON t.tag_id = et.tag_id
+LEFT JOIN attendees_count ac ON ac.event_id = e.event_id
+GROUP BY
+  e.event_id,
+  e.name,
+  e.description,
+  e.date,
+  e.latitude,
+  e.longitude,
+  e.fee,
+  e.organizer_id,
+  ac.count
+HAVING
+  ac.count > 10;
@@ -0,0 +1,69 @@
+DROP VIEW IF EXISTS popular_events;
+DROP VIEW IF EXISTS find_event_with_tags;
+
+ALTER TABLE events
+ADD COLUMN starts_at TIMESTAMPTZ,
+ADD COLUMN ends_at TIMESTAMPTZ,
+ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Existing events only have a day, so they are assumed to last all of it.
+UPDATE events
+SET starts_at = COALESCE(date, created_at::date)::timestamp AT TIME ZONE 'UTC',
+    ends_at = (COALESCE(date, created_at::date) + 1)::timestamp AT TIME ZONE 'UTC';
+
+ALTER TABLE events
+ALTER COLUMN starts_at SET NOT NULL,
+ALTER COLUMN ends_at SET NOT NULL,
+ADD CONSTRAINT events_time_range_check CHECK (ends_at > starts_at),
+DROP COLUMN date;
+
+CREATE INDEX events_starts_at_event_id_idx ON events (starts_at, event_id);
+CREATE INDEX events_ends_at_idx ON events (ends_at);
+
+CREATE VIEW find_event_with_tags AS
+SELECT e.event_id, e.name, e.description, e.starts_at, e.ends_at, e.timezone, e.latitude, e.longitude, e.fee, e.organizer_id,
+  COALESCE(
+    ARRAY_AGG(DISTINCT t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL),
+    ARRAY[]::text[]
+  ) AS tags,
+  e.capacity,
+  e.search_vector,
+  e.created_at
+FROM events e
+LEFT JOIN event_tag et ON et.event_id = e.event_id
+LEFT JOIN tags t ON t.tag_id = et.tag_id
+GROUP BY
+  e.event_id,
+  e.name,
+  e.description,
+  e.starts_at,
+  e.ends_at,
+  e.timezone,
+  e.latitude,
+  e.longitude,
+  e.fee,
+  e.organizer_id,
+  e.capacity,
+  e.search_vector,
+  e.created_at;
+
+CREATE VIEW popular_events AS
+SELECT e.event_id, e.name, e.description, e.starts_at, e.ends_at, e.timezone, e.latitude, e.longitude, e.fee, e.organizer_id, ac.count, COALESCE(ARRAY_AGG(DISTINCT t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL), ARRAY[]::text[]) AS tags
+FROM events e
+LEFT JOIN event_tag et ON et.event_id = e.event_id
+LEFT JOIN tags t ON t.tag_id = et.tag_id
+LEFT JOIN attendees_count ac ON ac.event_id = e.event_id
+GROUP BY
+  e.event_id,
+  e.name,
+  e.description,
+  e.starts_at,
+  e.ends_at,
+  e.timezone,
+  e.latitude,
+  e.longitude,
+  e.fee,
+  e.organizer_id,
+  ac.count
+HAVING
+  ac.count > 10;
@@ -0,0 +1,55 @@
+ALTER TABLE events
+ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX events_recurring_idx ON events (starts_at) WHERE recurrence <> '';
+
+CREATE OR REPLACE VIEW find_event_with_tags AS
+SELECT e.event_id, e.name, e.description, e.starts_at, e.ends_at, e.timezone, e.latitude, e.longitude, e.fee, e.organizer_id,
+  COALESCE(
+    ARRAY_AGG(DISTINCT t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL),
+    ARRAY[]::text[]
+  ) AS tags,
+  e.capacity,
+  e.search_vector,
+  e.created_at,
+  e.recurrence
+FROM events e
+LEFT JOIN event_tag et ON et.event_id = e.event_id
+LEFT JOIN tags t ON t.tag_id = et.tag_id
+GROUP BY
+  e.event_id,
+  e.name,
+  e.description,
+  e.starts_at,
+  e.ends_at,
+  e.timezone,
+  e.latitude,
+  e.longitude,
+  e.fee,
+  e.organizer_id,
+  e.capacity,
+  e.search_vector,
+  e.created_at,
+  e.recurrence;
+
+CREATE TABLE occurrence_exceptions (
+    event_id UUID NOT NULL,
+    recurrence_id TIMESTAMPTZ NOT NULL,
+    cancelled BOOLEAN NOT NULL DEFAULT false,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    PRIMARY KEY (event_id, recurrence_id),
+    FOREIGN KEY (event_id) REFERENCES events(event_id) ON DELETE CASCADE
+);
+
+CREATE TABLE occurrence_attendance (
+    user_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    recurrence_id TIMESTAMPTZ NOT NULL,
+    registered_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, event_id, recurrence_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (event_id) REFERENCES events(event_id) ON DELETE CASCADE
+);
+
+CREATE INDEX occurrence_attendance_event_idx ON occurrence_attendance (event_id, recurrence_id);
@@ -0,0 +1,59 @@
+ALTER TABLE events
+ADD COLUMN status TEXT NOT NULL DEFAULT 'published'
+  CONSTRAINT events_status_check CHECK (status IN ('draft', 'published', 'cancelled', 'completed'));
+
+-- drafts are only visible to their organizer, so they are left out of the
+-- public views
+CREATE OR REPLACE VIEW find_event_with_tags AS
+SELECT e.event_id, e.name, e.description, e.starts_at, e.ends_at, e.timezone, e.latitude, e.longitude, e.fee, e.organizer_id,
+  COALESCE(
+    ARRAY_AGG(DISTINCT t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL),
+    ARRAY[]::text[]
+  ) AS tags,
+  e.capacity,
+  e.search_vector,
+  e.created_at,
+  e.recurrence,
+  e.status
+FROM events e
+LEFT JOIN event_tag et ON et.event_id = e.event_id
+LEFT JOIN tags t ON t.tag_id = et.tag_id
+WHERE e.status <> 'draft'
+GROUP BY
+  e.event_id,
+  e.name,
+  e.description,
+  e.starts_at,
+  e.ends_at,
+  e.timezone,
+  e.latitude,
+  e.longitude,
+  e.fee,
+  e.organizer_id,
+  e.capacity,
+  e.search_vector,
+  e.created_at,
+  e.recurrence,
+  e.status;
+
+CREATE OR REPLACE VIEW popular_events AS
+SELECT e.event_id, e.name, e.description, e.starts_at, e.ends_at, e.timezone, e.latitude, e.longitude, e.fee, e.organizer_id, ac.count, COALESCE(ARRAY_AGG(DISTINCT t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL), ARRAY[]::text[]) AS tags
+FROM events e
+LEFT JOIN event_tag et ON et.event_id = e.event_id
+LEFT JOIN tags t ON t.tag_id = et.tag_id
+LEFT JOIN attendees_count ac ON ac.event_id = e.event_id
+WHERE e.status <> 'draft'
+GROUP BY
+  e.event_id,
+  e.name,
+  e.description,
+  e.starts_at,
+  e.ends_at,
+  e.timezone,
+  e.latitude,
+  e.longitude,
+  e.fee,
+  e.organizer_id,
+  ac.count
+HAVING
+  ac.count > 10;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS sessions_expires_at_idx;
+DROP INDEX IF EXISTS sessions_user_id_idx;
+
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS last_seen_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP CONSTRAINT IF EXISTS sessions_pkey,
+    ALTER COLUMN user_id DROP NOT NULL;
@@ -0,0 +1,13 @@
+DELETE FROM sessions WHERE user_id IS NULL OR session_id IS NULL;
+
+ALTER TABLE sessions
+    ALTER COLUMN user_id SET NOT NULL,
+    ADD PRIMARY KEY (session_id),
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN expires_at TIMESTAMPTZ NOT NULL DEFAULT now() + interval '7 days';
+
+ALTER TABLE sessions ALTER COLUMN expires_at DROP DEFAULT;
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
@@ -0,0 +1,7 @@
+DELETE FROM sessions;
+
+DROP INDEX IF EXISTS sessions_token_hash_idx;
+
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS token_hash,
+    ADD COLUMN session_id TEXT PRIMARY KEY;
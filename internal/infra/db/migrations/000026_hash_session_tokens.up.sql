@@ -0,0 +1,10 @@
+-- Existing tokens may already have leaked with the rows they were stored in,
+-- so the sessions are ended rather than converted and users log in again.
+DELETE FROM sessions;
+
+ALTER TABLE sessions
+    DROP CONSTRAINT sessions_pkey,
+    DROP COLUMN session_id,
+    ADD COLUMN token_hash BYTEA NOT NULL;
+
+CREATE UNIQUE INDEX sessions_token_hash_idx ON sessions (token_hash);
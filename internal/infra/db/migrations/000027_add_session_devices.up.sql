@@ -0,0 +1,4 @@
+ALTER TABLE sessions
+    ADD COLUMN session_id UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ip TEXT NOT NULL DEFAULT '';
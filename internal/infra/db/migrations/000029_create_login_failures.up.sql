@@ -0,0 +1,10 @@
+CREATE TABLE login_failures (
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    failures INT NOT NULL,
+    last_failure_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX login_failures_last_failure_at_idx ON login_failures (last_failure_at);
@@ -0,0 +1,3 @@
+-- access tokens issued before this are no longer accepted; NULL until the
+-- user first revokes their tokens
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMPTZ;
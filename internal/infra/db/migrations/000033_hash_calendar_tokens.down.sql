@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS users_calendar_token_hash_key;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS calendar_token_hash,
+    ADD COLUMN calendar_token TEXT;
+
+CREATE UNIQUE INDEX users_calendar_token_key ON users (calendar_token);
@@ -0,0 +1,9 @@
+-- plaintext feed tokens are dropped; subscribed calendars stop updating
+-- until the user fetches a new feed URL
+DROP INDEX IF EXISTS users_calendar_token_key;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS calendar_token,
+    ADD COLUMN calendar_token_hash BYTEA;
+
+CREATE UNIQUE INDEX users_calendar_token_hash_key ON users (calendar_token_hash);
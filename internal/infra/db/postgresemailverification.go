@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+type PostgresEmailVerificationRepo struct {
+	DB DBTX
+}
+
+func NewPostgresEmailVerificationRepo(db *sql.DB) user.EmailVerificationRepo {
+	return &PostgresEmailVerificationRepo{DB: db}
+}
+
+var _ user.EmailVerificationRepo = (*PostgresEmailVerificationRepo)(nil)
+
+func (p *PostgresEmailVerificationRepo) Create(ctx context.Context, userID string, email string, expiresAt time.Time) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// only the latest mail of the user works
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM email_verifications WHERE user_id = $1", userID)
+	if err != nil {
+		return "", err
+	}
+
+	token := generateToken()
+	query := "INSERT INTO email_verifications (token_hash, user_id, email, expires_at) VALUES ($1, $2, $3, $4)"
+	if _, err := p.DB.ExecContext(ctx, query, hashToken(token), userID, email, expiresAt); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (p *PostgresEmailVerificationRepo) Consume(ctx context.Context, token string, now time.Time) (*user.EmailVerification, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM email_verifications WHERE token_hash = $1 RETURNING user_id, email, expires_at"
+	var v user.EmailVerification
+	var expiresAt time.Time
+	err := p.DB.QueryRowContext(ctx, query, hashToken(token)).Scan(&v.UserID, &v.Email, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, user.ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !now.Before(expiresAt) {
+		return nil, user.ErrInvalidVerificationToken
+	}
+	return &v, nil
+}
+
+func (p *PostgresEmailVerificationRepo) LastSentAt(ctx context.Context, userID string) (time.Time, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT MAX(created_at) FROM email_verifications WHERE user_id = $1"
+	var sentAt sql.NullTime
+	if err := p.DB.QueryRowContext(ctx, query, userID).Scan(&sentAt); err != nil {
+		return time.Time{}, err
+	}
+	return sentAt.Time, nil
+}
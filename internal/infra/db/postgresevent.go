@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"html"
 	"log/slog"
 	"strings"
 	"time"
@@ -14,7 +15,7 @@ import (
 )
 
 type PostgresEventRepo struct {
-	DB      *sql.DB
+	DB      DBTX
 	TagRepo event.TagRepo
 }
 
@@ -22,12 +23,52 @@ func NewPostgresEventRepo(db *sql.DB, tr event.TagRepo) *PostgresEventRepo {
 	return &PostgresEventRepo{DB: db, TagRepo: tr}
 }
 
-func (p *PostgresEventRepo) FindByID(eventID string) (*event.Event, error) {
-	e, err := findEvent(p, eventID)
+const (
+	eventColumns         = "event_id, name, description, starts_at, ends_at, timezone, recurrence, latitude, longitude, fee, capacity, organizer_id, status"
+	eventWithTagsColumns = eventColumns + ", tags"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanEvent scans a row of eventColumns followed by any extra columns
+// selected by the query.
+func scanEvent(row rowScanner, extra ...any) (*event.Event, error) {
+	var e event.Event
+	dest := []any{
+		&e.EventID, &e.Name, &e.Description, &e.StartsAt, &e.EndsAt, &e.Timezone, &e.Recurrence,
+		&e.Latitude, &e.Longitude, &e.Fee, &e.Capacity, &e.OrganizerID, &e.Status,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// scanEventWithTags scans a row of eventWithTagsColumns followed by any
+// extra columns selected by the query.
+func scanEventWithTags(row rowScanner, extra ...any) (*event.Event, error) {
+	var e event.Event
+	var tags pq.StringArray
+	dest := []any{
+		&e.EventID, &e.Name, &e.Description, &e.StartsAt, &e.EndsAt, &e.Timezone, &e.Recurrence,
+		&e.Latitude, &e.Longitude, &e.Fee, &e.Capacity, &e.OrganizerID, &e.Status,
+		&tags,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	e.Tags = []string(tags)
+	return &e, nil
+}
+
+func (p *PostgresEventRepo) FindByID(ctx context.Context, eventID string) (*event.Event, error) {
+	e, err := findEvent(ctx, p, eventID)
 	if err != nil {
 		return nil, err
 	}
-	tags, err := findTagNames(p, eventID)
+	tags, err := findTagNames(ctx, p, eventID)
 	if err != nil {
 		return nil, err
 	}
@@ -35,8 +76,8 @@ func (p *PostgresEventRepo) FindByID(eventID string) (*event.Event, error) {
 	return e, nil
 }
 
-func findTagNames(p *PostgresEventRepo, eventID string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func findTagNames(ctx context.Context, p *PostgresEventRepo, eventID string) ([]string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 	query := "SELECT t.name FROM event_tag et INNER JOIN tags t ON et.tag_id = t.tag_id WHERE et.event_id = $1"
 
@@ -68,39 +109,56 @@ func findTagNames(p *PostgresEventRepo, eventID string) ([]string, error) {
 	return tagNames, nil
 }
 
-func findEvent(p *PostgresEventRepo, eventID string) (*event.Event, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func findEvent(ctx context.Context, p *PostgresEventRepo, eventID string) (*event.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
-	query := "SELECT event_id, name, description, date, latitude, longitude, fee, organizer_id FROM events WHERE event_id = $1"
-	rows, err := p.DB.QueryContext(ctx, query, eventID)
+	eid, err := uuid.Parse(eventID)
 	if err != nil {
-		return nil, err
+		// no event can have a malformed ID
+		return nil, event.ErrEventNotFound
 	}
 
-	rows.Next()
-	var e event.Event
-	if err := rows.Scan(&e.EventID, &e.Name, &e.Description, &e.Date, &e.Latitude, &e.Longitude, &e.Fee, &e.OrganizerID); err != nil {
+	query := "SELECT " + eventColumns + " FROM events WHERE event_id = $1 AND deleted_at IS NULL"
+	rows, err := p.DB.QueryContext(ctx, query, eid)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &e, nil
+	if !rows.Next() {
+		return nil, event.ErrEventNotFound
+	}
+	return scanEvent(rows)
 }
 
-func (p *PostgresEventRepo) Save(e *event.Event) error {
-	err := saveEvent(e, p)
+// Save inserts the event and its tags in one transaction, so an unknown tag
+// leaves nothing behind.
+func (p *PostgresEventRepo) Save(ctx context.Context, e *event.Event) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := begin(ctx, p.DB, nil)
 	if err != nil {
 		return err
 	}
-	return saveEventTag(e, p)
+	defer tx.Rollback()
+
+	if err := saveEvent(ctx, tx, e); err != nil {
+		return err
+	}
+	if err := p.saveEventTags(ctx, tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func saveEventTag(e *event.Event, p *PostgresEventRepo) error {
+func (p *PostgresEventRepo) saveEventTags(ctx context.Context, tx DBTX, e *event.Event) error {
 	eventID, err := uuid.Parse(e.EventID)
 	if err != nil {
 		return err
 	}
 	for _, tag := range e.Tags {
-		t, err := p.TagRepo.FindByName(tag)
+		t, err := p.TagRepo.FindByName(ctx, tag)
 		if err != nil {
 			return err
 		}
@@ -109,7 +167,7 @@ func saveEventTag(e *event.Event, p *PostgresEventRepo) error {
 		}
 
 		query := "INSERT INTO event_tag (event_id, tag_id) VALUES ($1, $2)"
-		_, err = p.DB.Exec(query, eventID, t.TagID)
+		_, err = tx.ExecContext(ctx, query, eventID, t.TagID)
 		if err != nil {
 			return err
 		}
@@ -117,10 +175,10 @@ func saveEventTag(e *event.Event, p *PostgresEventRepo) error {
 	return nil
 }
 
-func saveEvent(e *event.Event, p *PostgresEventRepo) error {
+func saveEvent(ctx context.Context, tx DBTX, e *event.Event) error {
 	query := "INSERT INTO events" +
-		"(event_id, name, description, date, latitude, longitude, fee, organizer_id)" +
-		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+		"(event_id, name, description, starts_at, ends_at, timezone, recurrence, latitude, longitude, fee, capacity, organizer_id, status)" +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)"
 
 	eventID, err := uuid.Parse(e.EventID)
 	if err != nil {
@@ -131,24 +189,120 @@ func saveEvent(e *event.Event, p *PostgresEventRepo) error {
 	if err != nil {
 		return err
 	}
-	_, err = p.DB.Exec(
+	_, err = tx.ExecContext(
+		ctx,
 		query,
 		eventID,
 		e.Name,
 		e.Description,
-		e.Date,
+		e.StartsAt,
+		e.EndsAt,
+		e.Timezone,
+		e.Recurrence,
 		e.Latitude,
 		e.Longitude,
 		e.Fee,
+		e.Capacity,
 		organizerID,
+		e.Status,
 	)
 	return err
 }
 
-func (p *PostgresEventRepo) FindAll() ([]*event.Event, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+func (p *PostgresEventRepo) Update(ctx context.Context, e *event.Event) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eventID, err := uuid.Parse(e.EventID)
+	if err != nil {
+		return err
+	}
+
+	tagIDs := make([]int64, 0, len(e.Tags))
+	for _, tag := range e.Tags {
+		t, err := p.TagRepo.FindByName(ctx, tag)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return sql.ErrNoRows
+		}
+		tagIDs = append(tagIDs, t.TagID)
+	}
+
+	tx, err := begin(ctx, p.DB, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE events
+SET name = $1, description = $2, starts_at = $3, ends_at = $4, timezone = $5, recurrence = $6,
+    latitude = $7, longitude = $8, fee = $9, capacity = $10, status = $11
+WHERE event_id = $12 AND deleted_at IS NULL`
+	res, err := tx.ExecContext(ctx, query, e.Name, e.Description, e.StartsAt, e.EndsAt, e.Timezone, e.Recurrence,
+		e.Latitude, e.Longitude, e.Fee, e.Capacity, e.Status, eventID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return event.ErrEventNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM event_tag WHERE event_id = $1", eventID); err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO event_tag (event_id, tag_id) VALUES ($1, $2)", eventID, tagID); err != nil {
+			return err
+		}
+	}
+
+	if err := dropStaleOccurrences(ctx, tx, e); err != nil {
+		return err
+	}
+
+	// a raised or removed capacity gives seats to the waitlist
+	if e.Status == event.StatusPublished {
+		var capacity sql.NullInt64
+		if e.Capacity != nil {
+			capacity = sql.NullInt64{Int64: int64(*e.Capacity), Valid: true}
+		}
+		if err := fillFromWaitlist(ctx, tx, eventID, capacity); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (p *PostgresEventRepo) UpdateStatus(ctx context.Context, eventID string, status event.Status) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.DB.ExecContext(ctx, "UPDATE events SET status = $1 WHERE event_id = $2 AND deleted_at IS NULL", status, eid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return event.ErrEventNotFound
+	}
+	return nil
+}
+
+func (p *PostgresEventRepo) FindAll(ctx context.Context) ([]*event.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
-	query := "SELECT event_id, name, description, date, latitude, longitude, fee, organizer_id, tags FROM find_event_with_tags"
+	query := "SELECT " + eventWithTagsColumns + " FROM find_event_with_tags"
 	rows, err := p.DB.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -161,13 +315,11 @@ func (p *PostgresEventRepo) FindAll() ([]*event.Event, error) {
 
 	var events []*event.Event
 	for rows.Next() {
-		var e event.Event
-		var tags pq.StringArray
-		if err := rows.Scan(&e.EventID, &e.Name, &e.Description, &e.Date, &e.Latitude, &e.Longitude, &e.Fee, &e.OrganizerID, &tags); err != nil {
+		e, err := scanEventWithTags(rows)
+		if err != nil {
 			return nil, err
 		}
-		e.Tags = []string(tags)
-		events = append(events, &e)
+		events = append(events, e)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -176,8 +328,12 @@ func (p *PostgresEventRepo) FindAll() ([]*event.Event, error) {
 	return events, nil
 }
 
-func (p *PostgresEventRepo) RegisterAttendance(userID, eventID string) error {
-	query := "INSERT INTO attendance (user_id, event_id) VALUES ($1, $2)"
+// RegisterAttendance locks the event row for the duration of the transaction,
+// so concurrent registrations for the same event are serialized and the
+// capacity check cannot be raced past.
+func (p *PostgresEventRepo) RegisterAttendance(ctx context.Context, userID, eventID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	uid, err := uuid.Parse(userID)
 	if err != nil {
@@ -189,21 +345,121 @@ func (p *PostgresEventRepo) RegisterAttendance(userID, eventID string) error {
 		return err
 	}
 
-	_, err = p.DB.Exec(query, uid, eid)
-	return err
+	tx, err := begin(ctx, p.DB, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	capacity, err := lockOpenEvent(ctx, tx, eid)
+	if err != nil {
+		return err
+	}
+
+	if attending, err := isAttending(ctx, tx, uid, eid); err != nil {
+		return err
+	} else if attending {
+		return event.ErrAlreadyRegistered
+	}
+
+	free, err := hasFreeSeat(ctx, tx, eid, capacity)
+	if err != nil {
+		return err
+	}
+	if !free {
+		return event.ErrEventFull
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO attendance (user_id, event_id) VALUES ($1, $2)", uid, eid); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM waitlist WHERE user_id = $1 AND event_id = $2", uid, eid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockEvent takes a row lock on the event and returns its capacity and
+// status.
+func lockEvent(ctx context.Context, tx DBTX, eid uuid.UUID) (sql.NullInt64, event.Status, error) {
+	var capacity sql.NullInt64
+	var status event.Status
+	err := tx.QueryRowContext(ctx, "SELECT capacity, status FROM events WHERE event_id = $1 AND deleted_at IS NULL FOR UPDATE", eid).Scan(&capacity, &status)
+	if err == sql.ErrNoRows {
+		return capacity, status, event.ErrEventNotFound
+	}
+	return capacity, status, err
 }
 
-func (p *PostgresEventRepo) IsUserAttending(userID string, eventID string) bool {
-	query := "SELECT EXISTS (SELECT 1 FROM attendance WHERE attendance.user_id = $1 AND attendance.event_id = $2)"
+// lockOpenEvent is lockEvent for registrations: it fails if the event does
+// not accept them.
+func lockOpenEvent(ctx context.Context, tx DBTX, eid uuid.UUID) (sql.NullInt64, error) {
+	capacity, status, err := lockEvent(ctx, tx, eid)
+	if err != nil {
+		return capacity, err
+	}
+	return capacity, status.AcceptsRegistrations()
+}
+
+func isAttending(ctx context.Context, tx DBTX, uid, eid uuid.UUID) (bool, error) {
 	var exists bool
-	err := p.DB.QueryRow(query, userID, eventID).Scan(&exists)
+	query := "SELECT EXISTS (SELECT 1 FROM attendance WHERE user_id = $1 AND event_id = $2)"
+	err := tx.QueryRowContext(ctx, query, uid, eid).Scan(&exists)
+	return exists, err
+}
+
+// hasFreeSeat reports whether one more user can register for the whole
+// event. The caller must hold the event row lock.
+func hasFreeSeat(ctx context.Context, tx DBTX, eid uuid.UUID, capacity sql.NullInt64) (bool, error) {
+	if !capacity.Valid {
+		return true, nil
+	}
+	taken, err := seatsTaken(ctx, tx, eid)
+	if err != nil {
+		return false, err
+	}
+	return int64(taken) < capacity.Int64, nil
+}
+
+// seatsTaken counts the seats counting towards the event's capacity. A seat
+// in a series is a seat at each of its occurrences, so the upcoming
+// occurrence with the most single-occurrence registrations decides.
+// Registrations for cancelled occurrences are kept but take no seat.
+func seatsTaken(ctx context.Context, tx DBTX, eid uuid.UUID) (int, error) {
+	query := `SELECT COALESCE((SELECT count FROM attendees_count WHERE event_id = $1), 0)
+  + COALESCE((SELECT MAX(n) FROM (
+      SELECT COUNT(*) AS n FROM occurrence_attendance a
+      WHERE a.event_id = $1 AND a.recurrence_id >= now()
+        AND NOT EXISTS (SELECT 1 FROM occurrence_exceptions x
+          WHERE x.event_id = a.event_id AND x.recurrence_id = a.recurrence_id AND x.cancelled)
+      GROUP BY a.recurrence_id) o), 0)`
+	var count int
+	if err := tx.QueryRowContext(ctx, query, eid).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// IsUserAttending reports whether the user is registered for the event or
+// for any of its occurrences.
+func (p *PostgresEventRepo) IsUserAttending(ctx context.Context, userID string, eventID string) bool {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT EXISTS (SELECT 1 FROM attendance WHERE attendance.user_id = $1 AND attendance.event_id = $2)
+  OR EXISTS (SELECT 1 FROM occurrence_attendance WHERE user_id = $1 AND event_id = $2)`
+	var exists bool
+	err := p.DB.QueryRowContext(ctx, query, userID, eventID).Scan(&exists)
 	if err != nil {
 		return false
 	}
 	return exists
 }
 
-func (p *PostgresEventRepo) GetAttendees(eventID string) ([]string, error) {
+func (p *PostgresEventRepo) GetAttendees(ctx context.Context, eventID string) ([]string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT user_id FROM attendance WHERE event_id = $1"
 
 	eid, err := uuid.Parse(eventID)
@@ -211,7 +467,7 @@ func (p *PostgresEventRepo) GetAttendees(eventID string) ([]string, error) {
 		return nil, err
 	}
 
-	rows, err := p.DB.Query(query, eid)
+	rows, err := p.DB.QueryContext(ctx, query, eid)
 	attendees := []string{}
 	if err != nil {
 		return nil, err
@@ -232,18 +488,107 @@ func (p *PostgresEventRepo) GetAttendees(eventID string) ([]string, error) {
 	return attendees, nil
 }
 
-func (p *PostgresEventRepo) GetAttendeesCount(eventID string) (int, error) {
+func (p *PostgresEventRepo) GetAttendeeProfiles(ctx context.Context, eventID string, pagination *event.Pagination) ([]event.Attendee, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	// users registered for single occurrences follow the series attendees
+	query := `SELECT u.user_id, u.name, u.email, a.registered_at, a.recurrence_id
+			  FROM (
+			    SELECT user_id, registered_at, NULL::timestamptz AS recurrence_id FROM attendance WHERE event_id = $1
+			    UNION ALL
+			    SELECT user_id, registered_at, recurrence_id FROM occurrence_attendance WHERE event_id = $1
+			  ) a
+			  INNER JOIN users u ON u.user_id = a.user_id
+			  ORDER BY a.recurrence_id ASC NULLS FIRST, a.registered_at ASC, u.user_id ASC`
+	args := []any{eid}
+
+	if pagination != nil && pagination.Limit() > 0 {
+		query += " LIMIT $2 OFFSET $3"
+		args = append(args, pagination.Limit(), pagination.Offset())
+	}
+
+	rows, err := p.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errClosingRows := rows.Close(); errClosingRows != nil {
+			slog.Warn("Rows was not closed")
+		}
+	}()
+
+	attendees := []event.Attendee{}
+	for rows.Next() {
+		var a event.Attendee
+		var recurrenceID sql.NullTime
+		if err := rows.Scan(&a.UserID, &a.Name, &a.Email, &a.RegisteredAt, &recurrenceID); err != nil {
+			return nil, err
+		}
+		if recurrenceID.Valid {
+			a.RecurrenceID = &recurrenceID.Time
+		}
+		attendees = append(attendees, a)
+	}
+	return attendees, rows.Err()
+}
+
+// CountAttendeeProfiles counts the rows of the roster GetAttendeeProfiles
+// pages through, single-occurrence registrations included.
+func (p *PostgresEventRepo) CountAttendeeProfiles(ctx context.Context, eventID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `SELECT (SELECT COUNT(*) FROM attendance WHERE event_id = $1)
+  + (SELECT COUNT(*) FROM occurrence_attendance WHERE event_id = $1)`
+	var count int
+	if err := p.DB.QueryRowContext(ctx, query, eid).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (p *PostgresEventRepo) GetAttendeesCount(ctx context.Context, eventID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT count FROM attendees_count c WHERE c.event_id = $1"
 
 	var count int
-	if err := p.DB.QueryRow(query, eventID).Scan(&count); err != nil {
+	if err := p.DB.QueryRowContext(ctx, query, eventID).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (p *PostgresEventRepo) RemoveAttendance(userID, eventID string) error {
-	query := "DELETE FROM attendance WHERE user_id = $1 AND event_id = $2"
+// GetSeatsTaken counts the seats taken the way registration does when it
+// enforces the capacity.
+func (p *PostgresEventRepo) GetSeatsTaken(ctx context.Context, eventID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return 0, event.ErrEventNotFound
+	}
+	return seatsTaken(ctx, p.DB, eid)
+}
+
+// RemoveAttendance deletes the user's registration and, if that frees a seat,
+// promotes the first user on the waitlist in the same transaction.
+func (p *PostgresEventRepo) RemoveAttendance(ctx context.Context, userID, eventID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	uid, err := uuid.Parse(userID)
 	if err != nil {
@@ -255,22 +600,50 @@ func (p *PostgresEventRepo) RemoveAttendance(userID, eventID string) error {
 		return err
 	}
 
-	_, err = p.DB.Exec(query, uid, eid)
-	return err
+	tx, err := begin(ctx, p.DB, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	capacity, status, err := lockEvent(ctx, tx, eid)
+	if err == event.ErrEventNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM attendance WHERE user_id = $1 AND event_id = $2", uid, eid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n > 0 && status == event.StatusPublished {
+		if err := promoteFromWaitlist(ctx, tx, eid, capacity); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (p *PostgresEventRepo) FindAllByTags(tagNames []string) ([]*event.Event, error) {
+func (p *PostgresEventRepo) FindAllByTags(ctx context.Context, tagNames []string) ([]*event.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	if len(tagNames) == 0 {
 		return []*event.Event{}, nil
 	}
 
 	query := `
-SELECT event_id, name, description, date, latitude, longitude, fee, organizer_id, tags
+SELECT ` + eventWithTagsColumns + `
 FROM find_event_with_tags
 WHERE tags && $1::text[];
 `
 
-	rows, err := p.DB.Query(query, pq.Array(tagNames))
+	rows, err := p.DB.QueryContext(ctx, query, pq.Array(tagNames))
 	if err != nil {
 		return nil, err
 	}
@@ -278,79 +651,174 @@ WHERE tags && $1::text[];
 
 	var events []*event.Event
 	for rows.Next() {
-		var e event.Event
-		var tags pq.StringArray
-		if err := rows.Scan(
-			&e.EventID, &e.Name, &e.Description, &e.Date,
-			&e.Latitude, &e.Longitude, &e.Fee, &e.OrganizerID,
-			&tags,
-		); err != nil {
+		e, err := scanEventWithTags(rows)
+		if err != nil {
 			return nil, err
 		}
-		e.Tags = []string(tags)
-		events = append(events, &e)
+		events = append(events, e)
 	}
 	return events, rows.Err()
 }
 
-func (p *PostgresEventRepo) FindAllWithFilters(filter *event.EventFilter) ([]*event.Event, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+// eventQuery holds the parts of a find_event_with_tags query built from an
+// event.EventFilter. The first whereArgs args belong to the WHERE clause, so
+// the same filter can back both the listing and its count.
+type eventQuery struct {
+	from       string
+	columns    string
+	conditions []string
+	args       []any
+	whereArgs  int
+	orderBy    string
+	distance   bool
+	search     bool
+	expanded   bool
+}
 
-	query := `
-SELECT event_id, name, description, date, latitude, longitude, fee, organizer_id, tags
-FROM find_event_with_tags
-`
-	var (
-		args       []any
-		conditions []string
-		argIndex   = 1
-	)
+// arg adds a query argument and returns its placeholder.
+func (q *eventQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
 
-	if filter != nil {
-		if len(filter.Tags) > 0 {
-			conditions = append(conditions, fmt.Sprintf("tags && $%d::text[]", argIndex))
-			args = append(args, pq.Array(filter.Tags))
-			argIndex++
-		}
+func (q *eventQuery) where() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
 
-		if filter.DateFrom != nil {
-			conditions = append(conditions, fmt.Sprintf(`date >= $%d`, argIndex))
-			args = append(args, *filter.DateFrom)
-			argIndex++
-		}
-		if filter.DateTo != nil {
-			conditions = append(conditions, fmt.Sprintf(`date <= $%d`, argIndex))
-			args = append(args, *filter.DateTo)
-			argIndex++
-		}
+// buildEventQuery builds the query for the filter. Listings with a date
+// window select from the given occurrences of recurring events instead of
+// the series themselves.
+func buildEventQuery(filter *event.EventFilter, occurrences []eventOccurrence) *eventQuery {
+	q := &eventQuery{
+		from:    "find_event_with_tags",
+		columns: eventWithTagsColumns,
+		orderBy: "starts_at ASC, event_id ASC",
+	}
+	if filter == nil {
+		return q
+	}
 
-		if filter.MinFee != nil {
-			conditions = append(conditions, fmt.Sprintf(`fee >= $%d`, argIndex))
-			args = append(args, *filter.MinFee)
-			argIndex++
-		}
-		if filter.MaxFee != nil {
-			conditions = append(conditions, fmt.Sprintf(`fee <= $%d`, argIndex))
-			args = append(args, *filter.MaxFee)
-			argIndex++
-		}
+	if _, _, ok := filter.OccurrenceWindow(); ok {
+		q.from = q.expandedEventsFrom(occurrences)
+		q.expanded = true
+	}
 
-		if len(conditions) > 0 {
-			query += " WHERE " + strings.Join(conditions, " AND ")
+	var distance, tsquery string
+
+	if filter.Near != nil && filter.RadiusKm != nil {
+		distance = haversineSQL(q.arg(filter.Near.Latitude), q.arg(filter.Near.Longitude))
+		box := filter.Near.BoundingBox(*filter.RadiusKm)
+		q.conditions = append(q.conditions,
+			fmt.Sprintf("latitude BETWEEN %s AND %s", q.arg(box.MinLat), q.arg(box.MaxLat)),
+			fmt.Sprintf("longitude BETWEEN %s AND %s", q.arg(box.MinLng), q.arg(box.MaxLng)),
+			fmt.Sprintf("%s <= %s", distance, q.arg(*filter.RadiusKm)),
+		)
+	}
+
+	if filter.Query != "" {
+		tsquery = fmt.Sprintf("websearch_to_tsquery('simple', %s)", q.arg(filter.Query))
+		q.conditions = append(q.conditions, "search_vector @@ "+tsquery)
+	}
+
+	if len(filter.Tags) > 0 {
+		q.conditions = append(q.conditions, fmt.Sprintf("tags && %s::text[]", q.arg(pq.Array(filter.Tags))))
+	}
+
+	// An event matches the date window if it overlaps it at all.
+	if filter.DateFrom != nil {
+		q.conditions = append(q.conditions, "ends_at > "+q.arg(*filter.DateFrom))
+	}
+	if filter.DateTo != nil {
+		q.conditions = append(q.conditions, "starts_at <= "+q.arg(*filter.DateTo))
+	}
+
+	if filter.MinFee != nil {
+		q.conditions = append(q.conditions, "fee >= "+q.arg(*filter.MinFee))
+	}
+	if filter.MaxFee != nil {
+		q.conditions = append(q.conditions, "fee <= "+q.arg(*filter.MaxFee))
+	}
+
+	q.whereArgs = len(q.args)
+
+	if filter.Near != nil {
+		if distance == "" {
+			distance = haversineSQL(q.arg(filter.Near.Latitude), q.arg(filter.Near.Longitude))
 		}
+		q.columns += ", " + distance + " AS distance_km"
+		q.distance = true
+	}
+
+	if tsquery != "" {
+		q.columns += fmt.Sprintf(`, ts_rank(search_vector, %[1]s) AS rank,
+  ts_headline('simple', COALESCE(NULLIF(description, ''), name), %[1]s, '%[2]s') AS headline`,
+			tsquery, headlineOptions)
+		q.search = true
+	}
 
-		query += " ORDER BY date ASC"
+	if q.expanded {
+		q.columns += ", recurrence_id"
+	}
+
+	if filter.Sort == "" && q.search {
+		q.orderBy = "rank DESC, " + q.orderBy
+	} else if orderBy, ok := sortOrderSQL[filter.Sort]; ok {
+		q.orderBy = orderBy
+	}
+
+	return q
+}
 
-		if filter.Pagination != nil && filter.Pagination.Limit() > 0 {
-			query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-			args = append(args, filter.Pagination.Limit(), filter.Pagination.Offset())
+// attendeesCountSQL is the number of attendees of the current row of
+// find_event_with_tags.
+const attendeesCountSQL = "(SELECT ac.count FROM attendees_count ac WHERE ac.event_id = find_event_with_tags.event_id)"
+
+// sortOrderSQL maps every event.SortOrder to its ORDER BY clause. Each clause
+// ends with a unique key so that pagination is stable.
+var sortOrderSQL = map[event.SortOrder]string{
+	event.SortDateAsc:    "starts_at ASC, event_id ASC",
+	event.SortDateDesc:   "starts_at DESC, event_id DESC",
+	event.SortFeeAsc:     "fee ASC, starts_at ASC, event_id ASC",
+	event.SortFeeDesc:    "fee DESC, starts_at ASC, event_id ASC",
+	event.SortPopularity: attendeesCountSQL + " DESC, starts_at ASC, event_id ASC",
+	event.SortNewest:     "created_at DESC, event_id ASC",
+	event.SortDistance:   "distance_km ASC, starts_at ASC, event_id ASC",
+}
+
+func (p *PostgresEventRepo) FindAllWithFilters(ctx context.Context, filter *event.EventFilter) ([]*event.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	occurrences, err := p.expandOccurrences(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	q := buildEventQuery(filter, occurrences)
+
+	var limit string
+	if filter != nil && filter.Keyset != nil {
+		if after := filter.Keyset.After; after != nil {
+			op := ">"
+			if filter.Sort == event.SortDateDesc {
+				op = "<"
+			}
+			q.conditions = append(q.conditions,
+				fmt.Sprintf("(starts_at, event_id) %s (%s, %s)", op, q.arg(after.StartsAt), q.arg(after.EventID)))
 		}
-	} else {
-		query += " ORDER BY date ASC"
+		limit = " LIMIT " + q.arg(filter.Keyset.Limit)
+	} else if filter != nil && filter.Pagination != nil && filter.Pagination.Limit() > 0 {
+		limit = fmt.Sprintf(" LIMIT %s OFFSET %s", q.arg(filter.Pagination.Limit()), q.arg(filter.Pagination.Offset()))
 	}
 
-	rows, err := p.DB.QueryContext(ctx, query, args...)
+	query := `
+SELECT ` + q.columns + `
+FROM ` + q.from + `
+` + q.where() + " ORDER BY " + q.orderBy + limit
+
+	rows, err := p.DB.QueryContext(ctx, query, q.args...)
 	if err != nil {
 		return nil, err
 	}
@@ -358,26 +826,89 @@ FROM find_event_with_tags
 
 	var events []*event.Event
 	for rows.Next() {
-		var e event.Event
-		var tags pq.StringArray
-
-		if err := rows.Scan(
-			&e.EventID, &e.Name, &e.Description, &e.Date,
-			&e.Latitude, &e.Longitude, &e.Fee, &e.OrganizerID,
-			&tags,
-		); err != nil {
+		var (
+			extra        []any
+			dist         sql.NullFloat64
+			rank         sql.NullFloat64
+			headline     sql.NullString
+			recurrenceID sql.NullTime
+		)
+		if q.distance {
+			extra = append(extra, &dist)
+		}
+		if q.search {
+			extra = append(extra, &rank, &headline)
+		}
+		if q.expanded {
+			extra = append(extra, &recurrenceID)
+		}
+		e, err := scanEventWithTags(rows, extra...)
+		if err != nil {
 			return nil, err
 		}
-
-		e.Tags = []string(tags)
-		events = append(events, &e)
+		if dist.Valid {
+			e.DistanceKm = &dist.Float64
+		}
+		if rank.Valid {
+			r := float32(rank.Float64)
+			e.SearchRank = &r
+			e.Headline = headlineHTML(headline.String)
+		}
+		if recurrenceID.Valid {
+			e.RecurrenceID = &recurrenceID.Time
+		}
+		events = append(events, e)
 	}
 
 	return events, rows.Err()
 }
 
-func (p *PostgresEventRepo) FindByOrganizer(userID string, pagination *event.Pagination) ([]*event.Event, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func (p *PostgresEventRepo) CountWithFilters(ctx context.Context, filter *event.EventFilter) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	occurrences, err := p.expandOccurrences(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	q := buildEventQuery(filter, occurrences)
+	query := "SELECT COUNT(*) FROM " + q.from + q.where()
+
+	var count int
+	err = p.DB.QueryRowContext(ctx, query, q.args[:q.whereArgs]...).Scan(&count)
+	return count, err
+}
+
+// Matches in ts_headline snippets are marked with control characters rather
+// than tags, so the organizer's text can be escaped before they become <b>.
+const (
+	headlineStartSel = "\x02"
+	headlineStopSel  = "\x03"
+)
+
+// headlineOptions configures the ts_headline snippets returned by text
+// searches.
+const headlineOptions = "StartSel=" + headlineStartSel + ", StopSel=" + headlineStopSel +
+	", MaxWords=25, MinWords=10, MaxFragments=2"
+
+// headlineHTML turns a ts_headline snippet into HTML: the text is escaped
+// and the matches are wrapped in <b></b>.
+func headlineHTML(headline string) string {
+	return strings.NewReplacer(headlineStartSel, "<b>", headlineStopSel, "</b>").Replace(html.EscapeString(headline))
+}
+
+// haversineSQL returns an expression computing the great-circle distance in
+// kilometres between the row's coordinates and the point given by the lat
+// and lng placeholders.
+func haversineSQL(lat, lng string) string {
+	return fmt.Sprintf(`(2 * %[3]g * asin(sqrt(least(1,
+  power(sin(radians(latitude - %[1]s::float8) / 2), 2) +
+  cos(radians(%[1]s::float8)) * cos(radians(latitude)) *
+  power(sin(radians(longitude - %[2]s::float8) / 2), 2)))))`, lat, lng, event.EarthRadiusKm)
+}
+
+func (p *PostgresEventRepo) FindByOrganizer(ctx context.Context, userID string, pagination *event.Pagination) ([]*event.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 
 	uid, err := uuid.Parse(userID)
@@ -385,8 +916,8 @@ func (p *PostgresEventRepo) FindByOrganizer(userID string, pagination *event.Pag
 		return nil, err
 	}
 
-	query := `SELECT event_id, name, description, date, latitude, longitude, fee, organizer_id 
-			  FROM events WHERE organizer_id = $1 ORDER BY date ASC`
+	query := `SELECT ` + eventColumns + `
+			  FROM events WHERE organizer_id = $1 AND deleted_at IS NULL ORDER BY starts_at ASC, event_id ASC`
 	args := []any{uid}
 
 	if pagination != nil && pagination.Limit() > 0 {
@@ -406,18 +937,18 @@ func (p *PostgresEventRepo) FindByOrganizer(userID string, pagination *event.Pag
 
 	var events []*event.Event
 	for rows.Next() {
-		var e event.Event
-		if err := rows.Scan(&e.EventID, &e.Name, &e.Description, &e.Date, &e.Latitude, &e.Longitude, &e.Fee, &e.OrganizerID); err != nil {
+		e, err := scanEvent(rows)
+		if err != nil {
 			return nil, err
 		}
-		events = append(events, &e)
+		events = append(events, e)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
 	for _, e := range events {
-		tags, err := findTagNames(p, e.EventID)
+		tags, err := findTagNames(ctx, p, e.EventID)
 		if err != nil {
 			return nil, err
 		}
@@ -427,8 +958,26 @@ func (p *PostgresEventRepo) FindByOrganizer(userID string, pagination *event.Pag
 	return events, nil
 }
 
-func (p *PostgresEventRepo) FindAttendingEvents(userID string, pagination *event.Pagination) ([]*event.Event, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+// attendingEventsSQL selects eventColumns and recurrence_id of the events the
+// user $1 is registered for. Registrations for a single occurrence are
+// listed as that occurrence, rescheduled as its exception says; cancelled
+// occurrences are left out.
+const attendingEventsSQL = `(
+SELECT ` + eventColumns + `, NULL::timestamptz AS recurrence_id
+FROM events
+WHERE event_id IN (SELECT event_id FROM attendance WHERE user_id = $1) AND deleted_at IS NULL
+UNION ALL
+SELECT e.event_id, e.name, e.description,
+  COALESCE(x.starts_at, oa.recurrence_id), COALESCE(x.ends_at, oa.recurrence_id + (e.ends_at - e.starts_at)),
+  e.timezone, e.recurrence, e.latitude, e.longitude, e.fee, e.capacity, e.organizer_id, e.status, oa.recurrence_id
+FROM occurrence_attendance oa
+JOIN events e ON e.event_id = oa.event_id
+LEFT JOIN occurrence_exceptions x ON x.event_id = oa.event_id AND x.recurrence_id = oa.recurrence_id
+WHERE oa.user_id = $1 AND e.deleted_at IS NULL AND x.cancelled IS NOT TRUE
+) attending`
+
+func (p *PostgresEventRepo) FindAttendingEvents(ctx context.Context, userID string, pagination *event.Pagination) ([]*event.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 
 	uid, err := uuid.Parse(userID)
@@ -436,10 +985,8 @@ func (p *PostgresEventRepo) FindAttendingEvents(userID string, pagination *event
 		return nil, err
 	}
 
-	query := `SELECT e.event_id, e.name, e.description, e.date, e.latitude, e.longitude, e.fee, e.organizer_id 
-			  FROM events e
-			  INNER JOIN attendance a ON a.event_id = e.event_id
-			  WHERE a.user_id = $1 ORDER BY e.date ASC`
+	query := `SELECT ` + eventColumns + `, recurrence_id FROM ` + attendingEventsSQL + `
+			  ORDER BY starts_at ASC, event_id ASC`
 	args := []any{uid}
 
 	if pagination != nil && pagination.Limit() > 0 {
@@ -459,18 +1006,22 @@ func (p *PostgresEventRepo) FindAttendingEvents(userID string, pagination *event
 
 	var events []*event.Event
 	for rows.Next() {
-		var e event.Event
-		if err := rows.Scan(&e.EventID, &e.Name, &e.Description, &e.Date, &e.Latitude, &e.Longitude, &e.Fee, &e.OrganizerID); err != nil {
+		var recurrenceID sql.NullTime
+		e, err := scanEvent(rows, &recurrenceID)
+		if err != nil {
 			return nil, err
 		}
-		events = append(events, &e)
+		if recurrenceID.Valid {
+			e.RecurrenceID = &recurrenceID.Time
+		}
+		events = append(events, e)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
 	for _, e := range events {
-		tags, err := findTagNames(p, e.EventID)
+		tags, err := findTagNames(ctx, p, e.EventID)
 		if err != nil {
 			return nil, err
 		}
@@ -480,8 +1031,38 @@ func (p *PostgresEventRepo) FindAttendingEvents(userID string, pagination *event
 	return events, nil
 }
 
-func (p *PostgresEventRepo) Delete(eventID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func (p *PostgresEventRepo) CountByOrganizer(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = p.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM events WHERE organizer_id = $1 AND deleted_at IS NULL", uid).Scan(&count)
+	return count, err
+}
+
+func (p *PostgresEventRepo) CountAttendingEvents(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = p.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+attendingEventsSQL, uid).Scan(&count)
+	return count, err
+}
+
+// Delete soft-deletes the event. It is left out of every query until it is
+// restored, or purged by PurgeDeleted.
+func (p *PostgresEventRepo) Delete(ctx context.Context, eventID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 
 	eid, err := uuid.Parse(eventID)
@@ -489,18 +1070,83 @@ func (p *PostgresEventRepo) Delete(eventID string) error {
 		return err
 	}
 
-	_, err = p.DB.ExecContext(ctx, "DELETE FROM event_tag WHERE event_id = $1", eid)
+	res, err := p.DB.ExecContext(ctx, "UPDATE events SET deleted_at = now() WHERE event_id = $1 AND deleted_at IS NULL", eid)
 	if err != nil {
 		return err
 	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return event.ErrEventNotFound
+	}
+	return nil
+}
+
+// FindDeletedByID returns a soft-deleted event.
+func (p *PostgresEventRepo) FindDeletedByID(ctx context.Context, eventID string) (*event.Event, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, event.ErrEventNotFound
+	}
+
+	query := "SELECT " + eventColumns + " FROM events WHERE event_id = $1 AND deleted_at IS NOT NULL"
+	e, err := scanEvent(p.DB.QueryRowContext(ctx, query, eid))
+	if err == sql.ErrNoRows {
+		return nil, event.ErrEventNotFound
+	}
+	return e, err
+}
 
-	_, err = p.DB.ExecContext(ctx, "DELETE FROM attendance WHERE event_id = $1", eid)
+func (p *PostgresEventRepo) Restore(ctx context.Context, eventID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eid, err := uuid.Parse(eventID)
 	if err != nil {
 		return err
 	}
 
-	_, err = p.DB.ExecContext(ctx, "DELETE FROM events WHERE event_id = $1", eid)
-	return err
+	res, err := p.DB.ExecContext(ctx, "UPDATE events SET deleted_at = NULL WHERE event_id = $1 AND deleted_at IS NOT NULL", eid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return event.ErrEventNotFound
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes the events soft-deleted before the given
+// time, together with their tags. Attendance, waitlists and occurrences are
+// removed by their foreign keys. It returns the number of events removed.
+func (p *PostgresEventRepo) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := begin(ctx, p.DB, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM event_tag WHERE event_id IN (SELECT event_id FROM events WHERE deleted_at < $1)"
+	if _, err := tx.ExecContext(ctx, query, before); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM events WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), tx.Commit()
 }
 
 var _ event.EventRepo = &PostgresEventRepo{}
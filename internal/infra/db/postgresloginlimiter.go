@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+)
+
+// PostgresLoginLimiter keeps failed logins in the login_failures table, so
+// they survive restarts and are shared by all instances. Limiters with
+// different scopes, say one for IPs and one for emails, share the table.
+type PostgresLoginLimiter struct {
+	DB     DBTX
+	Scope  string
+	Policy security.LockoutPolicy
+}
+
+func NewPostgresLoginLimiter(db *sql.DB, scope string, policy security.LockoutPolicy) security.LoginLimiter {
+	return &PostgresLoginLimiter{DB: db, Scope: scope, Policy: policy}
+}
+
+var _ security.LoginLimiter = (*PostgresLoginLimiter)(nil)
+
+func (p *PostgresLoginLimiter) Wait(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT locked_until FROM login_failures WHERE scope = $1 AND key = $2"
+	var lockedUntil time.Time
+	err := p.DB.QueryRowContext(ctx, query, p.Scope, key).Scan(&lockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !now.Before(lockedUntil) {
+		return 0, nil
+	}
+	return lockedUntil.Sub(now), nil
+}
+
+func (p *PostgresLoginLimiter) Fail(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// failures past the window that no longer lock the key out start over
+	query := `
+		INSERT INTO login_failures (scope, key, failures, last_failure_at, locked_until)
+		VALUES ($1, $2, 1, $3, $3)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failures = CASE
+				WHEN login_failures.last_failure_at <= $4 AND login_failures.locked_until <= $3 THEN 1
+				ELSE login_failures.failures + 1
+			END,
+			last_failure_at = $3
+		RETURNING failures`
+	var count int
+	err := p.DB.QueryRowContext(ctx, query, p.Scope, key, now, now.Add(-p.Policy.Window)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	lockout := p.Policy.Lockout(count)
+	if lockout == 0 {
+		return 0, nil
+	}
+	// GREATEST keeps a concurrent, longer lockout in place
+	query = `
+		UPDATE login_failures SET locked_until = GREATEST(locked_until, $3)
+		WHERE scope = $1 AND key = $2`
+	if _, err := p.DB.ExecContext(ctx, query, p.Scope, key, now.Add(lockout)); err != nil {
+		return 0, err
+	}
+	return lockout, nil
+}
+
+func (p *PostgresLoginLimiter) Reset(ctx context.Context, key string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM login_failures WHERE scope = $1 AND key = $2", p.Scope, key)
+	return err
+}
+
+func (p *PostgresLoginLimiter) Purge(ctx context.Context, now time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		DELETE FROM login_failures
+		WHERE scope = $1 AND last_failure_at <= $2 AND locked_until <= $3`
+	res, err := p.DB.ExecContext(ctx, query, p.Scope, now.Add(-p.Policy.Window), now)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
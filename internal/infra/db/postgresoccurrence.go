@@ -0,0 +1,319 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/lib/pq"
+)
+
+// expandedEventsSQL stands in for find_event_with_tags in listings with a
+// date window: recurring events are replaced by their occurrences, passed in
+// as parallel arrays of event ids, recurrence ids, starts and ends. The
+// subquery keeps the view's name so the rest of the query is unchanged.
+const expandedEventsSQL = `(
+SELECT f.event_id, f.name, f.description,
+  COALESCE(o.starts_at, f.starts_at) AS starts_at, COALESCE(o.ends_at, f.ends_at) AS ends_at,
+  f.timezone, f.recurrence, f.latitude, f.longitude, f.fee, f.capacity, f.organizer_id, f.status, f.tags,
+  f.search_vector, f.created_at, o.recurrence_id
+FROM find_event_with_tags f
+LEFT JOIN unnest(%s::uuid[], %s::timestamptz[], %s::timestamptz[], %s::timestamptz[])
+  AS o(event_id, recurrence_id, starts_at, ends_at) ON o.event_id = f.event_id
+WHERE f.recurrence = '' OR o.event_id IS NOT NULL
+) find_event_with_tags`
+
+type eventOccurrence struct {
+	EventID string
+	event.Occurrence
+}
+
+// expandedEventsFrom returns expandedEventsSQL for the occurrences, adding
+// its arguments to q.
+func (q *eventQuery) expandedEventsFrom(occurrences []eventOccurrence) string {
+	ids := make([]string, len(occurrences))
+	recurrenceIDs := make([]string, len(occurrences))
+	starts := make([]string, len(occurrences))
+	ends := make([]string, len(occurrences))
+	for i, o := range occurrences {
+		ids[i] = o.EventID
+		recurrenceIDs[i] = o.RecurrenceID.Format(time.RFC3339Nano)
+		starts[i] = o.StartsAt.Format(time.RFC3339Nano)
+		ends[i] = o.EndsAt.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf(expandedEventsSQL,
+		q.arg(pq.Array(ids)), q.arg(pq.Array(recurrenceIDs)), q.arg(pq.Array(starts)), q.arg(pq.Array(ends)))
+}
+
+// expandOccurrences returns the occurrences of every recurring event in the
+// filter's date window, or nil if the filter has no window.
+func (p *PostgresEventRepo) expandOccurrences(ctx context.Context, filter *event.EventFilter) ([]eventOccurrence, error) {
+	from, to, ok := filter.OccurrenceWindow()
+	if !ok {
+		return nil, nil
+	}
+
+	rows, err := p.DB.QueryContext(ctx,
+		"SELECT "+eventColumns+" FROM events WHERE recurrence <> '' AND status <> 'draft' AND deleted_at IS NULL AND starts_at <= $1", to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []*event.Event
+	var ids []string
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, e)
+		ids = append(ids, e.EventID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	occurrences := []eventOccurrence{}
+	if len(series) == 0 {
+		return occurrences, nil
+	}
+
+	exceptions, err := findOccurrenceExceptions(ctx, p.DB, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range series {
+		expanded, err := e.Occurrences(from, to, exceptions[e.EventID])
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range expanded {
+			occurrences = append(occurrences, eventOccurrence{EventID: e.EventID, Occurrence: o})
+		}
+	}
+	return occurrences, nil
+}
+
+func findOccurrenceExceptions(ctx context.Context, db DBTX, eventIDs []string) (map[string][]event.OccurrenceException, error) {
+	query := `SELECT event_id, recurrence_id, cancelled, starts_at, ends_at
+FROM occurrence_exceptions WHERE event_id = ANY($1::uuid[])`
+	rows, err := db.QueryContext(ctx, query, pq.Array(eventIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exceptions := make(map[string][]event.OccurrenceException)
+	for rows.Next() {
+		var ex event.OccurrenceException
+		var startsAt, endsAt sql.NullTime
+		if err := rows.Scan(&ex.EventID, &ex.RecurrenceID, &ex.Cancelled, &startsAt, &endsAt); err != nil {
+			return nil, err
+		}
+		if startsAt.Valid {
+			ex.StartsAt = &startsAt.Time
+		}
+		if endsAt.Valid {
+			ex.EndsAt = &endsAt.Time
+		}
+		exceptions[ex.EventID] = append(exceptions[ex.EventID], ex)
+	}
+	return exceptions, rows.Err()
+}
+
+// dropStaleOccurrences deletes the exceptions of and registrations for
+// occurrences the event's rule no longer generates, after its rule or start
+// was changed.
+func dropStaleOccurrences(ctx context.Context, tx DBTX, e *event.Event) error {
+	query := `SELECT recurrence_id FROM occurrence_exceptions WHERE event_id = $1
+UNION SELECT recurrence_id FROM occurrence_attendance WHERE event_id = $1`
+	rows, err := tx.QueryContext(ctx, query, e.EventID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var stale []string
+	for rows.Next() {
+		var recurrenceID time.Time
+		if err := rows.Scan(&recurrenceID); err != nil {
+			return err
+		}
+		if _, err := e.Occurrence(recurrenceID, nil); err != nil {
+			stale = append(stale, recurrenceID.Format(time.RFC3339Nano))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	for _, table := range []string{"occurrence_exceptions", "occurrence_attendance"} {
+		query := "DELETE FROM " + table + " WHERE event_id = $1 AND recurrence_id = ANY($2::timestamptz[])"
+		if _, err := tx.ExecContext(ctx, query, e.EventID, pq.Array(stale)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PostgresEventRepo) FindOccurrenceExceptions(ctx context.Context, eventID string) ([]event.OccurrenceException, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, err
+	}
+	exceptions, err := findOccurrenceExceptions(ctx, p.DB, []string{eventID})
+	if err != nil {
+		return nil, err
+	}
+	return exceptions[eventID], nil
+}
+
+// SaveOccurrenceException stores the exception, replacing any earlier one for
+// the same occurrence. Cancelling the fullest occurrence frees a seat in the
+// series, so the waitlist is promoted in the same transaction.
+func (p *PostgresEventRepo) SaveOccurrenceException(ctx context.Context, ex *event.OccurrenceException) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eid, err := uuid.Parse(ex.EventID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := begin(ctx, p.DB, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	capacity, status, err := lockEvent(ctx, tx, eid)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO occurrence_exceptions (event_id, recurrence_id, cancelled, starts_at, ends_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (event_id, recurrence_id)
+DO UPDATE SET cancelled = EXCLUDED.cancelled, starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at`
+	if _, err := tx.ExecContext(ctx, query, eid, ex.RecurrenceID, ex.Cancelled, ex.StartsAt, ex.EndsAt); err != nil {
+		return err
+	}
+	if ex.Cancelled && status == event.StatusPublished {
+		if err := fillFromWaitlist(ctx, tx, eid, capacity); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// RegisterOccurrence registers the user for a single occurrence of a
+// recurring event. Users registered for the whole series take a seat at
+// every occurrence, so they count towards its capacity too.
+func (p *PostgresEventRepo) RegisterOccurrence(ctx context.Context, userID, eventID string, recurrenceID time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := begin(ctx, p.DB, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	capacity, err := lockOpenEvent(ctx, tx, eid)
+	if err != nil {
+		return err
+	}
+
+	var registered bool
+	query := `SELECT EXISTS (SELECT 1 FROM attendance WHERE user_id = $1 AND event_id = $2)
+  OR EXISTS (SELECT 1 FROM occurrence_attendance WHERE user_id = $1 AND event_id = $2 AND recurrence_id = $3)`
+	if err := tx.QueryRowContext(ctx, query, uid, eid, recurrenceID).Scan(&registered); err != nil {
+		return err
+	}
+	if registered {
+		return event.ErrAlreadyRegistered
+	}
+
+	if capacity.Valid {
+		var count int64
+		query := `SELECT (SELECT count FROM attendees_count WHERE event_id = $1)
+  + (SELECT COUNT(*) FROM occurrence_attendance a
+     WHERE a.event_id = $1 AND a.recurrence_id = $2
+       AND NOT EXISTS (SELECT 1 FROM occurrence_exceptions x
+         WHERE x.event_id = a.event_id AND x.recurrence_id = a.recurrence_id AND x.cancelled))`
+		if err := tx.QueryRowContext(ctx, query, eid, recurrenceID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= capacity.Int64 {
+			return event.ErrEventFull
+		}
+	}
+
+	query = "INSERT INTO occurrence_attendance (user_id, event_id, recurrence_id) VALUES ($1, $2, $3)"
+	if _, err := tx.ExecContext(ctx, query, uid, eid, recurrenceID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveOccurrenceAttendance deletes the user's registration for the
+// occurrence. Leaving the fullest occurrence frees a seat in the series, so
+// the waitlist is promoted in the same transaction.
+func (p *PostgresEventRepo) RemoveOccurrenceAttendance(ctx context.Context, userID, eventID string, recurrenceID time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := begin(ctx, p.DB, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	capacity, status, err := lockEvent(ctx, tx, eid)
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM occurrence_attendance WHERE user_id = $1 AND event_id = $2 AND recurrence_id = $3"
+	res, err := tx.ExecContext(ctx, query, uid, eid, recurrenceID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n > 0 && status == event.StatusPublished {
+		if err := fillFromWaitlist(ctx, tx, eid, capacity); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+type PostgresPasswordResetRepo struct {
+	DB DBTX
+}
+
+func NewPostgresPasswordResetRepo(db *sql.DB) user.PasswordResetRepo {
+	return &PostgresPasswordResetRepo{DB: db}
+}
+
+var _ user.PasswordResetRepo = (*PostgresPasswordResetRepo)(nil)
+
+func (p *PostgresPasswordResetRepo) Create(ctx context.Context, userID string, expiresAt time.Time) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// only the latest mail of the user works
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM password_resets WHERE user_id = $1", userID)
+	if err != nil {
+		return "", err
+	}
+
+	token := generateToken()
+	query := "INSERT INTO password_resets (token_hash, user_id, expires_at) VALUES ($1, $2, $3)"
+	if _, err := p.DB.ExecContext(ctx, query, hashToken(token), userID, expiresAt); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (p *PostgresPasswordResetRepo) Consume(ctx context.Context, token string, now time.Time) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM password_resets WHERE token_hash = $1 RETURNING user_id, expires_at"
+	var userID string
+	var expiresAt time.Time
+	err := p.DB.QueryRowContext(ctx, query, hashToken(token)).Scan(&userID, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", user.ErrInvalidResetToken
+	}
+	if err != nil {
+		return "", err
+	}
+	if !now.Before(expiresAt) {
+		return "", user.ErrInvalidResetToken
+	}
+	return userID, nil
+}
+
+func (p *PostgresPasswordResetRepo) LastSentAt(ctx context.Context, userID string) (time.Time, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT MAX(created_at) FROM password_resets WHERE user_id = $1"
+	var sentAt sql.NullTime
+	if err := p.DB.QueryRowContext(ctx, query, userID).Scan(&sentAt); err != nil {
+		return time.Time{}, err
+	}
+	return sentAt.Time, nil
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+type PostgresRefreshTokenRepo struct {
+	DB DBTX
+}
+
+func NewPostgresRefreshTokenRepo(db *sql.DB) user.RefreshTokenRepo {
+	return &PostgresRefreshTokenRepo{DB: db}
+}
+
+var _ user.RefreshTokenRepo = (*PostgresRefreshTokenRepo)(nil)
+
+func (p *PostgresRefreshTokenRepo) Create(ctx context.Context, userID string, expiresAt time.Time) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// the user's expired tokens are dropped here, so abandoned ones do not
+	// pile up
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM refresh_tokens WHERE user_id = $1 AND expires_at <= now()", userID)
+	if err != nil {
+		return "", err
+	}
+
+	token := generateToken()
+	query := "INSERT INTO refresh_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)"
+	if _, err := p.DB.ExecContext(ctx, query, hashToken(token), userID, expiresAt); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (p *PostgresRefreshTokenRepo) Consume(ctx context.Context, token string, now time.Time) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// deleting and reading in one statement lets only one of two concurrent
+	// uses of the token succeed
+	query := "DELETE FROM refresh_tokens WHERE token_hash = $1 RETURNING user_id, expires_at"
+	var userID string
+	var expiresAt time.Time
+	err := p.DB.QueryRowContext(ctx, query, hashToken(token)).Scan(&userID, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", user.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return "", err
+	}
+	if !now.Before(expiresAt) {
+		return "", user.ErrInvalidRefreshToken
+	}
+	return userID, nil
+}
+
+func (p *PostgresRefreshTokenRepo) Delete(ctx context.Context, token string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM refresh_tokens WHERE token_hash = $1", hashToken(token))
+	return err
+}
+
+func (p *PostgresRefreshTokenRepo) DeleteByUser(ctx context.Context, userID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM refresh_tokens WHERE user_id = $1", userID)
+	return err
+}
@@ -2,75 +2,158 @@ package db
 
 import (
 	"context"
-	"crypto/rand"
 	"database/sql"
-	"encoding/base64"
-	"io"
+	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kapiw04/convenly/internal/domain/user"
 )
 
 type PostgresSessionRepo struct {
-	DB       *sql.DB
+	DB       DBTX
 	UserRepo user.UserRepo
+	// Policy sets how long sessions last; the zero value means
+	// user.DefaultSessionPolicy.
+	Policy user.SessionPolicy
 }
 
-func (p *PostgresSessionRepo) Create(email string) (id string, err error) {
-	query := "INSERT INTO sessions (user_id, session_id) VALUES ($1, $2)"
-	user, err := p.UserRepo.FindByEmail(email)
+func (p *PostgresSessionRepo) policy() user.SessionPolicy {
+	if p.Policy == (user.SessionPolicy{}) {
+		return user.DefaultSessionPolicy
+	}
+	return p.Policy
+}
+
+func (p *PostgresSessionRepo) Create(ctx context.Context, email string, device user.Device) (*user.Session, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO sessions (session_id, user_id, token_hash, user_agent, ip, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	u, err := p.UserRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	sessionID := generateSessionID()
-	userID := user.UUID
-	_, err = p.DB.Exec(query, userID, sessionID)
+	s := p.policy().NewSession(generateToken(), u.UUID, device, time.Now())
+	_, err = p.DB.ExecContext(ctx, query,
+		s.ID, s.UserID, hashToken(s.Token), s.UserAgent, s.IP, s.CreatedAt, s.LastSeenAt, s.ExpiresAt)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return sessionID, nil
+	return s, nil
 }
 
-func (p *PostgresSessionRepo) Delete(sessionID string) error {
-	query := "DELETE FROM sessions WHERE session_id = $1"
-	_, err := p.DB.Exec(query, sessionID)
+func (p *PostgresSessionRepo) Delete(ctx context.Context, token string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM sessions WHERE token_hash = $1"
+	_, err := p.DB.ExecContext(ctx, query, hashToken(token))
 	return err
 }
 
-func (p *PostgresSessionRepo) Get(sessionID string) (user.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+func (p *PostgresSessionRepo) DeleteByID(ctx context.Context, userID string, id string) error {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
-	query := "SELECT user_id FROM sessions WHERE sessions.session_id = $1"
-	rows, err := p.DB.QueryContext(ctx, query, sessionID)
 
+	if _, err := uuid.Parse(id); err != nil {
+		return user.ErrSessionNotFound
+	}
+	res, err := p.DB.ExecContext(ctx, "DELETE FROM sessions WHERE session_id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
 	if err != nil {
-		return user.User{}, err
+		return err
 	}
-	var userID string
+	if n == 0 {
+		return user.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (p *PostgresSessionRepo) DeleteByUser(ctx context.Context, userID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM sessions WHERE user_id = $1"
+	_, err := p.DB.ExecContext(ctx, query, userID)
+	return err
+}
+
+func (p *PostgresSessionRepo) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	rows.Next()
-	if err := rows.Scan(&userID); err != nil {
-		return user.User{}, err
+	res, err := p.DB.ExecContext(ctx, "DELETE FROM sessions WHERE expires_at <= $1", now)
+	if err != nil {
+		return 0, err
 	}
-	user, err := p.UserRepo.FindByUUID(userID)
-	return *user, err
+	n, err := res.RowsAffected()
+	return int(n), err
 }
 
-var _ user.SessionRepo = (*PostgresSessionRepo)(nil)
+const sessionColumns = "session_id, user_id, user_agent, ip, created_at, last_seen_at, expires_at"
+
+func scanSession(row rowScanner, s *user.Session) error {
+	return row.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt)
+}
+
+func (p *PostgresSessionRepo) Get(ctx context.Context, token string) (*user.Session, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tokenHash := hashToken(token)
+	query := "SELECT " + sessionColumns + " FROM sessions WHERE token_hash = $1"
+	s := user.Session{Token: token}
+	err := scanSession(p.DB.QueryRowContext(ctx, query, tokenHash), &s)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, user.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
 
-func NewPostgresSessionRepo(db *sql.DB, userRepo user.UserRepo) user.SessionRepo {
-	return &PostgresSessionRepo{DB: db, UserRepo: userRepo}
+	if err := p.policy().Renew(&s, time.Now()); err != nil {
+		return nil, err
+	}
+	query = "UPDATE sessions SET last_seen_at = $2, expires_at = $3 WHERE token_hash = $1"
+	if _, err := p.DB.ExecContext(ctx, query, tokenHash, s.LastSeenAt, s.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
 }
 
-func generateSessionID() string {
-	id := make([]byte, 32)
+func (p *PostgresSessionRepo) ListByUser(ctx context.Context, userID string) ([]*user.Session, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	_, err := io.ReadFull(rand.Reader, id)
+	query := "SELECT " + sessionColumns + ` FROM sessions
+		WHERE user_id = $1 AND expires_at > $2
+		ORDER BY last_seen_at DESC`
+	rows, err := p.DB.QueryContext(ctx, query, userID, time.Now())
 	if err != nil {
-		panic("failed to generate session id")
+		return nil, err
 	}
+	defer rows.Close()
+
+	var sessions []*user.Session
+	for rows.Next() {
+		var s user.Session
+		if err := scanSession(rows, &s); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, rows.Err()
+}
+
+var _ user.SessionRepo = (*PostgresSessionRepo)(nil)
 
-	return base64.RawURLEncoding.EncodeToString(id)
+func NewPostgresSessionRepo(db *sql.DB, userRepo user.UserRepo, policy user.SessionPolicy) user.SessionRepo {
+	return &PostgresSessionRepo{DB: db, UserRepo: userRepo, Policy: policy}
 }
@@ -3,25 +3,24 @@ package db
 import (
 	"context"
 	"database/sql"
-	"time"
 
 	"github.com/kapiw04/convenly/internal/domain/event"
 )
 
 type PostgresTagRepo struct {
-	DB *sql.DB
+	DB DBTX
 }
 
 func NewPostgresTagRepo(db *sql.DB) *PostgresTagRepo {
 	repo := &PostgresTagRepo{DB: db}
-	if err := repo.SeedDefaults(); err != nil {
+	if err := repo.SeedDefaults(context.Background()); err != nil {
 		panic("failed to seed default tags: " + err.Error())
 	}
 	return repo
 }
 
-func (r *PostgresTagRepo) FindAll() ([]event.Tag, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func (r *PostgresTagRepo) FindAll(ctx context.Context) ([]event.Tag, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 
 	query := "SELECT tag_id, name FROM tags"
@@ -43,8 +42,8 @@ func (r *PostgresTagRepo) FindAll() ([]event.Tag, error) {
 	return tags, rows.Err()
 }
 
-func (r *PostgresTagRepo) FindByName(name string) (*event.Tag, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func (r *PostgresTagRepo) FindByName(ctx context.Context, name string) (*event.Tag, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 
 	query := "SELECT tag_id, name FROM tags WHERE name = $1"
@@ -61,8 +60,8 @@ func (r *PostgresTagRepo) FindByName(name string) (*event.Tag, error) {
 	return &t, nil
 }
 
-func (r *PostgresTagRepo) CreateIfNotExists(name string) (*event.Tag, error) {
-	existing, err := r.FindByName(name)
+func (r *PostgresTagRepo) CreateIfNotExists(ctx context.Context, name string) (*event.Tag, error) {
+	existing, err := r.FindByName(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +69,7 @@ func (r *PostgresTagRepo) CreateIfNotExists(name string) (*event.Tag, error) {
 		return existing, nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 
 	query := `
@@ -83,7 +82,7 @@ func (r *PostgresTagRepo) CreateIfNotExists(name string) (*event.Tag, error) {
 	var t event.Tag
 	err = row.Scan(&t.TagID, &t.Name)
 	if err != nil {
-		if existing, findErr := r.FindByName(name); findErr == nil && existing != nil {
+		if existing, findErr := r.FindByName(ctx, name); findErr == nil && existing != nil {
 			return existing, nil
 		}
 		return nil, err
@@ -92,9 +91,9 @@ func (r *PostgresTagRepo) CreateIfNotExists(name string) (*event.Tag, error) {
 	return &t, nil
 }
 
-func (r *PostgresTagRepo) SeedDefaults() error {
+func (r *PostgresTagRepo) SeedDefaults(ctx context.Context) error {
 	for _, tagName := range event.DefaultTagNames {
-		if _, err := r.CreateIfNotExists(tagName); err != nil {
+		if _, err := r.CreateIfNotExists(ctx, tagName); err != nil {
 			return err
 		}
 	}
@@ -6,12 +6,14 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/kapiw04/convenly/internal/domain/event"
 	"github.com/kapiw04/convenly/internal/domain/user"
 	"github.com/lib/pq"
 )
 
 type PostgresUserRepo struct {
-	DB *sql.DB
+	DB DBTX
 }
 
 func mapPgErr(err error) error {
@@ -42,10 +44,10 @@ func mapPgErr(err error) error {
 
 	return err
 }
-func (r *PostgresUserRepo) FindByEmail(email string) (*user.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func (r *PostgresUserRepo) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
-	query := "SELECT user_id, name, email, password_hash, role FROM users WHERE users.email = $1"
+	query := "SELECT user_id, name, email, password_hash, role, email_verified, tokens_valid_after FROM users WHERE users.email = $1"
 	rows, err := r.DB.QueryContext(ctx, query, email)
 	if err != nil {
 		return nil, err
@@ -53,32 +55,125 @@ func (r *PostgresUserRepo) FindByEmail(email string) (*user.User, error) {
 	defer rows.Close()
 
 	if !rows.Next() {
-		return nil, sql.ErrNoRows
+		return nil, user.ErrUserNotFound
 	}
 
 	var user user.User
-	if err := rows.Scan(&user.UUID, &user.Name, &user.Email, &user.PasswordHash, &user.Role); err != nil {
+	if err := scanUser(rows, &user); err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *PostgresUserRepo) Count() (int, error) {
+func (r *PostgresUserRepo) Count(ctx context.Context) (int, error) {
 	panic("unimplemented")
 }
 
-func (r *PostgresUserRepo) DeleteByUUID(uuid string) error {
-	panic("unimplemented")
+func (r *PostgresUserRepo) DeleteByUUID(ctx context.Context, userID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return user.ErrUserNotFound
+	}
+
+	tx, err := begin(ctx, r.DB, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// leave the waitlists first, so the user is not promoted into a seat
+	// they are giving up
+	if _, err := tx.ExecContext(ctx, "DELETE FROM waitlist WHERE user_id = $1", uid); err != nil {
+		return err
+	}
+	if err := releaseSeats(ctx, tx, uid); err != nil {
+		return err
+	}
+
+	// the hosted events go with the user through their foreign key, which
+	// takes attendance, waitlists and occurrences along; tags and sessions
+	// have to be removed by hand
+	query := "DELETE FROM event_tag WHERE event_id IN (SELECT event_id FROM events WHERE organizer_id = $1)"
+	if _, err := tx.ExecContext(ctx, query, uid); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM sessions WHERE user_id = $1", uid); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM users WHERE user_id = $1", uid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return user.ErrUserNotFound
+	}
+	return tx.Commit()
+}
+
+// releaseSeats removes the user's attendance of events and occurrences,
+// promoting waitlisted users of each published event into the freed seats,
+// as RemoveAttendance and RemoveOccurrenceAttendance do.
+func releaseSeats(ctx context.Context, tx DBTX, uid uuid.UUID) error {
+	// events are locked in a fixed order so that two deletions cannot
+	// deadlock
+	query := `SELECT event_id FROM attendance WHERE user_id = $1
+UNION SELECT event_id FROM occurrence_attendance WHERE user_id = $1
+ORDER BY event_id`
+	rows, err := tx.QueryContext(ctx, query, uid)
+	if err != nil {
+		return err
+	}
+	var eventIDs []uuid.UUID
+	for rows.Next() {
+		var eid uuid.UUID
+		if err := rows.Scan(&eid); err != nil {
+			rows.Close()
+			return err
+		}
+		eventIDs = append(eventIDs, eid)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, eid := range eventIDs {
+		capacity, status, err := lockEvent(ctx, tx, eid)
+		if errors.Is(err, event.ErrEventNotFound) {
+			// a deleted event; its attendance goes with the user
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, table := range []string{"attendance", "occurrence_attendance"} {
+			_, err = tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE user_id = $1 AND event_id = $2", uid, eid)
+			if err != nil {
+				return err
+			}
+		}
+		if status == event.StatusPublished {
+			if err := fillFromWaitlist(ctx, tx, eid, capacity); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-func (r *PostgresUserRepo) FindAll() ([]*user.User, error) {
+func (r *PostgresUserRepo) FindAll(ctx context.Context) ([]*user.User, error) {
 	panic("unimplemented")
 }
 
-func (r *PostgresUserRepo) FindByUUID(uuid string) (*user.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+func (r *PostgresUserRepo) FindByUUID(ctx context.Context, uuid string) (*user.User, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
-	query := "SELECT user_id, name, email, password_hash, role FROM users WHERE users.user_id = $1"
+	query := "SELECT user_id, name, email, password_hash, role, email_verified, tokens_valid_after FROM users WHERE users.user_id = $1"
 	rows, err := r.DB.QueryContext(ctx, query, uuid)
 	if err != nil {
 		return nil, err
@@ -86,30 +181,118 @@ func (r *PostgresUserRepo) FindByUUID(uuid string) (*user.User, error) {
 	defer rows.Close()
 
 	if !rows.Next() {
-		return nil, sql.ErrNoRows
+		return nil, user.ErrUserNotFound
 	}
 
 	var user user.User
-	if err := rows.Scan(&user.UUID, &user.Name, &user.Email, &user.PasswordHash, &user.Role); err != nil {
+	if err := scanUser(rows, &user); err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *PostgresUserRepo) Update(user *user.User) error {
-	email := string(user.Email)
-	query := "UPDATE users SET name=$1, email=$2, password_hash=$3, role=$4 WHERE user_id=$5"
-	_, err := r.DB.Exec(query, user.Name, email, user.PasswordHash, user.Role, user.UUID)
-	return mapPgErr(err)
+func (r *PostgresUserRepo) UpdateName(ctx context.Context, userID string, name string) error {
+	return r.updateUser(ctx, "UPDATE users SET name = $1 WHERE user_id = $2", name, userID)
+}
+
+func (r *PostgresUserRepo) SetPasswordHash(ctx context.Context, userID string, passwordHash string) error {
+	return r.updateUser(ctx, "UPDATE users SET password_hash = $1 WHERE user_id = $2", passwordHash, userID)
+}
+
+func (r *PostgresUserRepo) SetRole(ctx context.Context, userID string, role user.Role) error {
+	return r.updateUser(ctx, "UPDATE users SET role = $1 WHERE user_id = $2", role, userID)
+}
+
+func (r *PostgresUserRepo) MarkEmailVerified(ctx context.Context, userID string, email string) error {
+	return r.updateUser(ctx, "UPDATE users SET email = $1, email_verified = true WHERE user_id = $2", email, userID)
+}
+
+func (r *PostgresUserRepo) RevokeAccessTokens(ctx context.Context, userID string, at time.Time) error {
+	// never move it back, which would make revoked tokens valid again
+	query := "UPDATE users SET tokens_valid_after = GREATEST(tokens_valid_after, $1) WHERE user_id = $2"
+	return r.updateUser(ctx, query, at, userID)
+}
+
+// scanUser reads the columns user_id, name, email, password_hash, role,
+// email_verified and tokens_valid_after into u.
+func scanUser(row interface{ Scan(...any) error }, u *user.User) error {
+	var validAfter sql.NullTime
+	if err := row.Scan(&u.UUID, &u.Name, &u.Email, &u.PasswordHash, &u.Role, &u.EmailVerified, &validAfter); err != nil {
+		return err
+	}
+	u.TokensValidAfter = validAfter.Time
+	return nil
+}
+
+// updateUser runs an UPDATE of a single user, reporting ErrUserNotFound if
+// it matched no row.
+func (r *PostgresUserRepo) updateUser(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return mapPgErr(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return user.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *PostgresUserRepo) CalendarToken(ctx context.Context, userID string) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	token := generateToken()
+	query := `UPDATE users SET calendar_token_hash = COALESCE(calendar_token_hash, $1) WHERE user_id = $2
+RETURNING calendar_token_hash = $1`
+	var created bool
+	err := r.DB.QueryRowContext(ctx, query, hashToken(token), userID).Scan(&created)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", user.ErrUserNotFound
+	}
+	if err != nil || !created {
+		return "", err
+	}
+	return token, nil
+}
+
+func (r *PostgresUserRepo) RotateCalendarToken(ctx context.Context, userID string) (string, error) {
+	token := generateToken()
+	err := r.updateUser(ctx, "UPDATE users SET calendar_token_hash = $1 WHERE user_id = $2", hashToken(token), userID)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (r *PostgresUserRepo) FindByCalendarToken(ctx context.Context, token string) (*user.User, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	query := "SELECT user_id, name, email, password_hash, role, email_verified, tokens_valid_after FROM users WHERE calendar_token_hash = $1"
+	var u user.User
+	err := scanUser(r.DB.QueryRowContext(ctx, query, hashToken(token)), &u)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, user.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
 }
 
 func NewPostgresUserRepo(db *sql.DB) user.UserRepo {
 	return &PostgresUserRepo{DB: db}
 }
 
-func (r *PostgresUserRepo) Save(user *user.User) error {
-	query := "INSERT INTO users (name, email, password_hash, role) VALUES ($1, $2, $3, $4) RETURNING user_id"
-	err := r.DB.QueryRow(query, user.Name, user.Email, user.PasswordHash, user.Role).Scan(&user.UUID)
+func (r *PostgresUserRepo) Save(ctx context.Context, user *user.User) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO users (name, email, password_hash, role, email_verified) VALUES ($1, $2, $3, $4, $5) RETURNING user_id"
+	err := r.DB.QueryRowContext(ctx, query, user.Name, user.Email, user.PasswordHash, user.Role, user.EmailVerified).Scan(&user.UUID)
 	return mapPgErr(err)
 }
 
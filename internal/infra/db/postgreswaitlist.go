@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/lib/pq"
+)
+
+const waitlistPositionsQuery = `
+SELECT user_id, ROW_NUMBER() OVER (ORDER BY seq) AS position, joined_at
+FROM waitlist
+WHERE event_id = $1`
+
+// JoinWaitlist appends the user to the event's waitlist and returns their
+// position. If a seat was freed since the registration attempt and nobody is
+// queued for it, the user is registered right away and 0 is returned instead.
+func (p *PostgresEventRepo) JoinWaitlist(ctx context.Context, userID, eventID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := begin(ctx, p.DB, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	capacity, err := lockOpenEvent(ctx, tx, eid)
+	if err != nil {
+		return 0, err
+	}
+
+	if attending, err := isAttending(ctx, tx, uid, eid); err != nil {
+		return 0, err
+	} else if attending {
+		return 0, event.ErrAlreadyRegistered
+	}
+
+	free, err := hasFreeSeat(ctx, tx, eid, capacity)
+	if err != nil {
+		return 0, err
+	}
+	if free {
+		// a seat someone is already queued for is not up for grabs
+		err := tx.QueryRowContext(ctx, "SELECT NOT EXISTS (SELECT 1 FROM waitlist WHERE event_id = $1)", eid).Scan(&free)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if free {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO attendance (user_id, event_id) VALUES ($1, $2)", uid, eid); err != nil {
+			return 0, err
+		}
+		return 0, tx.Commit()
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO waitlist (user_id, event_id) VALUES ($1, $2)", uid, eid)
+	var pqe *pq.Error
+	if errors.As(err, &pqe) && pqe.Code == "23505" {
+		return 0, event.ErrAlreadyWaitlisted
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	position, err := waitlistPosition(ctx, tx, uid, eid)
+	if err != nil {
+		return 0, err
+	}
+	return position, tx.Commit()
+}
+
+func (p *PostgresEventRepo) LeaveWaitlist(ctx context.Context, userID, eventID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.DB.ExecContext(ctx, "DELETE FROM waitlist WHERE user_id = $1 AND event_id = $2", uid, eid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return event.ErrNotWaitlisted
+	}
+	return nil
+}
+
+func (p *PostgresEventRepo) GetWaitlist(ctx context.Context, eventID string) ([]event.WaitlistEntry, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.DB.QueryContext(ctx, waitlistPositionsQuery+" ORDER BY seq", eid)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errClosingRows := rows.Close(); errClosingRows != nil {
+			slog.Warn("Rows was not closed")
+		}
+	}()
+
+	entries := []event.WaitlistEntry{}
+	for rows.Next() {
+		var entry event.WaitlistEntry
+		if err := rows.Scan(&entry.UserID, &entry.Position, &entry.JoinedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (p *PostgresEventRepo) GetWaitlistPosition(ctx context.Context, userID, eventID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := begin(ctx, p.DB, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	return waitlistPosition(ctx, tx, uid, eid)
+}
+
+func waitlistPosition(ctx context.Context, tx DBTX, uid, eid uuid.UUID) (int, error) {
+	query := "SELECT position FROM (" + waitlistPositionsQuery + ") w WHERE w.user_id = $2"
+	var position int
+	err := tx.QueryRowContext(ctx, query, eid, uid).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, event.ErrNotWaitlisted
+	}
+	return position, err
+}
+
+// promoteFromWaitlist moves the first waitlisted user into attendance if the
+// event has a free seat. The caller must hold the event row lock.
+func promoteFromWaitlist(ctx context.Context, tx DBTX, eid uuid.UUID, capacity sql.NullInt64) error {
+	_, err := promoteNext(ctx, tx, eid, capacity)
+	return err
+}
+
+// fillFromWaitlist is promoteFromWaitlist for as many seats as are free, as
+// after the event's capacity was raised.
+func fillFromWaitlist(ctx context.Context, tx DBTX, eid uuid.UUID, capacity sql.NullInt64) error {
+	for {
+		promoted, err := promoteNext(ctx, tx, eid, capacity)
+		if err != nil || !promoted {
+			return err
+		}
+	}
+}
+
+// promoteNext reports whether it moved a user into attendance.
+func promoteNext(ctx context.Context, tx DBTX, eid uuid.UUID, capacity sql.NullInt64) (bool, error) {
+	free, err := hasFreeSeat(ctx, tx, eid, capacity)
+	if err != nil || !free {
+		return false, err
+	}
+
+	query := `
+DELETE FROM waitlist
+WHERE event_id = $1 AND seq = (SELECT MIN(seq) FROM waitlist WHERE event_id = $1)
+RETURNING user_id`
+	var uid uuid.UUID
+	err = tx.QueryRowContext(ctx, query, eid).Scan(&uid)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "INSERT INTO attendance (user_id, event_id) VALUES ($1, $2)", uid, eid); err != nil {
+		return false, err
+	}
+	return true, nil
+}
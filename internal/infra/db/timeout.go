@@ -0,0 +1,17 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// QueryTimeout caps every repository call on top of the deadline of the
+// caller's context. Zero or less leaves the caller's context alone.
+var QueryTimeout = 5 * time.Second
+
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, QueryTimeout)
+}
@@ -0,0 +1,28 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+)
+
+// generateToken returns a random URL-safe secret, such as a session token.
+func generateToken() string {
+	id := make([]byte, 32)
+
+	_, err := io.ReadFull(rand.Reader, id)
+	if err != nil {
+		panic("failed to generate token")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// hashToken is what the tables store in place of a token, so reading them
+// is not enough to log in as their users. The token is 256 random bits, so
+// a plain SHA-256 is enough; it needs no salt or stretching.
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kapiw04/convenly/internal/domain/uow"
+)
+
+// DBTX is what the repositories need from a database handle. Both *sql.DB
+// and *sql.Tx implement it, so a repository works the same on its own and
+// inside a transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// txn is a transaction started by begin. On a handle that is already a
+// transaction it is a savepoint, so repository methods that need their own
+// transaction can run inside a TxManager unit of work.
+type txn struct {
+	DBTX
+	tx   *sql.Tx
+	done bool
+}
+
+func begin(ctx context.Context, db DBTX, opts *sql.TxOptions) (*txn, error) {
+	if b, ok := db.(txBeginner); ok {
+		tx, err := b.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &txn{DBTX: tx, tx: tx}, nil
+	}
+	if _, err := db.ExecContext(ctx, "SAVEPOINT repo_tx"); err != nil {
+		return nil, err
+	}
+	return &txn{DBTX: db}, nil
+}
+
+func (t *txn) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.tx != nil {
+		return t.tx.Commit()
+	}
+	_, err := t.DBTX.ExecContext(context.Background(), "RELEASE SAVEPOINT repo_tx")
+	return err
+}
+
+// Rollback undoes the transaction unless it was committed. Like
+// (*sql.Tx).Rollback it is safe to defer.
+func (t *txn) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.tx != nil {
+		return t.tx.Rollback()
+	}
+	_, err := t.DBTX.ExecContext(context.Background(), "ROLLBACK TO SAVEPOINT repo_tx")
+	return err
+}
+
+type PostgresTxManager struct {
+	DB DBTX
+}
+
+func NewPostgresTxManager(db *sql.DB) *PostgresTxManager {
+	return &PostgresTxManager{DB: db}
+}
+
+func (m *PostgresTxManager) WithinTx(ctx context.Context, fn func(uow.Repos) error) error {
+	tx, err := begin(ctx, m.DB, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	userRepo := &PostgresUserRepo{DB: tx}
+	repos := uow.Repos{
+		Events:         &PostgresEventRepo{DB: tx, TagRepo: &PostgresTagRepo{DB: tx}},
+		Users:          userRepo,
+		Sessions:       &PostgresSessionRepo{DB: tx, UserRepo: userRepo},
+		PasswordResets: &PostgresPasswordResetRepo{DB: tx},
+		RefreshTokens:  &PostgresRefreshTokenRepo{DB: tx},
+	}
+	if err := fn(repos); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+var _ uow.TxManager = (*PostgresTxManager)(nil)
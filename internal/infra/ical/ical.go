@@ -0,0 +1,284 @@
+// Package ical renders events as RFC 5545 iCalendar documents.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+)
+
+const (
+	prodID     = "-//convenly//convenly//EN"
+	uidDomain  = "convenly"
+	timeFormat = "20060102T150405Z"
+	// localTimeFormat is the form of DATE-TIME values with a TZID.
+	localTimeFormat = "20060102T150405"
+	// maxLineOctets is the longest content line allowed before folding.
+	maxLineOctets = 75
+)
+
+// ContentType is the MIME type of the documents produced by Write.
+const ContentType = "text/calendar; charset=utf-8"
+
+// Write encodes the events as a single VCALENDAR. name is used as the
+// calendar display name and may be empty. exceptions are the cancelled and
+// rescheduled occurrences of the recurring events, by event ID. stamp is used
+// as DTSTAMP of every VEVENT.
+func Write(w io.Writer, name string, events []*event.Event, exceptions map[string][]event.OccurrenceException, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + prodID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if name != "" {
+		lw.line("X-WR-CALNAME:" + escapeText(name))
+	}
+	writeTimezones(lw, events)
+	for _, e := range events {
+		writeEvent(lw, e, exceptions[e.EventID], stamp)
+	}
+	lw.line("END:VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+func writeEvent(lw *lineWriter, e *event.Event, exceptions []event.OccurrenceException, stamp time.Time) {
+	series := e.IsRecurring() && e.RecurrenceID == nil
+
+	lw.line("BEGIN:VEVENT")
+	if e.RecurrenceID != nil {
+		// a single occurrence is published as an event of its own, since
+		// the series it belongs to may not be in the calendar
+		lw.line(fmt.Sprintf("UID:%s-%s@%s", e.EventID, formatTime(*e.RecurrenceID), uidDomain))
+	} else {
+		lw.line(fmt.Sprintf("UID:%s@%s", e.EventID, uidDomain))
+	}
+	lw.line("DTSTAMP:" + formatTime(stamp))
+	if series {
+		// the rule repeats in the event's local time, so the start has to
+		// carry its timezone for occurrences to stay put across DST changes
+		lw.line("DTSTART" + formatZonedTime(e.StartsAt, e.Timezone))
+		lw.line("DTEND" + formatZonedTime(e.EndsAt, e.Timezone))
+		lw.line("RRULE:" + strings.TrimPrefix(e.Recurrence, "RRULE:"))
+		for _, ex := range exceptions {
+			if ex.Cancelled {
+				lw.line("EXDATE" + formatZonedTime(ex.RecurrenceID, e.Timezone))
+			}
+		}
+	} else {
+		lw.line("DTSTART:" + formatTime(e.StartsAt))
+		lw.line("DTEND:" + formatTime(e.EndsAt))
+	}
+	writeDetails(lw, e)
+	lw.line("END:VEVENT")
+
+	if !series {
+		return
+	}
+	// rescheduled occurrences override the ones the rule generates
+	duration := e.EndsAt.Sub(e.StartsAt)
+	for _, ex := range exceptions {
+		if ex.Cancelled || (ex.StartsAt == nil && ex.EndsAt == nil) {
+			continue
+		}
+		startsAt, endsAt := ex.RecurrenceID, ex.RecurrenceID.Add(duration)
+		if ex.StartsAt != nil {
+			startsAt = *ex.StartsAt
+		}
+		if ex.EndsAt != nil {
+			endsAt = *ex.EndsAt
+		}
+		lw.line("BEGIN:VEVENT")
+		lw.line(fmt.Sprintf("UID:%s@%s", e.EventID, uidDomain))
+		lw.line("DTSTAMP:" + formatTime(stamp))
+		lw.line("RECURRENCE-ID" + formatZonedTime(ex.RecurrenceID, e.Timezone))
+		lw.line("DTSTART:" + formatTime(startsAt))
+		lw.line("DTEND:" + formatTime(endsAt))
+		writeDetails(lw, e)
+		lw.line("END:VEVENT")
+	}
+}
+
+// writeDetails writes the properties a series shares with its rescheduled
+// occurrences.
+func writeDetails(lw *lineWriter, e *event.Event) {
+	lw.line("SUMMARY:" + escapeText(e.Name))
+	if e.Status == event.StatusCancelled {
+		lw.line("STATUS:CANCELLED")
+	}
+	if e.Description != "" {
+		lw.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	lw.line(fmt.Sprintf("GEO:%.6f;%.6f", e.Latitude, e.Longitude))
+	if len(e.Tags) > 0 {
+		escaped := make([]string, len(e.Tags))
+		for i, tag := range e.Tags {
+			escaped[i] = escapeText(tag)
+		}
+		lw.line("CATEGORIES:" + strings.Join(escaped, ","))
+	}
+}
+
+// writeTimezones writes a VTIMEZONE for every zone referenced by a TZID,
+// covering the time the recurring events using it can span.
+func writeTimezones(lw *lineWriter, events []*event.Event) {
+	type span struct {
+		loc      *time.Location
+		from, to time.Time
+	}
+	spans := make(map[string]*span)
+	for _, e := range events {
+		if !e.IsRecurring() || e.RecurrenceID != nil {
+			continue
+		}
+		loc, err := event.LoadTimezone(e.Timezone)
+		if err != nil || loc == time.UTC {
+			continue
+		}
+		to := e.StartsAt.Add(event.MaxRecurrenceHorizon)
+		if sp, ok := spans[e.Timezone]; ok {
+			sp.from = minTime(sp.from, e.StartsAt)
+			sp.to = maxTime(sp.to, to)
+			continue
+		}
+		spans[e.Timezone] = &span{loc: loc, from: e.StartsAt, to: to}
+	}
+
+	names := slices.Sorted(maps.Keys(spans))
+	for _, name := range names {
+		sp := spans[name]
+		lw.line("BEGIN:VTIMEZONE")
+		lw.line("TZID:" + name)
+		// every offset change is written as an observance of its own,
+		// starting with the one in effect at the first event
+		t := sp.from.In(sp.loc)
+		for {
+			start, end := t.ZoneBounds()
+			writeObservance(lw, t, start)
+			if end.IsZero() || end.After(sp.to) {
+				break
+			}
+			t = end.In(sp.loc)
+		}
+		lw.line("END:VTIMEZONE")
+	}
+}
+
+// writeObservance writes the STANDARD or DAYLIGHT component of the zone in
+// effect at t, which began at onset. A zero onset means the zone was always
+// in effect.
+func writeObservance(lw *lineWriter, t, onset time.Time) {
+	abbrev, offset := t.Zone()
+	offsetFrom := offset
+	dtstart := "19700101T000000"
+	if !onset.IsZero() {
+		_, offsetFrom = onset.Add(-time.Second).In(t.Location()).Zone()
+		// the onset is given in the local time of the previous offset
+		dtstart = onset.In(time.FixedZone("", offsetFrom)).Format(localTimeFormat)
+	}
+
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	lw.line("BEGIN:" + kind)
+	lw.line("DTSTART:" + dtstart)
+	lw.line("TZOFFSETFROM:" + formatOffset(offsetFrom))
+	lw.line("TZOFFSETTO:" + formatOffset(offset))
+	lw.line("TZNAME:" + escapeText(abbrev))
+	lw.line("END:" + kind)
+}
+
+// formatOffset formats a UTC offset in seconds as a UTC-OFFSET value.
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+	if offset%60 != 0 {
+		s += fmt.Sprintf("%02d", offset%60)
+	}
+	return s
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// formatZonedTime formats t as the parameters and value of a DATE-TIME
+// property in the timezone, falling back to UTC.
+func formatZonedTime(t time.Time, timezone string) string {
+	loc, err := event.LoadTimezone(timezone)
+	if err != nil || loc == time.UTC {
+		return ":" + formatTime(t)
+	}
+	return ";TZID=" + timezone + ":" + t.In(loc).Format(localTimeFormat)
+}
+
+// escapeText escapes a TEXT property value as described in RFC 5545 3.3.11.
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// lineWriter writes CRLF terminated content lines, folding them at
+// maxLineOctets without splitting multi-byte characters.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			// the leading space of a continuation line counts towards its length
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	_, lw.err = lw.w.WriteString(b.String())
+}
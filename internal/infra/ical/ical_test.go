@@ -0,0 +1,180 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_SingleEvent(t *testing.T) {
+	var buf bytes.Buffer
+	e := &event.Event{
+		EventID:     "123e4567-e89b-12d3-a456-426614174000",
+		Name:        "Go Meetup; Vol. 3",
+		Description: "Talks, pizza\nand networking",
+		StartsAt:    time.Date(2025, 12, 31, 18, 30, 0, 0, time.UTC),
+		EndsAt:      time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC),
+		Timezone:    "Europe/Warsaw",
+		Latitude:    52.2297,
+		Longitude:   21.0122,
+		Tags:        []string{"Tech", "Meetup"},
+	}
+	stamp := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, Write(&buf, "", []*event.Event{e}, nil, stamp))
+
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	require.Contains(t, out, "UID:123e4567-e89b-12d3-a456-426614174000@convenly\r\n")
+	require.Contains(t, out, "DTSTAMP:20251201T100000Z\r\n")
+	require.Contains(t, out, "DTSTART:20251231T183000Z\r\n")
+	require.Contains(t, out, "DTEND:20251231T220000Z\r\n")
+	require.Contains(t, out, `SUMMARY:Go Meetup\; Vol. 3`+"\r\n")
+	require.Contains(t, out, `DESCRIPTION:Talks\, pizza\nand networking`+"\r\n")
+	require.Contains(t, out, "GEO:52.229700;21.012200\r\n")
+	require.Contains(t, out, "CATEGORIES:Tech,Meetup\r\n")
+	require.NotContains(t, out, "X-WR-CALNAME")
+	require.NotContains(t, out, "STATUS:")
+	require.NotContains(t, out, "VTIMEZONE")
+}
+
+func TestWrite_CancelledEvent(t *testing.T) {
+	var buf bytes.Buffer
+	e := &event.Event{EventID: "event-1", Name: "Called Off", StartsAt: time.Now(), Status: event.StatusCancelled}
+
+	require.NoError(t, Write(&buf, "", []*event.Event{e}, nil, time.Now()))
+	require.Contains(t, buf.String(), "STATUS:CANCELLED\r\n")
+}
+
+func TestWrite_RecurringEvent(t *testing.T) {
+	var buf bytes.Buffer
+	e := &event.Event{
+		EventID:    "event-1",
+		Name:       "Weekly Meetup",
+		StartsAt:   time.Date(2025, 3, 3, 17, 0, 0, 0, time.UTC),
+		EndsAt:     time.Date(2025, 3, 3, 19, 0, 0, 0, time.UTC),
+		Timezone:   "Europe/Warsaw",
+		Recurrence: "FREQ=WEEKLY;BYDAY=MO;COUNT=10",
+	}
+
+	require.NoError(t, Write(&buf, "", []*event.Event{e}, nil, time.Now()))
+
+	out := buf.String()
+	require.Contains(t, out, "DTSTART;TZID=Europe/Warsaw:20250303T180000\r\n")
+	require.Contains(t, out, "DTEND;TZID=Europe/Warsaw:20250303T200000\r\n")
+	require.Contains(t, out, "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=10\r\n")
+	require.NotContains(t, out, "EXDATE")
+	require.NotContains(t, out, "RECURRENCE-ID")
+
+	// the zone is defined before the events referencing it, starting with
+	// the offset in effect at the first occurrence
+	require.Less(t, strings.Index(out, "BEGIN:VTIMEZONE\r\nTZID:Europe/Warsaw\r\n"), strings.Index(out, "BEGIN:VEVENT"))
+	require.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20241027T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nEND:STANDARD\r\n")
+	require.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20250330T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nEND:DAYLIGHT\r\n")
+	require.Equal(t, 1, strings.Count(out, "BEGIN:VTIMEZONE"))
+}
+
+func TestWrite_RecurringEventExceptions(t *testing.T) {
+	var buf bytes.Buffer
+	e := &event.Event{
+		EventID:    "event-1",
+		Name:       "Weekly Meetup",
+		StartsAt:   time.Date(2025, 3, 3, 17, 0, 0, 0, time.UTC),
+		EndsAt:     time.Date(2025, 3, 3, 19, 0, 0, 0, time.UTC),
+		Timezone:   "Europe/Warsaw",
+		Recurrence: "FREQ=WEEKLY;BYDAY=MO;COUNT=10",
+	}
+	movedStart := time.Date(2025, 4, 1, 16, 0, 0, 0, time.UTC)
+	movedEnd := time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC)
+	exceptions := map[string][]event.OccurrenceException{
+		"event-1": {
+			{EventID: "event-1", RecurrenceID: time.Date(2025, 3, 10, 17, 0, 0, 0, time.UTC), Cancelled: true},
+			{EventID: "event-1", RecurrenceID: time.Date(2025, 3, 31, 16, 0, 0, 0, time.UTC), StartsAt: &movedStart, EndsAt: &movedEnd},
+		},
+	}
+
+	require.NoError(t, Write(&buf, "", []*event.Event{e}, exceptions, time.Now()))
+
+	out := buf.String()
+	require.Contains(t, out, "EXDATE;TZID=Europe/Warsaw:20250310T180000\r\n")
+	require.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT\r\n"))
+	require.Equal(t, 2, strings.Count(out, "UID:event-1@convenly\r\n"))
+
+	override := out[strings.LastIndex(out, "BEGIN:VEVENT"):]
+	require.Contains(t, override, "RECURRENCE-ID;TZID=Europe/Warsaw:20250331T180000\r\n")
+	require.Contains(t, override, "DTSTART:20250401T160000Z\r\n")
+	require.Contains(t, override, "DTEND:20250401T180000Z\r\n")
+	require.Contains(t, override, "SUMMARY:Weekly Meetup\r\n")
+	require.NotContains(t, override, "RRULE")
+}
+
+func TestFormatOffset(t *testing.T) {
+	require.Equal(t, "+0100", formatOffset(3600))
+	require.Equal(t, "-0330", formatOffset(-(3*3600 + 30*60)))
+	require.Equal(t, "+012345", formatOffset(3600+23*60+45))
+}
+
+func TestWrite_Occurrence(t *testing.T) {
+	var buf bytes.Buffer
+	recurrenceID := time.Date(2025, 3, 10, 17, 0, 0, 0, time.UTC)
+	e := &event.Event{
+		EventID:      "event-1",
+		Name:         "Weekly Meetup",
+		StartsAt:     time.Date(2025, 3, 11, 17, 0, 0, 0, time.UTC),
+		EndsAt:       time.Date(2025, 3, 11, 19, 0, 0, 0, time.UTC),
+		Timezone:     "Europe/Warsaw",
+		Recurrence:   "FREQ=WEEKLY;BYDAY=MO;COUNT=10",
+		RecurrenceID: &recurrenceID,
+	}
+
+	require.NoError(t, Write(&buf, "", []*event.Event{e}, nil, time.Now()))
+
+	out := buf.String()
+	require.Contains(t, out, "UID:event-1-20250310T170000Z@convenly\r\n")
+	require.Contains(t, out, "DTSTART:20250311T170000Z\r\n")
+	require.Contains(t, out, "DTEND:20250311T190000Z\r\n")
+	require.NotContains(t, out, "RRULE")
+}
+
+func TestWrite_Feed(t *testing.T) {
+	var buf bytes.Buffer
+	events := []*event.Event{
+		{EventID: "event-1", Name: "First", StartsAt: time.Now()},
+		{EventID: "event-2", Name: "Second", StartsAt: time.Now()},
+	}
+
+	require.NoError(t, Write(&buf, "My events", events, nil, time.Now()))
+
+	out := buf.String()
+	require.Contains(t, out, "X-WR-CALNAME:My events\r\n")
+	require.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT\r\n"))
+	require.Equal(t, 2, strings.Count(out, "END:VEVENT\r\n"))
+}
+
+func TestWrite_FoldsLongLines(t *testing.T) {
+	var buf bytes.Buffer
+	e := &event.Event{
+		EventID:     "event-1",
+		Name:        "Long",
+		Description: strings.Repeat("ż", 100),
+		StartsAt:    time.Now(),
+	}
+
+	require.NoError(t, Write(&buf, "", []*event.Event{e}, nil, time.Now()))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineOctets)
+	}
+
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	require.Contains(t, unfolded, "DESCRIPTION:"+strings.Repeat("ż", 100)+"\r\n")
+}
+
+func TestEscapeText(t *testing.T) {
+	require.Equal(t, `a\\b\;c\,d\ne`, escapeText("a\\b;c,d\r\ne"))
+}
@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/mail"
+)
+
+// LogMailer does not deliver mail. It appends each message to the file at
+// Path, or writes it to the log when Path is empty, so links sent to users
+// can be followed in development and tests.
+type LogMailer struct {
+	Path string
+	From string
+
+	mu sync.Mutex
+}
+
+var _ mail.Mailer = (*LogMailer)(nil)
+
+func (m *LogMailer) Send(ctx context.Context, msg mail.Message) error {
+	if m.Path == "" {
+		slog.Info("Mail not sent, logging it instead", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+		return nil
+	}
+
+	body, err := format(m.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "%s\r\n\r\n", body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
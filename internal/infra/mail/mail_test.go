@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/mail"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	msg := mail.Message{To: "alice@example.com", Subject: "Hello", Body: "line one\nline two"}
+
+	b, err := format("noreply@convenly.test", msg, now)
+
+	require.NoError(t, err)
+	require.Equal(t, "From: noreply@convenly.test\r\n"+
+		"To: alice@example.com\r\n"+
+		"Subject: Hello\r\n"+
+		"Date: Sat, 01 Mar 2025 12:00:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n"+
+		"\r\n"+
+		"line one\r\nline two", string(b))
+}
+
+func TestFormat_RejectsHeaderInjection(t *testing.T) {
+	msg := mail.Message{To: "alice@example.com\r\nBcc: eve@example.com", Subject: "Hello"}
+
+	_, err := format("noreply@convenly.test", msg, time.Now())
+
+	require.ErrorIs(t, err, errHeaderInjection)
+}
+
+func TestLogMailer_AppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	m := &LogMailer{Path: path, From: "noreply@convenly.test"}
+
+	require.NoError(t, m.Send(context.Background(), mail.Message{To: "alice@example.com", Subject: "First", Body: "one"}))
+	require.NoError(t, m.Send(context.Background(), mail.Message{To: "bob@example.com", Subject: "Second", Body: "two"}))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(b), "From: noreply@convenly.test"))
+	require.Contains(t, string(b), "To: bob@example.com")
+	require.Contains(t, string(b), "two")
+}
@@ -0,0 +1,81 @@
+package security
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/stretchr/testify/require"
+)
+
+var testClaims = security.Claims{
+	Subject:   "123e4567-e89b-12d3-a456-426614174000",
+	IssuedAt:  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	ExpiresAt: time.Date(2025, 3, 1, 12, 15, 0, 0, time.UTC),
+}
+
+func TestJWTSigner_SignAndVerify(t *testing.T) {
+	signer := NewJWTSigner([]byte("secret"))
+
+	token, err := signer.Sign(testClaims)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(token, "."), 3)
+
+	claims, err := signer.Verify(token, testClaims.IssuedAt.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, testClaims.Subject, claims.Subject)
+	require.True(t, testClaims.ExpiresAt.Equal(claims.ExpiresAt))
+}
+
+func TestJWTSigner_Verify_Expired(t *testing.T) {
+	signer := NewJWTSigner([]byte("secret"))
+
+	token, err := signer.Sign(testClaims)
+	require.NoError(t, err)
+
+	_, err = signer.Verify(token, testClaims.ExpiresAt)
+	require.ErrorIs(t, err, security.ErrTokenExpired)
+}
+
+func TestJWTSigner_Verify_OtherSecret(t *testing.T) {
+	token, err := NewJWTSigner([]byte("secret")).Sign(testClaims)
+	require.NoError(t, err)
+
+	_, err = NewJWTSigner([]byte("other")).Verify(token, testClaims.IssuedAt)
+	require.ErrorIs(t, err, security.ErrInvalidToken)
+}
+
+func TestJWTSigner_Verify_TamperedPayload(t *testing.T) {
+	signer := NewJWTSigner([]byte("secret"))
+	token, err := signer.Sign(testClaims)
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"someone-else","iat":0,"exp":9999999999}`))
+
+	_, err = signer.Verify(strings.Join(parts, "."), testClaims.IssuedAt)
+	require.ErrorIs(t, err, security.ErrInvalidToken)
+}
+
+func TestJWTSigner_Verify_RejectsUnsignedAlgorithm(t *testing.T) {
+	signer := NewJWTSigner([]byte("secret"))
+	token, err := signer.Sign(testClaims)
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	_, err = signer.Verify(parts[0]+"."+parts[1]+".", testClaims.IssuedAt)
+	require.ErrorIs(t, err, security.ErrInvalidToken)
+}
+
+func TestJWTSigner_Verify_Garbage(t *testing.T) {
+	signer := NewJWTSigner([]byte("secret"))
+
+	for _, token := range []string{"", "abc", "a.b", "a.b.c"} {
+		_, err := signer.Verify(token, testClaims.IssuedAt)
+		require.ErrorIs(t, err, security.ErrInvalidToken, token)
+	}
+}
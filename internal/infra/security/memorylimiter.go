@@ -0,0 +1,85 @@
+package security
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+)
+
+type failures struct {
+	count       int
+	lastAt      time.Time
+	lockedUntil time.Time
+}
+
+// stale reports whether the failures are past the policy's window and no
+// longer lock the key out.
+func (f *failures) stale(p security.LockoutPolicy, now time.Time) bool {
+	return !now.Before(f.lastAt.Add(p.Window)) && !now.Before(f.lockedUntil)
+}
+
+// MemoryLoginLimiter keeps failed logins in memory. It is lost on restart
+// and not shared between instances, so it suits a single server.
+type MemoryLoginLimiter struct {
+	Policy security.LockoutPolicy
+
+	mu   sync.Mutex
+	keys map[string]*failures
+}
+
+func NewMemoryLoginLimiter(policy security.LockoutPolicy) *MemoryLoginLimiter {
+	return &MemoryLoginLimiter{Policy: policy, keys: make(map[string]*failures)}
+}
+
+var _ security.LoginLimiter = (*MemoryLoginLimiter)(nil)
+
+func (m *MemoryLoginLimiter) Wait(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.keys[key]
+	if !ok || !now.Before(f.lockedUntil) {
+		return 0, nil
+	}
+	return f.lockedUntil.Sub(now), nil
+}
+
+func (m *MemoryLoginLimiter) Fail(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.keys[key]
+	if !ok || f.stale(m.Policy, now) {
+		f = &failures{}
+		m.keys[key] = f
+	}
+	f.count++
+	f.lastAt = now
+	lockout := m.Policy.Lockout(f.count)
+	f.lockedUntil = now.Add(lockout)
+	return lockout, nil
+}
+
+func (m *MemoryLoginLimiter) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.keys, key)
+	return nil
+}
+
+func (m *MemoryLoginLimiter) Purge(ctx context.Context, now time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for key, f := range m.keys {
+		if f.stale(m.Policy, now) {
+			delete(m.keys, key)
+			n++
+		}
+	}
+	return n, nil
+}
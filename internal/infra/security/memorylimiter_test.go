@@ -0,0 +1,111 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/stretchr/testify/require"
+)
+
+var testLockoutPolicy = security.LockoutPolicy{
+	MaxFailures: 3,
+	BaseDelay:   time.Minute,
+	MaxDelay:    4 * time.Minute,
+	Window:      time.Hour,
+}
+
+func failTimes(t *testing.T, l *MemoryLoginLimiter, key string, now time.Time, n int) time.Duration {
+	t.Helper()
+	var lockout time.Duration
+	for range n {
+		var err error
+		lockout, err = l.Fail(context.Background(), key, now)
+		require.NoError(t, err)
+	}
+	return lockout
+}
+
+func TestLockoutPolicy_Lockout(t *testing.T) {
+	expected := []time.Duration{0, 0, 0, time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute}
+	for failures, want := range expected {
+		require.Equal(t, want, testLockoutPolicy.Lockout(failures), failures)
+	}
+	require.Equal(t, 4*time.Minute, testLockoutPolicy.Lockout(1000))
+}
+
+func TestMemoryLoginLimiter_LocksOutAfterMaxFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryLoginLimiter(testLockoutPolicy)
+
+	require.Zero(t, failTimes(t, l, "alice@example.com", now, 2))
+	wait, err := l.Wait(ctx, "alice@example.com", now)
+	require.NoError(t, err)
+	require.Zero(t, wait)
+
+	require.Equal(t, time.Minute, failTimes(t, l, "alice@example.com", now, 1))
+	wait, err = l.Wait(ctx, "alice@example.com", now.Add(20*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, 40*time.Second, wait)
+
+	// other keys are not affected
+	wait, err = l.Wait(ctx, "bob@example.com", now)
+	require.NoError(t, err)
+	require.Zero(t, wait)
+
+	wait, err = l.Wait(ctx, "alice@example.com", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Zero(t, wait)
+}
+
+func TestMemoryLoginLimiter_BacksOffExponentially(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryLoginLimiter(testLockoutPolicy)
+
+	require.Equal(t, time.Minute, failTimes(t, l, "key", now, 3))
+	now = now.Add(time.Minute)
+	require.Equal(t, 2*time.Minute, failTimes(t, l, "key", now, 1))
+	now = now.Add(2 * time.Minute)
+	require.Equal(t, 4*time.Minute, failTimes(t, l, "key", now, 1))
+	now = now.Add(4 * time.Minute)
+	require.Equal(t, 4*time.Minute, failTimes(t, l, "key", now, 1))
+}
+
+func TestMemoryLoginLimiter_ForgetsAfterWindow(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryLoginLimiter(testLockoutPolicy)
+
+	failTimes(t, l, "key", now, 2)
+	require.Zero(t, failTimes(t, l, "key", now.Add(time.Hour), 1))
+}
+
+func TestMemoryLoginLimiter_Reset(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryLoginLimiter(testLockoutPolicy)
+
+	failTimes(t, l, "key", now, 3)
+	require.NoError(t, l.Reset(ctx, "key"))
+
+	wait, err := l.Wait(ctx, "key", now)
+	require.NoError(t, err)
+	require.Zero(t, wait)
+	require.Zero(t, failTimes(t, l, "key", now, 1))
+}
+
+func TestMemoryLoginLimiter_Purge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryLoginLimiter(testLockoutPolicy)
+
+	failTimes(t, l, "old", now, 1)
+	failTimes(t, l, "recent", now.Add(30*time.Minute), 1)
+
+	n, err := l.Purge(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, l.keys, 1)
+	require.Contains(t, l.keys, "recent")
+}
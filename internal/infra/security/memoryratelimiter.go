@@ -0,0 +1,96 @@
+package security
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+)
+
+// sweepInterval is how often MemoryRateLimiter drops the buckets that have
+// refilled, so idle clients do not use memory.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	limit     security.RateLimit
+}
+
+// rate is how many tokens the bucket gets back per second.
+func (b *bucket) rate() float64 {
+	return float64(b.limit.Requests) / b.limit.Per.Seconds()
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	if elapsed > 0 {
+		b.tokens = min(float64(b.limit.Requests), b.tokens+elapsed*b.rate())
+		b.updatedAt = now
+	}
+}
+
+func (b *bucket) full() bool {
+	return b.tokens >= float64(b.limit.Requests)
+}
+
+// MemoryRateLimiter keeps the buckets in memory, so each instance limits the
+// requests it serves on its own.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: make(map[string]*bucket)}
+}
+
+var _ security.RateLimiter = (*MemoryRateLimiter)(nil)
+
+func (m *MemoryRateLimiter) Take(ctx context.Context, key string, limit security.RateLimit, now time.Time) (security.RateLimitResult, error) {
+	if limit.Unlimited() {
+		return security.RateLimitResult{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+	b, ok := m.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Requests), updatedAt: now, limit: limit}
+		m.buckets[key] = b
+	}
+	b.refill(now)
+
+	res := security.RateLimitResult{}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / b.rate())
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = seconds((float64(limit.Requests) - b.tokens) / b.rate())
+	return res, nil
+}
+
+func (m *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		b.refill(now)
+		if b.full() {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
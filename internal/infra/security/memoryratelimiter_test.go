@@ -0,0 +1,79 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/stretchr/testify/require"
+)
+
+var testRateLimit = security.RateLimit{Requests: 3, Per: 3 * time.Second}
+
+func take(t *testing.T, l *MemoryRateLimiter, key string, now time.Time) security.RateLimitResult {
+	t.Helper()
+	res, err := l.Take(context.Background(), key, testRateLimit, now)
+	require.NoError(t, err)
+	return res
+}
+
+func TestMemoryRateLimiter_AllowsBurst(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryRateLimiter()
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		res := take(t, l, "key", now)
+		require.True(t, res.Allowed)
+		require.Equal(t, remaining, res.Remaining)
+	}
+
+	res := take(t, l, "key", now)
+	require.False(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+	require.Equal(t, time.Second, res.RetryAfter)
+	require.Equal(t, 3*time.Second, res.Reset)
+
+	// other keys have their own bucket
+	require.True(t, take(t, l, "other", now).Allowed)
+}
+
+func TestMemoryRateLimiter_Refills(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryRateLimiter()
+	for range 3 {
+		take(t, l, "key", now)
+	}
+
+	res := take(t, l, "key", now.Add(500*time.Millisecond))
+	require.False(t, res.Allowed)
+	require.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	res = take(t, l, "key", now.Add(time.Second))
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+
+	// the bucket never holds more than the limit
+	res = take(t, l, "key", now.Add(time.Hour))
+	require.True(t, res.Allowed)
+	require.Equal(t, 2, res.Remaining)
+}
+
+func TestMemoryRateLimiter_Unlimited(t *testing.T) {
+	l := NewMemoryRateLimiter()
+
+	res, err := l.Take(context.Background(), "key", security.RateLimit{}, time.Now())
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Empty(t, l.buckets)
+}
+
+func TestMemoryRateLimiter_SweepsFullBuckets(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryRateLimiter()
+	take(t, l, "idle", now)
+
+	take(t, l, "active", now.Add(sweepInterval))
+	require.Len(t, l.buckets, 1)
+	require.Contains(t, l.buckets, "active")
+}
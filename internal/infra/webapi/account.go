@@ -0,0 +1,241 @@
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+// RequestPasswordResetHandler mails a reset link to the account with the
+// email. It answers the same whether or not there is one.
+func (rt *Router) RequestPasswordResetHandler(w http.ResponseWriter, r *http.Request) {
+	var req PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	if req.Email == "" {
+		ErrorResponse(w, http.StatusBadRequest, "empty fields")
+		return
+	}
+
+	err := rt.AccountService.RequestPasswordReset(r.Context(), req.Email)
+	switch {
+	case errors.Is(err, user.ErrInvalidEmailFormat):
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to request password reset", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ConfirmPasswordResetHandler sets a new password with the token from a
+// reset link. All sessions and refresh tokens of the user are revoked.
+func (rt *Router) ConfirmPasswordResetHandler(w http.ResponseWriter, r *http.Request) {
+	var req ConfirmPasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	if req.Token == "" || req.Password == "" {
+		ErrorResponse(w, http.StatusBadRequest, "empty fields")
+		return
+	}
+
+	err := rt.AccountService.ResetPassword(r.Context(), req.Token, req.Password)
+	switch {
+	case errors.Is(err, user.ErrPasswordTooShort), errors.Is(err, user.ErrPasswordTooLong),
+		errors.Is(err, user.ErrPasswordTooWeak), errors.Is(err, user.ErrInvalidResetToken):
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, user.ErrUserNotFound):
+		ErrorResponse(w, http.StatusBadRequest, user.ErrInvalidResetToken.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to reset password", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// VerifyEmailHandler marks the email a verification link was sent to as
+// verified.
+func (rt *Router) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	if req.Token == "" {
+		ErrorResponse(w, http.StatusBadRequest, "empty fields")
+		return
+	}
+
+	err := rt.AccountService.VerifyEmail(r.Context(), req.Token)
+	switch {
+	case errors.Is(err, user.ErrInvalidVerificationToken), errors.Is(err, user.ErrUserNotFound):
+		ErrorResponse(w, http.StatusBadRequest, user.ErrInvalidVerificationToken.Error())
+		return
+	case errors.Is(err, user.ErrUserExists):
+		// another account took the address since the link was sent
+		ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to verify email", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ResendVerificationHandler mails the current user another verification
+// link.
+func (rt *Router) ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	err := rt.AccountService.ResendVerification(r.Context(), getUserID(r))
+	var lockedOut *security.LockedOutError
+	switch {
+	case errors.As(err, &lockedOut):
+		TooManyAttemptsResponse(w, lockedOut)
+		return
+	case errors.Is(err, user.ErrEmailAlreadyVerified):
+		ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to resend verification mail", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateProfileHandler changes the fields of the current user's profile
+// given in the body and returns the user.
+func (rt *Router) UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+
+	u, err := rt.UserService.UpdateProfile(r.Context(), getUserID(r), &user.ProfileUpdate{Name: req.Name})
+	switch {
+	case errors.Is(err, user.ErrUsernameTooShort):
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to update profile", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	u.PasswordHash = ""
+	JSONResponse(w, http.StatusOK, u)
+}
+
+// ChangePasswordHandler sets a new password for the current user, who has
+// to give their current one. The user is logged out everywhere.
+func (rt *Router) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		ErrorResponse(w, http.StatusBadRequest, "empty fields")
+		return
+	}
+
+	err := rt.AccountService.ChangePassword(r.Context(), getUserID(r), req.CurrentPassword, req.NewPassword, clientIP(r))
+	var lockedOut *security.LockedOutError
+	switch {
+	case errors.As(err, &lockedOut):
+		TooManyAttemptsResponse(w, lockedOut)
+		return
+	case errors.Is(err, user.ErrInvalidCredentials):
+		ErrorResponse(w, http.StatusForbidden, "current password is wrong")
+		return
+	case errors.Is(err, user.ErrPasswordTooShort), errors.Is(err, user.ErrPasswordTooLong), errors.Is(err, user.ErrPasswordTooWeak):
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to change password", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	clearSessionCookie(w)
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ChangeEmailHandler mails a verification link to the new email of the
+// current user, which replaces the old one once the link is followed.
+func (rt *Router) ChangeEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var req ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	if req.Email == "" || req.Password == "" {
+		ErrorResponse(w, http.StatusBadRequest, "empty fields")
+		return
+	}
+
+	err := rt.AccountService.ChangeEmail(r.Context(), getUserID(r), req.Email, req.Password, clientIP(r))
+	var lockedOut *security.LockedOutError
+	switch {
+	case errors.As(err, &lockedOut):
+		TooManyAttemptsResponse(w, lockedOut)
+		return
+	case errors.Is(err, user.ErrInvalidCredentials):
+		ErrorResponse(w, http.StatusForbidden, "password is wrong")
+		return
+	case errors.Is(err, user.ErrInvalidEmailFormat), errors.Is(err, user.ErrEmailUnchanged):
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, user.ErrUserExists):
+		ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to change email", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// DeleteAccountHandler deletes the current user's account, with the events
+// they host, after checking their password.
+func (rt *Router) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	if req.Password == "" {
+		ErrorResponse(w, http.StatusBadRequest, "empty fields")
+		return
+	}
+
+	err := rt.AccountService.DeleteAccount(r.Context(), getUserID(r), req.Password, clientIP(r))
+	var lockedOut *security.LockedOutError
+	switch {
+	case errors.As(err, &lockedOut):
+		TooManyAttemptsResponse(w, lockedOut)
+		return
+	case errors.Is(err, user.ErrInvalidCredentials):
+		ErrorResponse(w, http.StatusForbidden, "password is wrong")
+		return
+	case err != nil:
+		slog.Error("Failed to delete account", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	clearSessionCookie(w)
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,175 @@
+package webapi
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/domain/user"
+	"github.com/kapiw04/convenly/internal/infra/ical"
+)
+
+func (rt *Router) AttendeesCSVHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	if err := uuid.Validate(eventID); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: invalid event id")
+		return
+	}
+
+	attendees, err := rt.EventService.GetAttendeeRoster(r.Context(), userID, eventID, nil)
+	if err != nil {
+		switch {
+		case errors.Is(err, event.ErrEventNotFound):
+			ErrorResponse(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, event.ErrNotOrganizer):
+			ErrorResponse(w, http.StatusForbidden, "only the organizer can export the attendees")
+		default:
+			slog.Error("Failed to get attendees", "err", err)
+			ErrorResponse(w, http.StatusInternalServerError, "failed to get attendees")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="attendees-%s.csv"`, eventID))
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeAttendeesCSV(w, attendees); err != nil {
+		slog.Warn("Failed to write attendees csv", "err", err)
+	}
+}
+
+func (rt *Router) EventICSHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+
+	e, err := rt.EventService.GetVisibleEvent(r.Context(), getUserID(r), eventID)
+	if err != nil {
+		if errors.Is(err, event.ErrEventNotFound) {
+			ErrorResponse(w, http.StatusNotFound, "event not found")
+			return
+		}
+		slog.Error("Failed to get event", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "failed to get event")
+		return
+	}
+
+	events := []*event.Event{e}
+	exceptions, err := rt.EventService.GetOccurrenceExceptions(r.Context(), events)
+	if err != nil {
+		slog.Error("Failed to get occurrence exceptions", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "failed to get event")
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%s.ics"`, eventID))
+	writeCalendar(w, "", events, exceptions)
+}
+
+func (rt *Router) CalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	u, err := rt.UserService.GetByCalendarToken(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			ErrorResponse(w, http.StatusNotFound, "calendar not found")
+			return
+		}
+		slog.Error("Failed to get calendar", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "failed to get calendar")
+		return
+	}
+
+	events, err := rt.EventService.GetAttendingEvents(r.Context(), u.UUID.String(), nil)
+	if err != nil {
+		slog.Error("Failed to get attending events", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "failed to get attending events")
+		return
+	}
+
+	exceptions, err := rt.EventService.GetOccurrenceExceptions(r.Context(), events)
+	if err != nil {
+		slog.Error("Failed to get occurrence exceptions", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "failed to get attending events")
+		return
+	}
+
+	writeCalendar(w, "Convenly - "+u.Name, events, exceptions)
+}
+
+func (rt *Router) CalendarTokenHandler(w http.ResponseWriter, r *http.Request) {
+	token, err := rt.UserService.GetCalendarToken(r.Context(), getUserID(r))
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, "failed to get calendar token")
+		return
+	}
+	if token == "" {
+		// the feed exists, but its URL was shown when it was created
+		JSONResponse(w, http.StatusOK, map[string]string{})
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"feed_url": calendarFeedPath(token)})
+}
+
+func (rt *Router) RotateCalendarTokenHandler(w http.ResponseWriter, r *http.Request) {
+	token, err := rt.UserService.RotateCalendarToken(r.Context(), getUserID(r))
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, "failed to rotate calendar token")
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"feed_url": calendarFeedPath(token)})
+}
+
+func calendarFeedPath(token string) string {
+	return "/api/calendar/" + token + ".ics"
+}
+
+func writeCalendar(w http.ResponseWriter, name string, events []*event.Event, exceptions map[string][]event.OccurrenceException) {
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.WriteHeader(http.StatusOK)
+	if err := ical.Write(w, name, events, exceptions, time.Now()); err != nil {
+		slog.Warn("Failed to write calendar", "err", err)
+	}
+}
+
+func writeAttendeesCSV(w io.Writer, attendees []event.Attendee) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"name", "email", "registered_at", "recurrence_id"}); err != nil {
+		return err
+	}
+	for _, a := range attendees {
+		recurrenceID := ""
+		if a.RecurrenceID != nil {
+			recurrenceID = a.RecurrenceID.UTC().Format(time.RFC3339)
+		}
+		err := cw.Write([]string{
+			csvSafe(a.Name),
+			csvSafe(a.Email),
+			a.RegisteredAt.UTC().Format(time.RFC3339),
+			recurrenceID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvSafe neutralises values that spreadsheet applications would otherwise
+// evaluate as formulas.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
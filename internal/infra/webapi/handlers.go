@@ -2,7 +2,9 @@ package webapi
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,6 +13,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/domain/security"
 	"github.com/kapiw04/convenly/internal/domain/user"
 )
 
@@ -23,7 +26,7 @@ func (rt *Router) RegisterUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = rt.UserService.Register(registerRequest.Name, registerRequest.Email, registerRequest.Password)
+	err = rt.AccountService.Register(r.Context(), registerRequest.Name, registerRequest.Email, registerRequest.Password)
 	if err != nil {
 		slog.Error("Error: ", "err", err)
 		switch err {
@@ -52,21 +55,21 @@ func (rt *Router) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, "empty fields")
 		return
 	}
-	sessionID, err := rt.UserService.Login(loginRequest.Email, loginRequest.Password)
+	device := user.Device{UserAgent: r.UserAgent(), IP: clientIP(r)}
+	session, err := rt.UserService.Login(r.Context(), loginRequest.Email, loginRequest.Password, device)
+	var lockedOut *security.LockedOutError
+	if errors.As(err, &lockedOut) {
+		TooManyAttemptsResponse(w, lockedOut)
+		return
+	}
 	if err != nil {
 		slog.Error("Login failed: %v", "err", err)
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
 	slog.Info("User logged in successfully: %s", "email", loginRequest.Email)
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session-id",
-		Quoted:   false,
-		Value:    sessionID,
-		HttpOnly: true,
-		Secure:   true,
-	})
-	user, err := rt.UserService.GetByEmail(loginRequest.Email)
+	setSessionCookie(w, session)
+	user, err := rt.UserService.GetByEmail(r.Context(), loginRequest.Email)
 	if err != nil {
 		slog.Error("Failed to get user after login: %v", "err", err)
 		ErrorResponse(w, http.StatusInternalServerError, "internal server error: "+err.Error())
@@ -76,19 +79,101 @@ func (rt *Router) LoginHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (rt *Router) LogoutHandler(w http.ResponseWriter, r *http.Request) {
-	sessionID := r.Context().Value(ctxSessionID).(string)
+	sessionID, _ := r.Context().Value(ctxSessionID).(string)
 	if sessionID == "" {
 		ErrorResponse(w, http.StatusBadRequest, "missing session ID")
 		return
 	}
-	err := rt.UserService.Logout(sessionID)
+	err := rt.UserService.Logout(r.Context(), sessionID)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
+	clearSessionCookie(w)
 	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// LogoutEverywhereHandler ends all sessions of the user, including the one
+// making the request, and revokes their refresh tokens.
+func (rt *Router) LogoutEverywhereHandler(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	err := rt.UserService.LogoutEverywhere(r.Context(), userID)
+	if err == nil {
+		err = rt.TokenService.RevokeAll(r.Context(), userID)
+	}
+	if err != nil {
+		slog.Error("Failed to log out everywhere", "userID", userID, "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	clearSessionCookie(w)
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ListSessionsHandler lists where the user is logged in, marking the session
+// of the request as current.
+func (rt *Router) ListSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	sessions, err := rt.UserService.ListSessions(r.Context(), getUserID(r))
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, "failed to list sessions")
+		return
+	}
+
+	type sessionResponse struct {
+		*user.Session
+		Current bool `json:"current"`
+	}
+	// requests with a bearer token have no current session
+	current, _ := r.Context().Value(ctxSession).(*user.Session)
+	resp := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, sessionResponse{Session: s, Current: current != nil && s.ID == current.ID})
+	}
+	JSONResponse(w, http.StatusOK, resp)
+}
+
+// RevokeSessionHandler logs the user out of one of their sessions, which may
+// be the one making the request.
+func (rt *Router) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	err := rt.UserService.RevokeSession(r.Context(), getUserID(r), sessionID)
+	switch {
+	case errors.Is(err, user.ErrSessionNotFound):
+		ErrorResponse(w, http.StatusNotFound, "session not found")
+		return
+	case err != nil:
+		ErrorResponse(w, http.StatusInternalServerError, "failed to revoke session")
+		return
+	}
+	if current, _ := r.Context().Value(ctxSession).(*user.Session); current != nil && current.ID.String() == sessionID {
+		clearSessionCookie(w)
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// setSessionCookie sends the session cookie, expiring along with the session.
+func setSessionCookie(w http.ResponseWriter, session *user.Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session-id",
+		Quoted:   false,
+		Value:    session.Token,
+		Expires:  session.ExpiresAt,
+		MaxAge:   max(int(time.Until(session.ExpiresAt).Seconds()), 1),
+		HttpOnly: true,
+		Secure:   true,
+	})
+}
+
+func clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session-id",
+		Value:    "",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+	})
+}
+
 func (rt *Router) CreateEventHandler(w http.ResponseWriter, r *http.Request) {
 	d := json.NewDecoder(r.Body)
 	var addEventRequest CreateEventRequest
@@ -98,26 +183,48 @@ func (rt *Router) CreateEventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	date, err := time.Parse(time.RFC3339, addEventRequest.Date)
+	startsAt, err := time.Parse(time.RFC3339, addEventRequest.StartsAt)
 	if err != nil {
-		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		ErrorResponse(w, http.StatusBadRequest, "bad request: invalid starts_at: "+err.Error())
+		return
+	}
+	endsAt, err := time.Parse(time.RFC3339, addEventRequest.EndsAt)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: invalid ends_at: "+err.Error())
 		return
 	}
+	timezone := addEventRequest.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	status := event.StatusPublished
+	if addEventRequest.Status != "" {
+		status, err = event.ParseStatus(addEventRequest.Status)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+			return
+		}
+	}
 	uid := getUserID(r)
 
 	e := &event.Event{
 		EventID:     uuid.New().String(),
 		Name:        addEventRequest.Name,
 		Description: addEventRequest.Description,
-		Date:        date,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		Timezone:    timezone,
+		Recurrence:  addEventRequest.Recurrence,
 		Latitude:    addEventRequest.Latitude,
 		Longitude:   addEventRequest.Longitude,
 		Fee:         addEventRequest.Fee,
+		Capacity:    addEventRequest.Capacity,
 		OrganizerID: uid,
 		Tags:        addEventRequest.Tags,
+		Status:      status,
 	}
 
-	err = rt.EventService.CreateEvent(e)
+	err = rt.EventService.CreateEvent(r.Context(), e)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
@@ -125,24 +232,100 @@ func (rt *Router) CreateEventHandler(w http.ResponseWriter, r *http.Request) {
 	JSONResponse(w, http.StatusCreated, map[string]string{"status": "ok"})
 }
 
+func (rt *Router) UpdateEventHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	d := json.NewDecoder(r.Body)
+	var updateRequest UpdateEventRequest
+	if err := d.Decode(&updateRequest); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+
+	if updateRequest.ClearCapacity && updateRequest.Capacity != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+event.ErrCapacityConflict.Error())
+		return
+	}
+
+	upd := &event.EventUpdate{
+		Name:          updateRequest.Name,
+		Description:   updateRequest.Description,
+		Timezone:      updateRequest.Timezone,
+		Recurrence:    updateRequest.Recurrence,
+		Latitude:      updateRequest.Latitude,
+		Longitude:     updateRequest.Longitude,
+		Fee:           updateRequest.Fee,
+		Capacity:      updateRequest.Capacity,
+		ClearCapacity: updateRequest.ClearCapacity,
+		Tags:          updateRequest.Tags,
+	}
+	if updateRequest.StartsAt != nil {
+		startsAt, err := time.Parse(time.RFC3339, *updateRequest.StartsAt)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "bad request: invalid starts_at: "+err.Error())
+			return
+		}
+		upd.StartsAt = &startsAt
+	}
+	if updateRequest.EndsAt != nil {
+		endsAt, err := time.Parse(time.RFC3339, *updateRequest.EndsAt)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "bad request: invalid ends_at: "+err.Error())
+			return
+		}
+		upd.EndsAt = &endsAt
+	}
+	if updateRequest.Status != nil {
+		status, err := event.ParseStatus(*updateRequest.Status)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+			return
+		}
+		upd.Status = &status
+	}
+
+	e, err := rt.EventService.UpdateEvent(r.Context(), userID, eventID, upd)
+	if err != nil {
+		switch {
+		case errors.Is(err, event.ErrEventNotFound):
+			ErrorResponse(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, event.ErrNotOrganizer):
+			ErrorResponse(w, http.StatusForbidden, "you can only edit your own events")
+		case errors.Is(err, event.ErrInvalidStatusTransition):
+			ErrorResponse(w, http.StatusConflict, err.Error())
+		default:
+			ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		}
+		return
+	}
+	JSONResponse(w, http.StatusOK, e)
+}
+
 func (rt *Router) ListEventsHandler(w http.ResponseWriter, r *http.Request) {
 	filter := &event.EventFilter{}
 
-	if page := r.URL.Query().Get("page"); page != "" {
-		p, err := strconv.Atoi(page)
-		if err != nil || p < 1 {
-			ErrorResponse(w, http.StatusBadRequest, "invalid page format")
+	if r.URL.Query().Has("cursor") {
+		if r.URL.Query().Get("page") != "" {
+			ErrorResponse(w, http.StatusBadRequest, event.ErrPaginationConflict.Error())
 			return
 		}
-		pageSize := 12
-		if ps := r.URL.Query().Get("page_size"); ps != "" {
-			pageSize, err = strconv.Atoi(ps)
-			if err != nil || pageSize < 1 || pageSize > 100 {
-				ErrorResponse(w, http.StatusBadRequest, "invalid page_size format (1-100)")
-				return
-			}
+		keyset, err := parseKeyset(r, 12)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Keyset = keyset
+	} else {
+		pagination, err := parsePagination(r, 12)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if pagination == nil {
+			pagination = &event.Pagination{Page: 1, PageSize: 12}
 		}
-		filter.Pagination = &event.Pagination{Page: p, PageSize: pageSize}
+		filter.Pagination = pagination
 	}
 
 	if dateFrom := r.URL.Query().Get("date_from"); dateFrom != "" {
@@ -194,28 +377,61 @@ func (rt *Router) ListEventsHandler(w http.ResponseWriter, r *http.Request) {
 		filter.Tags = strings.Split(tags, ",")
 	}
 
-	hasFilters := filter.DateFrom != nil || filter.DateTo != nil ||
-		filter.MinFee != nil || filter.MaxFee != nil || len(filter.Tags) > 0 ||
-		filter.Pagination != nil
+	filter.Query = strings.TrimSpace(r.URL.Query().Get("q"))
 
-	var events []*event.Event
-	var err error
+	lat, lng := r.URL.Query().Get("lat"), r.URL.Query().Get("lng")
+	if lat != "" || lng != "" {
+		latitude, err := strconv.ParseFloat(lat, 64)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "invalid lat format, lat and lng must be given together")
+			return
+		}
+		longitude, err := strconv.ParseFloat(lng, 64)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "invalid lng format, lat and lng must be given together")
+			return
+		}
+		filter.Near = &event.GeoPoint{Latitude: latitude, Longitude: longitude}
+	}
 
-	if hasFilters {
-		events, err = rt.EventService.GetEventsWithFilters(filter)
-	} else {
-		events, err = rt.EventService.GetAllEvents()
+	if radius := r.URL.Query().Get("radius_km"); radius != "" {
+		km, err := strconv.ParseFloat(radius, 64)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "invalid radius_km format")
+			return
+		}
+		filter.RadiusKm = &km
 	}
 
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		order, err := event.ParseSortOrder(sort)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Sort = order
+	}
+
+	if filter.Keyset != nil {
+		page, err := rt.EventService.ListEventsByCursor(r.Context(), filter)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+			return
+		}
+		JSONResponse(w, http.StatusOK, page)
+		return
+	}
+
+	page, err := rt.EventService.ListEvents(r.Context(), filter)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
-	JSONResponseSlice(w, http.StatusOK, events)
+	JSONResponse(w, http.StatusOK, page)
 }
 
 func (rt *Router) GetUserInfoHandler(w http.ResponseWriter, r *http.Request) {
-	user, err := rt.UserService.GetByUUID(getUserID(r))
+	user, err := rt.UserService.GetByUUID(r.Context(), getUserID(r))
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
@@ -227,7 +443,7 @@ func (rt *Router) GetUserInfoHandler(w http.ResponseWriter, r *http.Request) {
 
 func (rt *Router) BecomeHostHandler(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
-	err := rt.UserService.PromoteToHost(userID)
+	err := rt.UserService.PromoteToHost(r.Context(), userID)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
@@ -238,25 +454,38 @@ func (rt *Router) BecomeHostHandler(w http.ResponseWriter, r *http.Request) {
 func (rt *Router) EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 	eid := chi.URLParam(r, "id")
 	uid := getUserID(r)
-	e, err := rt.EventService.GetEventByID(eid)
+	e, err := rt.EventService.GetVisibleEvent(r.Context(), uid, eid)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
-	attendeesCount, err := rt.EventService.GetAttendeesCount(eid)
+	attendeesCount, err := rt.EventService.GetAttendeesCount(r.Context(), eid)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, "internal server error: "+err.Error())
 		return
 	}
-	isUserAttending := rt.EventService.IsUserAttending(uid, eid)
+	var remainingSeats *int
+	if e.Capacity != nil {
+		// counted like registration does, so series include the fullest
+		// upcoming occurrence
+		seatsTaken, err := rt.EventService.GetSeatsTaken(r.Context(), eid)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, "internal server error: "+err.Error())
+			return
+		}
+		remainingSeats = e.RemainingSeats(seatsTaken)
+	}
+	isUserAttending := rt.EventService.IsUserAttending(r.Context(), uid, eid)
 
 	JSONResponse(w, http.StatusOK, struct {
 		*event.Event
 		AttendeesCount int  `json:"attendees_count"`
+		RemainingSeats *int `json:"remaining_seats,omitempty"`
 		UserRegistered bool `json:"user_registered"`
 	}{
 		Event:          e,
 		AttendeesCount: attendeesCount,
+		RemainingSeats: remainingSeats,
 		UserRegistered: uid != "" && isUserAttending,
 	})
 }
@@ -265,7 +494,7 @@ func (rt *Router) UnregisterFromEventHandler(w http.ResponseWriter, r *http.Requ
 	eventID := chi.URLParam(r, "id")
 	userID := getUserID(r)
 
-	err := rt.EventService.RemoveAttendance(userID, eventID)
+	err := rt.EventService.RemoveAttendance(r.Context(), userID, eventID)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
@@ -277,8 +506,104 @@ func (rt *Router) RegisterForEventHandler(w http.ResponseWriter, r *http.Request
 	eventID := chi.URLParam(r, "id")
 	userID := getUserID(r)
 
-	err := rt.EventService.RegisterAttendance(userID, eventID)
+	position, err := rt.EventService.RegisterAttendance(r.Context(), userID, eventID)
+	if err != nil {
+		switch {
+		case errors.Is(err, event.ErrEventFull), errors.Is(err, event.ErrAlreadyWaitlisted),
+			errors.Is(err, event.ErrAlreadyRegistered), errors.Is(err, event.ErrEventCancelled),
+			errors.Is(err, event.ErrRegistrationClosed):
+			ErrorResponse(w, http.StatusConflict, err.Error())
+		default:
+			ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		}
+		return
+	}
+	if position > 0 {
+		JSONResponse(w, http.StatusAccepted, map[string]any{"status": "waitlisted", "position": position})
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (rt *Router) WaitlistHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	entries, err := rt.EventService.GetWaitlist(r.Context(), userID, eventID)
+	if err != nil {
+		switch {
+		case errors.Is(err, event.ErrEventNotFound):
+			ErrorResponse(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, event.ErrNotOrganizer):
+			ErrorResponse(w, http.StatusForbidden, "only the organizer can see the waitlist")
+		default:
+			ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		}
+		return
+	}
+	JSONResponseSlice(w, http.StatusOK, entries)
+}
+
+func (rt *Router) AttendeesHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	if err := uuid.Validate(eventID); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: invalid event id")
+		return
+	}
+
+	pagination, err := parsePagination(r, 50)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if pagination == nil {
+		pagination = &event.Pagination{Page: 1, PageSize: 50}
+	}
+
+	attendees, err := rt.EventService.ListAttendeeRoster(r.Context(), userID, eventID, pagination)
+	if err != nil {
+		switch {
+		case errors.Is(err, event.ErrEventNotFound):
+			ErrorResponse(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, event.ErrNotOrganizer):
+			ErrorResponse(w, http.StatusForbidden, "only the organizer can see the attendees")
+		default:
+			slog.Error("Failed to get attendees", "err", err)
+			ErrorResponse(w, http.StatusInternalServerError, "failed to get attendees")
+		}
+		return
+	}
+	JSONResponse(w, http.StatusOK, attendees)
+}
+
+func (rt *Router) WaitlistPositionHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	position, err := rt.EventService.GetWaitlistPosition(r.Context(), userID, eventID)
 	if err != nil {
+		if errors.Is(err, event.ErrNotWaitlisted) {
+			ErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]int{"position": position})
+}
+
+func (rt *Router) LeaveWaitlistHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	err := rt.EventService.LeaveWaitlist(r.Context(), userID, eventID)
+	if err != nil {
+		if errors.Is(err, event.ErrNotWaitlisted) {
+			ErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
 		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
 		return
 	}
@@ -292,39 +617,64 @@ func (rt *Router) MyEventsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hosting, err := rt.EventService.GetHostingEvents(userID, nil)
+	pagination, err := parsePagination(r, 50)
 	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, "failed to get hosting events: "+err.Error())
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if pagination == nil {
+		pagination = &event.Pagination{Page: 1, PageSize: 50}
+	}
 
-	attending, err := rt.EventService.GetAttendingEvents(userID, nil)
+	hosting, err := rt.EventService.ListHostingEvents(r.Context(), userID, pagination)
 	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, "failed to get attending events: "+err.Error())
+		ErrorResponse(w, http.StatusInternalServerError, "failed to get hosting events: "+err.Error())
 		return
 	}
 
-	if hosting == nil {
-		hosting = []*event.Event{}
-	}
-	if attending == nil {
-		attending = []*event.Event{}
+	attending, err := rt.EventService.ListAttendingEvents(r.Context(), userID, pagination)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, "failed to get attending events: "+err.Error())
+		return
 	}
 
 	JSONResponse(w, http.StatusOK, struct {
-		Hosting   []*event.Event `json:"hosting"`
-		Attending []*event.Event `json:"attending"`
+		Hosting   *event.Page[*event.Event] `json:"hosting"`
+		Attending *event.Page[*event.Event] `json:"attending"`
 	}{
 		Hosting:   hosting,
 		Attending: attending,
 	})
 }
 
+// DeleteEventHandler cancels the event, keeping it and its attendance.
+// With ?remove=true the event is deleted instead; the organizer can restore
+// it until it is purged.
 func (rt *Router) DeleteEventHandler(w http.ResponseWriter, r *http.Request) {
 	eventID := chi.URLParam(r, "id")
 	userID := getUserID(r)
 
-	eventData, err := rt.EventService.GetEventByID(eventID)
+	if r.URL.Query().Get("remove") != "true" {
+		e, err := rt.EventService.CancelEvent(r.Context(), userID, eventID)
+		if err != nil {
+			switch {
+			case errors.Is(err, event.ErrNotOrganizer):
+				ErrorResponse(w, http.StatusForbidden, "you can only delete your own events")
+			case errors.Is(err, event.ErrInvalidStatusTransition):
+				ErrorResponse(w, http.StatusConflict, "completed events cannot be cancelled")
+			case errors.Is(err, event.ErrEventNotFound):
+				ErrorResponse(w, http.StatusNotFound, "event not found")
+			default:
+				slog.Error("Failed to cancel event", "err", err)
+				ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+		JSONResponse(w, http.StatusOK, e)
+		return
+	}
+
+	eventData, err := rt.EventService.GetEventByID(r.Context(), eventID)
 	if err != nil {
 		ErrorResponse(w, http.StatusNotFound, "event not found")
 		return
@@ -335,7 +685,7 @@ func (rt *Router) DeleteEventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = rt.EventService.DeleteEvent(eventID)
+	err = rt.EventService.DeleteEvent(r.Context(), eventID)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, "failed to delete event: "+err.Error())
 		return
@@ -344,6 +694,26 @@ func (rt *Router) DeleteEventHandler(w http.ResponseWriter, r *http.Request) {
 	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+func (rt *Router) RestoreEventHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	e, err := rt.EventService.RestoreEvent(r.Context(), userID, eventID)
+	if err != nil {
+		switch {
+		case errors.Is(err, event.ErrNotOrganizer):
+			ErrorResponse(w, http.StatusForbidden, "you can only restore your own events")
+		case errors.Is(err, event.ErrEventNotFound):
+			ErrorResponse(w, http.StatusNotFound, "deleted event not found")
+		default:
+			slog.Error("Failed to restore event", "err", err)
+			ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+	JSONResponse(w, http.StatusOK, e)
+}
+
 func (rt *Router) HealthHandler(w http.ResponseWriter, r *http.Request) {
 	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
 }
@@ -352,6 +722,62 @@ func (rt *Router) NotFoundHandler(w http.ResponseWriter, r *http.Request) {
 	ErrorResponse(w, http.StatusNotFound, "path not found")
 }
 
+// parsePagination reads the page and page_size query params. It returns nil
+// when neither was given.
+func parsePagination(r *http.Request, defaultPageSize int) (*event.Pagination, error) {
+	page, ps := r.URL.Query().Get("page"), r.URL.Query().Get("page_size")
+	if page == "" && ps == "" {
+		return nil, nil
+	}
+	p := 1
+	if page != "" {
+		var err error
+		p, err = strconv.Atoi(page)
+		if err != nil || p < 1 {
+			return nil, errors.New("invalid page format")
+		}
+	}
+	pageSize := defaultPageSize
+	if ps != "" {
+		var err error
+		pageSize, err = strconv.Atoi(ps)
+		if err != nil || pageSize < 1 || pageSize > 100 {
+			return nil, errors.New("invalid page_size format (1-100)")
+		}
+	}
+	return &event.Pagination{Page: p, PageSize: pageSize}, nil
+}
+
+// parseKeyset reads the cursor and page_size query params. An empty cursor
+// starts from the first event.
+func parseKeyset(r *http.Request, defaultPageSize int) (*event.Keyset, error) {
+	keyset := &event.Keyset{Limit: defaultPageSize}
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursor, err := event.DecodeCursor(c)
+		if err != nil {
+			return nil, err
+		}
+		keyset.After = cursor
+	}
+	if ps := r.URL.Query().Get("page_size"); ps != "" {
+		pageSize, err := strconv.Atoi(ps)
+		if err != nil || pageSize < 1 || pageSize > 100 {
+			return nil, errors.New("invalid page_size format (1-100)")
+		}
+		keyset.Limit = pageSize
+	}
+	return keyset, nil
+}
+
+// clientIP is the address the request came from, without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func getUserID(r *http.Request) string {
 	userID, ok := r.Context().Value(ctxUserID).(string)
 	if !ok {
@@ -1,16 +1,26 @@
 package webapi_test
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/kapiw04/convenly/internal/app"
+	"github.com/kapiw04/convenly/internal/domain/event"
+	mock_event "github.com/kapiw04/convenly/internal/domain/event/mocks"
+	mock_mail "github.com/kapiw04/convenly/internal/domain/mail/mocks"
+	"github.com/kapiw04/convenly/internal/domain/security"
 	mock_security "github.com/kapiw04/convenly/internal/domain/security/mocks"
+	mock_uow "github.com/kapiw04/convenly/internal/domain/uow/mocks"
 	"github.com/kapiw04/convenly/internal/domain/user"
 	mock_user "github.com/kapiw04/convenly/internal/domain/user/mocks"
+	infrasecurity "github.com/kapiw04/convenly/internal/infra/security"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -34,14 +44,135 @@ func setupMockService(t *testing.T, ctrl *gomock.Controller) (*mock_user.MockUse
 
 func setupServer(t *testing.T, srvc *app.UserService) *httptest.Server {
 	t.Helper()
+	ctrl := setupMockController(t)
+	verifications := mock_user.NewMockEmailVerificationRepo(ctrl)
+	verifications.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("verify-token", nil).AnyTimes()
+	mailer := mock_mail.NewMockMailer(ctrl)
+	mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	accounts := app.NewAccountService(srvc, nil, nil, nil, verifications, mailer, "https://convenly.test", app.DefaultAccountPolicy)
+
 	mux := chi.NewRouter()
-	rt := &webapi.Router{UserService: srvc, Handler: mux}
+	rt := &webapi.Router{UserService: srvc, AccountService: accounts, Handler: mux}
 	mux.Post("/register", rt.RegisterUserHandler)
+	mux.Post("/login", rt.LoginHandler)
 	srv := httptest.NewServer(rt.Handler)
 	t.Cleanup(srv.Close)
 	return srv
 }
 
+func setupEventServer(t *testing.T, ctrl *gomock.Controller) (*mock_event.MockEventRepo, *mock_uow.MockTxManager, *httptest.Server) {
+	t.Helper()
+	eventRepo := mock_event.NewMockEventRepo(ctrl)
+	txManager := mock_uow.NewMockTxManager(ctrl)
+
+	mux := chi.NewRouter()
+	rt := &webapi.Router{EventService: app.NewEventService(eventRepo, txManager), Handler: mux}
+	mux.Delete("/events/{id}", rt.DeleteEventHandler)
+	mux.Post("/events/{id}/restore", rt.RestoreEventHandler)
+	mux.Get("/events/{id}/calendar.ics", rt.EventICSHandler)
+	mux.Get("/events/{id}/attendees", rt.AttendeesHandler)
+	mux.Get("/events/{id}/attendees.csv", rt.AttendeesCSVHandler)
+	srv := httptest.NewServer(rt.Handler)
+	t.Cleanup(srv.Close)
+	return eventRepo, txManager, srv
+}
+
+func doRequest(t *testing.T, method, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestCancelEvent_NotFound(t *testing.T) {
+	ctrl := setupMockController(t)
+	_, txManager, srv := setupEventServer(t, ctrl)
+
+	txManager.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(event.ErrEventNotFound)
+
+	resp := doRequest(t, http.MethodDelete, srv.URL+"/events/event-1")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestCancelEvent_InternalError(t *testing.T) {
+	ctrl := setupMockController(t)
+	_, txManager, srv := setupEventServer(t, ctrl)
+
+	txManager.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+
+	resp := doRequest(t, http.MethodDelete, srv.URL+"/events/event-1")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestRestoreEvent_NotFound(t *testing.T) {
+	ctrl := setupMockController(t)
+	_, txManager, srv := setupEventServer(t, ctrl)
+
+	txManager.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(event.ErrEventNotFound)
+
+	resp := doRequest(t, http.MethodPost, srv.URL+"/events/event-1/restore")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRestoreEvent_InternalError(t *testing.T) {
+	ctrl := setupMockController(t)
+	_, txManager, srv := setupEventServer(t, ctrl)
+
+	txManager.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+
+	resp := doRequest(t, http.MethodPost, srv.URL+"/events/event-1/restore")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestEventICS_NotFound(t *testing.T) {
+	ctrl := setupMockController(t)
+	eventRepo, _, srv := setupEventServer(t, ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(nil, event.ErrEventNotFound)
+
+	resp := doRequest(t, http.MethodGet, srv.URL+"/events/event-1/calendar.ics")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestEventICS_InternalError(t *testing.T) {
+	ctrl := setupMockController(t)
+	eventRepo, _, srv := setupEventServer(t, ctrl)
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), "event-1").Return(nil, errors.New("connection reset"))
+
+	resp := doRequest(t, http.MethodGet, srv.URL+"/events/event-1/calendar.ics")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestAttendees_InvalidEventID(t *testing.T) {
+	ctrl := setupMockController(t)
+	_, _, srv := setupEventServer(t, ctrl)
+
+	for _, path := range []string{"/events/not-a-uuid/attendees", "/events/not-a-uuid/attendees.csv"} {
+		resp := doRequest(t, http.MethodGet, srv.URL+path)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func TestAttendees_InternalError(t *testing.T) {
+	ctrl := setupMockController(t)
+	eventRepo, _, srv := setupEventServer(t, ctrl)
+	eventID := uuid.NewString()
+
+	eventRepo.EXPECT().FindByID(gomock.Any(), eventID).Return(nil, errors.New(`pq: relation "events" does not exist`)).Times(2)
+
+	for _, path := range []string{"/events/" + eventID + "/attendees", "/events/" + eventID + "/attendees.csv"} {
+		resp := doRequest(t, http.MethodGet, srv.URL+path)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(body), "pq:")
+	}
+}
+
 func TestRegister_Success(t *testing.T) {
 	ctrl := setupMockController(t)
 	mockRepo, _, mockHasher, mockSvc := setupMockService(t, ctrl)
@@ -54,10 +185,16 @@ func TestRegister_Success(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(1)
 
+	mockRepo.
+		EXPECT().
+		FindByEmail(gomock.Any(), "alice@example.com").
+		Return(&user.User{UUID: uuid.New(), Name: "Alice", Email: "alice@example.com"}, nil).
+		Times(1)
+
 	srv := setupServer(t, mockSvc)
 
 	body := `{"name":"Alice","email":"alice@example.com","password":"Secret123!"}`
@@ -73,7 +210,7 @@ func TestRegister_EmptyFields(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(0)
 
@@ -91,7 +228,7 @@ func TestRegister_MissingFields(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(0)
 
@@ -109,7 +246,7 @@ func TestRegister_InvalidEmail(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(user.ErrInvalidEmailFormat).
 		Times(1)
 
@@ -133,7 +270,7 @@ func TestRegister_WeakPassword(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(0)
 
@@ -151,7 +288,7 @@ func TestRegister_PasswordTooShort(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(0)
 
@@ -169,7 +306,7 @@ func TestRegister_PasswordTooLong(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(0)
 
@@ -193,7 +330,7 @@ func TestRegister_UsernameTooShort(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(user.ErrUsernameTooShort).
 		Times(1)
 
@@ -217,10 +354,16 @@ func TestRegister_UsernameExactlyFiveChars(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(1)
 
+	mockRepo.
+		EXPECT().
+		FindByEmail(gomock.Any(), "bobby@example.com").
+		Return(&user.User{UUID: uuid.New(), Name: "Bobby", Email: "bobby@example.com"}, nil).
+		Times(1)
+
 	srv := setupServer(t, mockSvc)
 
 	body := `{"name":"Bobby","email":"bobby@example.com","password":"Secret123!"}`
@@ -241,7 +384,7 @@ func TestRegister_UsernameFourChars(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		Save(gomock.Any()).
+		Save(gomock.Any(), gomock.Any()).
 		Return(user.ErrUsernameTooShort).
 		Times(1)
 
@@ -252,3 +395,30 @@ func TestRegister_UsernameFourChars(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 }
+
+func TestLogin_TooManyAttempts(t *testing.T) {
+	ctrl := setupMockController(t)
+	mockRepo, _, mockHasher, mockSvc := setupMockService(t, ctrl)
+	mockSvc.LimitLogins(nil, infrasecurity.NewMemoryLoginLimiter(security.LockoutPolicy{
+		MaxFailures: 1,
+		BaseDelay:   time.Minute,
+		MaxDelay:    time.Hour,
+		Window:      time.Hour,
+	}))
+
+	mockRepo.EXPECT().FindByEmail(gomock.Any(), "alice@example.com").Return(&user.User{PasswordHash: "hashed"}, nil).Times(1)
+	mockHasher.EXPECT().Compare(gomock.Any(), "hashed").Return(false).Times(1)
+
+	srv := setupServer(t, mockSvc)
+
+	body := `{"email":"alice@example.com","password":"Wrong123!"}`
+	res, err := http.Post(srv.URL+"/login", "application/json", strings.NewReader(body))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// the password is not checked while the account is locked out
+	res, err = http.Post(srv.URL+"/login", "application/json", strings.NewReader(body))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "60", res.Header.Get("Retry-After"))
+}
@@ -2,9 +2,11 @@ package webapi
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/kapiw04/convenly/internal/app"
 	"github.com/kapiw04/convenly/internal/domain/user"
@@ -25,29 +27,109 @@ func AclMiddleware(requiredRoles ...user.Role) func(next http.Handler) http.Hand
 	}
 }
 
-func AuthMiddleware(srvc *app.UserService) func(next http.Handler) http.Handler {
+// VerifiedEmailMiddleware lets through only users who have verified their
+// email. It has to run after AuthMiddleware.
+func VerifiedEmailMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, _ := r.Context().Value(ctxEmailVerified).(bool)
+		if !verified {
+			slog.Warn("Email verification check failed", "userID", r.Context().Value(ctxUserID))
+			ErrorResponse(w, http.StatusForbidden, user.ErrEmailNotVerified.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// errNoCredentials is returned by an Authenticator when the request carries
+// no credential of its kind, so that the next one can try.
+var errNoCredentials = errors.New("no credentials")
+
+// Principal is who an authenticated request is made by.
+type Principal struct {
+	User *user.User
+	// Session is the cookie session of the request; it is nil for requests
+	// authenticated by a bearer token.
+	Session *user.Session
+}
+
+// Authenticator identifies the user making a request from one kind of
+// credential.
+type Authenticator interface {
+	Authenticate(w http.ResponseWriter, r *http.Request) (*Principal, error)
+}
+
+// SessionAuthenticator accepts the session-id cookie.
+type SessionAuthenticator struct {
+	Users *app.UserService
+}
+
+func (a SessionAuthenticator) Authenticate(w http.ResponseWriter, r *http.Request) (*Principal, error) {
+	c, err := r.Cookie("session-id")
+	if err != nil {
+		return nil, errNoCredentials
+	}
+	u, session, err := a.Users.Authenticate(r.Context(), c.Value)
+	if err != nil {
+		return nil, err
+	}
+	// the session was renewed, so the cookie has to last as long
+	setSessionCookie(w, session)
+	return &Principal{User: u, Session: session}, nil
+}
+
+// BearerAuthenticator accepts an access token in the Authorization header.
+type BearerAuthenticator struct {
+	Tokens *app.TokenService
+}
+
+func (a BearerAuthenticator) Authenticate(w http.ResponseWriter, r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, errNoCredentials
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return nil, errors.New("unsupported authorization scheme")
+	}
+	u, err := a.Tokens.Authenticate(r.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{User: u}, nil
+}
+
+// AuthMiddleware lets through requests one of the authenticators accepts.
+// They are tried in order; the first that finds its kind of credential
+// decides.
+func AuthMiddleware(authenticators ...Authenticator) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			var sessionID string
-			if c, err := r.Cookie("session-id"); err == nil {
-				sessionID = c.Value
-			} else {
-				slog.Warn("No session cookie found", "err", err)
-				ErrorResponse(w, http.StatusUnauthorized, "unauthorized")
-				return
-			}
-			user, err := srvc.GetBySessionID(sessionID)
-			if err != nil {
-				slog.Warn("Invalid session ID", "err", err)
-				ErrorResponse(w, http.StatusUnauthorized, "unauthorized")
+			for _, a := range authenticators {
+				p, err := a.Authenticate(w, r)
+				if errors.Is(err, errNoCredentials) {
+					continue
+				}
+				if err != nil {
+					slog.Warn("Invalid credentials", "err", err)
+					ErrorResponse(w, http.StatusUnauthorized, "unauthorized")
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), ctxUserID, p.User.UUID.String())
+				ctx = context.WithValue(ctx, ctxUserRole, p.User.Role)
+				ctx = context.WithValue(ctx, ctxEmailVerified, p.User.EmailVerified)
+				if p.Session != nil {
+					ctx = context.WithValue(ctx, ctxSessionID, p.Session.Token)
+					ctx = context.WithValue(ctx, ctxSession, p.Session)
+				}
+
+				slog.Info("Authenticated user", "userID", p.User.UUID.String(), "role", p.User.Role)
+				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
-			ctx := context.WithValue(r.Context(), ctxUserID, user.UUID.String())
-			ctx = context.WithValue(ctx, ctxSessionID, sessionID)
-			ctx = context.WithValue(ctx, ctxUserRole, user.Role)
-
-			slog.Info("Authenticated user", "userID", user.UUID.String(), "role", user.Role)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			slog.Warn("No credentials found")
+			ErrorResponse(w, http.StatusUnauthorized, "unauthorized")
 		})
 	}
 }
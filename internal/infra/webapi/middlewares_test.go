@@ -1,15 +1,19 @@
 package webapi_test
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kapiw04/convenly/internal/app"
+	domainsecurity "github.com/kapiw04/convenly/internal/domain/security"
 	mock_security "github.com/kapiw04/convenly/internal/domain/security/mocks"
 	"github.com/kapiw04/convenly/internal/domain/user"
 	mock_user "github.com/kapiw04/convenly/internal/domain/user/mocks"
+	"github.com/kapiw04/convenly/internal/infra/security"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -33,8 +37,13 @@ func TestAuthMiddleware_Success(t *testing.T) {
 
 	mockSessionRepo.
 		EXPECT().
-		Get("valid-session-id").
-		Return(testUser, nil).
+		Get(gomock.Any(), "valid-session-id").
+		Return(&user.Session{Token: "valid-session-id", UserID: testUser.UUID, ExpiresAt: time.Now().Add(time.Hour)}, nil).
+		Times(1)
+	mockUserRepo.
+		EXPECT().
+		FindByUUID(gomock.Any(), testUser.UUID.String()).
+		Return(&testUser, nil).
 		Times(1)
 
 	handlerCalled := false
@@ -43,7 +52,7 @@ func TestAuthMiddleware_Success(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	middleware := webapi.AuthMiddleware(userSrvc)
+	middleware := webapi.AuthMiddleware(webapi.SessionAuthenticator{Users: userSrvc})
 	handler := middleware(testHandler)
 
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
@@ -54,6 +63,12 @@ func TestAuthMiddleware_Success(t *testing.T) {
 
 	require.Equal(t, http.StatusOK, w.Code)
 	require.True(t, handlerCalled)
+
+	// the renewed session is sent back to the browser
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, "valid-session-id", cookies[0].Value)
+	require.Greater(t, cookies[0].MaxAge, 0)
 }
 
 func TestAuthMiddleware_MissingCookie(t *testing.T) {
@@ -70,7 +85,7 @@ func TestAuthMiddleware_MissingCookie(t *testing.T) {
 		handlerCalled = true
 	})
 
-	middleware := webapi.AuthMiddleware(userSrvc)
+	middleware := webapi.AuthMiddleware(webapi.SessionAuthenticator{Users: userSrvc})
 	handler := middleware(testHandler)
 
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
@@ -93,8 +108,8 @@ func TestAuthMiddleware_InvalidSession(t *testing.T) {
 
 	mockSessionRepo.
 		EXPECT().
-		Get("invalid-session-id").
-		Return(user.User{}, user.ErrUserNotFound).
+		Get(gomock.Any(), "invalid-session-id").
+		Return(nil, user.ErrSessionExpired).
 		Times(1)
 
 	handlerCalled := false
@@ -102,7 +117,7 @@ func TestAuthMiddleware_InvalidSession(t *testing.T) {
 		handlerCalled = true
 	})
 
-	middleware := webapi.AuthMiddleware(userSrvc)
+	middleware := webapi.AuthMiddleware(webapi.SessionAuthenticator{Users: userSrvc})
 	handler := middleware(testHandler)
 
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
@@ -114,3 +129,246 @@ func TestAuthMiddleware_InvalidSession(t *testing.T) {
 	require.Equal(t, http.StatusUnauthorized, w.Code)
 	require.False(t, handlerCalled)
 }
+
+func TestAuthMiddleware_BearerToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockUserRepo := mock_user.NewMockUserRepo(ctrl)
+	userSrvc := app.NewUserService(mockUserRepo, mock_user.NewMockSessionRepo(ctrl), mock_security.NewMockHasher(ctrl))
+	signer := security.NewJWTSigner([]byte("secret"))
+	tokenSrvc := app.NewTokenService(userSrvc, mock_user.NewMockRefreshTokenRepo(ctrl), signer, time.Minute, time.Hour)
+
+	testUser := user.User{UUID: uuid.New(), Name: "Alice", Role: user.HOST}
+	mockUserRepo.EXPECT().FindByUUID(gomock.Any(), testUser.UUID.String()).Return(&testUser, nil)
+
+	token, err := signer.Sign(domainsecurity.Claims{
+		Subject:   testUser.UUID.String(),
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	handlerCalled := false
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	})
+	// the host-only ACL passes only if the role reached the request context
+	handler := webapi.AuthMiddleware(
+		webapi.BearerAuthenticator{Tokens: tokenSrvc},
+		webapi.SessionAuthenticator{Users: userSrvc},
+	)(webapi.AclMiddleware(user.HOST)(testHandler))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, handlerCalled)
+	require.Empty(t, w.Result().Cookies())
+}
+
+func TestAuthMiddleware_InvalidBearerToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	userSrvc := app.NewUserService(mock_user.NewMockUserRepo(ctrl), mock_user.NewMockSessionRepo(ctrl), mock_security.NewMockHasher(ctrl))
+	tokenSrvc := app.NewTokenService(userSrvc, mock_user.NewMockRefreshTokenRepo(ctrl), security.NewJWTSigner([]byte("secret")), time.Minute, time.Hour)
+
+	handlerCalled := false
+	handler := webapi.AuthMiddleware(
+		webapi.BearerAuthenticator{Tokens: tokenSrvc},
+		webapi.SessionAuthenticator{Users: userSrvc},
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	}))
+
+	for _, header := range []string{"Bearer not-a-token", "Basic YWxpY2U6c2VjcmV0"} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code, header)
+	}
+	require.False(t, handlerCalled)
+}
+
+func TestVerifiedEmailMiddleware(t *testing.T) {
+	for _, verified := range []bool{false, true} {
+		ctrl := gomock.NewController(t)
+		mockUserRepo := mock_user.NewMockUserRepo(ctrl)
+		mockSessionRepo := mock_user.NewMockSessionRepo(ctrl)
+		userSrvc := app.NewUserService(mockUserRepo, mockSessionRepo, mock_security.NewMockHasher(ctrl))
+
+		testUser := user.User{UUID: uuid.New(), Email: "alice@example.com", Role: user.ATTENDEE, EmailVerified: verified}
+		mockSessionRepo.EXPECT().Get(gomock.Any(), "valid-session-id").
+			Return(&user.Session{Token: "valid-session-id", UserID: testUser.UUID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+		mockUserRepo.EXPECT().FindByUUID(gomock.Any(), testUser.UUID.String()).Return(&testUser, nil)
+
+		handlerCalled := false
+		handler := webapi.AuthMiddleware(webapi.SessionAuthenticator{Users: userSrvc})(
+			webapi.VerifiedEmailMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalled = true
+			})),
+		)
+
+		req := httptest.NewRequest(http.MethodPost, "/test", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: "valid-session-id"})
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		require.Equal(t, verified, handlerCalled)
+		if !verified {
+			require.Equal(t, http.StatusForbidden, w.Code)
+		}
+		ctrl.Finish()
+	}
+}
+
+func TestRateLimitMiddleware_LimitsByIP(t *testing.T) {
+	limit := domainsecurity.RateLimit{Requests: 2, Per: time.Minute}
+	handler := webapi.RateLimitMiddleware(security.NewMemoryRateLimiter(), "anonymous", limit)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }),
+	)
+
+	serve := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("203.0.113.7:1234")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, "30", w.Header().Get("X-RateLimit-Reset"))
+
+	// the port does not matter
+	w = serve("203.0.113.7:5678")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = serve("203.0.113.7:1234")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	w = serve("198.51.100.1:1234")
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimitMiddleware_NestedReportsTightestBucket(t *testing.T) {
+	store := security.NewMemoryRateLimiter()
+	outer := webapi.RateLimitMiddleware(store, "outer", domainsecurity.RateLimit{Requests: 2, Per: time.Minute})
+	inner := webapi.RateLimitMiddleware(store, "inner", domainsecurity.RateLimit{Requests: 5, Per: time.Minute})
+	handler := outer(inner(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }),
+	))
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve()
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = serve()
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = serve()
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// the tighter bucket inside is reported over the looser one outside
+	store = security.NewMemoryRateLimiter()
+	outer = webapi.RateLimitMiddleware(store, "outer", domainsecurity.RateLimit{Requests: 5, Per: time.Minute})
+	inner = webapi.RateLimitMiddleware(store, "inner", domainsecurity.RateLimit{Requests: 2, Per: time.Minute})
+	handler = outer(inner(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }),
+	))
+	w = serve()
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestRouter_LimitsUnauthenticatedRequestsByIP(t *testing.T) {
+	limits := webapi.RateLimits{
+		Store:   security.NewMemoryRateLimiter(),
+		PreAuth: domainsecurity.RateLimit{Requests: 2, Per: time.Minute},
+	}
+	router := webapi.NewRouter(nil, nil, nil, nil, limits)
+
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, serve())
+	require.Equal(t, http.StatusUnauthorized, serve())
+	require.Equal(t, http.StatusTooManyRequests, serve())
+}
+
+func TestRateLimitMiddleware_KeysByUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockUserRepo := mock_user.NewMockUserRepo(ctrl)
+	mockSessionRepo := mock_user.NewMockSessionRepo(ctrl)
+	mockLimiter := mock_security.NewMockRateLimiter(ctrl)
+	userSrvc := app.NewUserService(mockUserRepo, mockSessionRepo, mock_security.NewMockHasher(ctrl))
+	testUser := user.User{UUID: uuid.New(), Role: user.ATTENDEE}
+	limit := domainsecurity.RateLimit{Requests: 10, Per: time.Minute}
+
+	mockSessionRepo.EXPECT().Get(gomock.Any(), "valid-session-id").
+		Return(&user.Session{Token: "valid-session-id", UserID: testUser.UUID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockUserRepo.EXPECT().FindByUUID(gomock.Any(), testUser.UUID.String()).Return(&testUser, nil)
+	mockLimiter.EXPECT().Take(gomock.Any(), "authenticated:user:"+testUser.UUID.String(), limit, gomock.Any()).
+		Return(domainsecurity.RateLimitResult{Allowed: false, RetryAfter: 1500 * time.Millisecond}, nil)
+
+	handler := webapi.AuthMiddleware(webapi.SessionAuthenticator{Users: userSrvc})(
+		webapi.RateLimitMiddleware(mockLimiter, "authenticated", limit)(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }),
+		),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: "valid-session-id"})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "2", w.Header().Get("Retry-After"))
+}
+
+func TestRateLimitMiddleware_StoreErrorLetsThrough(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockLimiter := mock_security.NewMockRateLimiter(ctrl)
+	mockLimiter.EXPECT().Take(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domainsecurity.RateLimitResult{}, errors.New("store down"))
+
+	handler := webapi.RateLimitMiddleware(mockLimiter, "anonymous", domainsecurity.RateLimit{Requests: 1, Per: time.Minute})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }),
+	)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
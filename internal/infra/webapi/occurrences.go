@@ -0,0 +1,133 @@
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kapiw04/convenly/internal/domain/event"
+)
+
+// occurrenceErrorResponse writes the response for an error returned by one
+// of the occurrence endpoints.
+func occurrenceErrorResponse(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, event.ErrEventNotFound):
+		ErrorResponse(w, http.StatusNotFound, "event not found")
+	case errors.Is(err, event.ErrOccurrenceNotFound), errors.Is(err, event.ErrNotRecurring):
+		ErrorResponse(w, http.StatusNotFound, event.ErrOccurrenceNotFound.Error())
+	case errors.Is(err, event.ErrNotOrganizer):
+		ErrorResponse(w, http.StatusForbidden, "you can only edit your own events")
+	case errors.Is(err, event.ErrEventFull), errors.Is(err, event.ErrAlreadyRegistered):
+		ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+	}
+}
+
+// recurrenceIDParam parses the recurrence_id URL parameter, which may come
+// percent-encoded as RFC 3339 offsets contain a "+".
+func recurrenceIDParam(r *http.Request) (time.Time, error) {
+	s, err := url.PathUnescape(chi.URLParam(r, "recurrence_id"))
+	if err != nil {
+		return time.Time{}, event.ErrOccurrenceNotFound
+	}
+	return event.ParseRecurrenceID(s)
+}
+
+func (rt *Router) RegisterForOccurrenceHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	recurrenceID, err := recurrenceIDParam(r)
+	if err != nil {
+		occurrenceErrorResponse(w, err)
+		return
+	}
+
+	if err := rt.EventService.RegisterOccurrence(r.Context(), userID, eventID, recurrenceID); err != nil {
+		occurrenceErrorResponse(w, err)
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (rt *Router) UnregisterFromOccurrenceHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	recurrenceID, err := recurrenceIDParam(r)
+	if err != nil {
+		occurrenceErrorResponse(w, err)
+		return
+	}
+
+	if err := rt.EventService.UnregisterOccurrence(r.Context(), userID, eventID, recurrenceID); err != nil {
+		occurrenceErrorResponse(w, err)
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (rt *Router) CancelOccurrenceHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	recurrenceID, err := recurrenceIDParam(r)
+	if err != nil {
+		occurrenceErrorResponse(w, err)
+		return
+	}
+
+	if err := rt.EventService.CancelOccurrence(r.Context(), userID, eventID, recurrenceID); err != nil {
+		occurrenceErrorResponse(w, err)
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (rt *Router) UpdateOccurrenceHandler(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := getUserID(r)
+
+	recurrenceID, err := recurrenceIDParam(r)
+	if err != nil {
+		occurrenceErrorResponse(w, err)
+		return
+	}
+
+	d := json.NewDecoder(r.Body)
+	var updateRequest UpdateOccurrenceRequest
+	if err := d.Decode(&updateRequest); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+
+	var startsAt, endsAt *time.Time
+	if updateRequest.StartsAt != nil {
+		t, err := time.Parse(time.RFC3339, *updateRequest.StartsAt)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "bad request: invalid starts_at: "+err.Error())
+			return
+		}
+		startsAt = &t
+	}
+	if updateRequest.EndsAt != nil {
+		t, err := time.Parse(time.RFC3339, *updateRequest.EndsAt)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "bad request: invalid ends_at: "+err.Error())
+			return
+		}
+		endsAt = &t
+	}
+
+	o, err := rt.EventService.RescheduleOccurrence(r.Context(), userID, eventID, recurrenceID, startsAt, endsAt)
+	if err != nil {
+		occurrenceErrorResponse(w, err)
+		return
+	}
+	JSONResponse(w, http.StatusOK, o)
+}
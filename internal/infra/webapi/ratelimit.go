@@ -0,0 +1,78 @@
+package webapi
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
+)
+
+// RateLimits are the request limits of the route groups. Authenticated
+// requests count against the user and anonymous ones against the client IP.
+// Requests to authenticated routes also count against the client IP before
+// authentication under PreAuth, so ones without valid credentials are limited
+// as well. PreAuth is shared by everyone behind one address, so it should be
+// well above Authenticated. Host routes take from their own bucket on top of
+// the authenticated one. A nil Store turns limiting off, as does a zero limit
+// for its group.
+type RateLimits struct {
+	Store         security.RateLimiter
+	Anonymous     security.RateLimit
+	PreAuth       security.RateLimit
+	Authenticated security.RateLimit
+	Host          security.RateLimit
+}
+
+// RateLimitMiddleware lets through as many requests as limit allows per
+// user, or per client IP before authentication, and answers the rest with
+// 429 Too Many Requests. Buckets are separate for every scope. When several
+// are nested, the X-RateLimit headers describe the tightest bucket.
+func RateLimitMiddleware(store security.RateLimiter, scope string, limit security.RateLimit) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if store == nil || limit.Unlimited() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := store.Take(r.Context(), rateLimitKey(scope, r), limit, time.Now())
+			if err != nil {
+				// better to serve everyone than no one when the store is down
+				slog.Error("Rate limiter failed, letting the request through", "err", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !res.Allowed || tighterThanReported(w, res.Remaining) {
+				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+				w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+				w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			}
+			if !res.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(res.RetryAfter), 1)))
+				ErrorResponse(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func rateLimitKey(scope string, r *http.Request) string {
+	if userID := getUserID(r); userID != "" {
+		return scope + ":user:" + userID
+	}
+	return scope + ":ip:" + clientIP(r)
+}
+
+// tighterThanReported reports whether remaining is below what an outer
+// bucket already put in the headers.
+func tighterThanReported(w http.ResponseWriter, remaining int) bool {
+	reported, err := strconv.Atoi(w.Header().Get("X-RateLimit-Remaining"))
+	return err != nil || remaining < reported
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
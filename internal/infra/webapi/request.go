@@ -11,12 +11,73 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type UpdateProfileRequest struct {
+	Name *string `json:"name,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
 type CreateEventRequest struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
-	Date        string   `json:"date"` // ISO 8601 format
+	StartsAt    string   `json:"starts_at"`            // RFC 3339 format
+	EndsAt      string   `json:"ends_at"`              // RFC 3339 format
+	Timezone    string   `json:"timezone,omitempty"`   // IANA name, defaults to UTC
+	Recurrence  string   `json:"recurrence,omitempty"` // RFC 5545 RRULE
 	Latitude    float64  `json:"latitude"`
 	Longitude   float64  `json:"longitude"`
 	Fee         float32  `json:"fee"`
+	Capacity    *int     `json:"capacity,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+	Status      string   `json:"status,omitempty"` // draft or published, defaults to published
+}
+
+type UpdateEventRequest struct {
+	Name          *string   `json:"name,omitempty"`
+	Description   *string   `json:"description,omitempty"`
+	StartsAt      *string   `json:"starts_at,omitempty"` // RFC 3339 format
+	EndsAt        *string   `json:"ends_at,omitempty"`   // RFC 3339 format
+	Timezone      *string   `json:"timezone,omitempty"`
+	Recurrence    *string   `json:"recurrence,omitempty"` // empty to stop repeating
+	Latitude      *float64  `json:"latitude,omitempty"`
+	Longitude     *float64  `json:"longitude,omitempty"`
+	Fee           *float32  `json:"fee,omitempty"`
+	Capacity      *int      `json:"capacity,omitempty"`
+	ClearCapacity bool      `json:"clear_capacity,omitempty"` // true to remove the capacity limit
+	Tags          *[]string `json:"tags,omitempty"`
+	Status        *string   `json:"status,omitempty"`
+}
+
+type UpdateOccurrenceRequest struct {
+	StartsAt *string `json:"starts_at,omitempty"` // RFC 3339 format
+	EndsAt   *string `json:"ends_at,omitempty"`   // RFC 3339 format
 }
@@ -3,6 +3,9 @@ package webapi
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+
+	"github.com/kapiw04/convenly/internal/domain/security"
 )
 
 func JSONResponse(w http.ResponseWriter, status int, data any) {
@@ -26,3 +29,9 @@ func JSONResponseSlice[T any](w http.ResponseWriter, status int, data []T) {
 func ErrorResponse(w http.ResponseWriter, status int, message string) {
 	JSONResponse(w, status, map[string]string{"error": message})
 }
+
+// TooManyAttemptsResponse tells a locked out client when to try again.
+func TooManyAttemptsResponse(w http.ResponseWriter, err *security.LockedOutError) {
+	w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(err.RetryAfter), 1)))
+	ErrorResponse(w, http.StatusTooManyRequests, err.Error())
+}
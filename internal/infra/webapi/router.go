@@ -12,51 +12,100 @@ import (
 type ctxKey string
 
 const (
-	ctxUserID    ctxKey = "userID"
-	ctxUserRole  ctxKey = "userRole"
-	ctxSessionID ctxKey = "sessionID"
+	ctxUserID        ctxKey = "userID"
+	ctxUserRole      ctxKey = "userRole"
+	ctxSessionID     ctxKey = "sessionID"
+	ctxSession       ctxKey = "session"
+	ctxEmailVerified ctxKey = "emailVerified"
 )
 
 type Router struct {
-	UserService  *app.UserService
-	EventService *app.EventService
-	Handler      http.Handler
+	UserService    *app.UserService
+	EventService   *app.EventService
+	TokenService   *app.TokenService
+	AccountService *app.AccountService
+	Handler        http.Handler
 }
 
-func NewRouter(userService *app.UserService, eventService *app.EventService) *Router {
+func NewRouter(userService *app.UserService, eventService *app.EventService, tokenService *app.TokenService, accountService *app.AccountService, limits RateLimits) *Router {
 	r := chi.NewRouter()
 	router := &Router{
-		UserService:  userService,
-		EventService: eventService,
-		Handler:      r,
+		UserService:    userService,
+		EventService:   eventService,
+		TokenService:   tokenService,
+		AccountService: accountService,
+		Handler:        r,
 	}
 	r.Use(cors.Handler(cors.Options{
 		AllowCredentials: true,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "Authorization"},
 		AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
 	}))
 
 	r.Get("/api/health", router.HealthHandler)
-	r.Post("/api/register", router.RegisterUserHandler)
-	r.Post("/api/login", router.LoginHandler)
-	r.Get("/api/events", router.ListEventsHandler)
 	r.NotFound(router.NotFoundHandler)
 
+	r.Group(func(anonR chi.Router) {
+		anonR.Use(RateLimitMiddleware(limits.Store, "anonymous", limits.Anonymous))
+		anonR.Post("/api/register", router.RegisterUserHandler)
+		anonR.Post("/api/login", router.LoginHandler)
+		anonR.Post("/api/token", router.IssueTokenHandler)
+		anonR.Post("/api/token/refresh", router.RefreshTokenHandler)
+		anonR.Post("/api/token/revoke", router.RevokeTokenHandler)
+		anonR.Post("/api/password-reset", router.RequestPasswordResetHandler)
+		anonR.Post("/api/password-reset/confirm", router.ConfirmPasswordResetHandler)
+		anonR.Post("/api/verify-email", router.VerifyEmailHandler)
+		anonR.Get("/api/events", router.ListEventsHandler)
+		anonR.Get("/api/calendar/{token}.ics", router.CalendarFeedHandler)
+	})
+
 	r.Group(func(authR chi.Router) {
-		authR.Use(AuthMiddleware(router.UserService))
+		// keyed by client IP, so requests failing authentication are limited
+		// too; its own scope keeps users sharing an IP out of each other's
+		// buckets
+		authR.Use(RateLimitMiddleware(limits.Store, "preauth", limits.PreAuth))
+		authR.Use(AuthMiddleware(
+			BearerAuthenticator{Tokens: router.TokenService},
+			SessionAuthenticator{Users: router.UserService},
+		))
+		authR.Use(RateLimitMiddleware(limits.Store, "authenticated", limits.Authenticated))
 		authR.Get("/api/me", router.GetUserInfoHandler)
+		authR.Patch("/api/me", router.UpdateProfileHandler)
+		authR.Delete("/api/me", router.DeleteAccountHandler)
+		authR.Post("/api/me/password", router.ChangePasswordHandler)
+		authR.Post("/api/me/email", router.ChangeEmailHandler)
 		authR.Post("/api/logout", router.LogoutHandler)
-		authR.Post("/api/become-host", router.BecomeHostHandler)
+		authR.Post("/api/logout-all", router.LogoutEverywhereHandler)
+		authR.With(VerifiedEmailMiddleware).Post("/api/become-host", router.BecomeHostHandler)
+		authR.Post("/api/me/verify-email/resend", router.ResendVerificationHandler)
 		authR.Get("/api/my-events", router.MyEventsHandler)
+		authR.Get("/api/me/sessions", router.ListSessionsHandler)
+		authR.Delete("/api/me/sessions/{id}", router.RevokeSessionHandler)
+		authR.Get("/api/me/calendar", router.CalendarTokenHandler)
+		authR.Post("/api/me/calendar/rotate", router.RotateCalendarTokenHandler)
 		authR.Get("/api/events/{id}", router.EventDetailHandler)
+		authR.Get("/api/events/{id}/calendar.ics", router.EventICSHandler)
 		authR.Post("/api/events/{id}/register", router.RegisterForEventHandler)
 		authR.Delete("/api/events/{id}/unregister", router.UnregisterFromEventHandler)
+		authR.Get("/api/events/{id}/waitlist", router.WaitlistHandler)
+		authR.Get("/api/events/{id}/waitlist/me", router.WaitlistPositionHandler)
+		authR.Delete("/api/events/{id}/waitlist", router.LeaveWaitlistHandler)
+		authR.Post("/api/events/{id}/occurrences/{recurrence_id}/register", router.RegisterForOccurrenceHandler)
+		authR.Delete("/api/events/{id}/occurrences/{recurrence_id}/unregister", router.UnregisterFromOccurrenceHandler)
 
 		authR.Group(func(hostR chi.Router) {
 			hostR.Use(AclMiddleware(user.HOST))
-			hostR.Post("/api/events/add", router.CreateEventHandler)
+			hostR.Use(RateLimitMiddleware(limits.Store, "host", limits.Host))
+			hostR.With(VerifiedEmailMiddleware).Post("/api/events/add", router.CreateEventHandler)
+			hostR.Put("/api/events/{id}", router.UpdateEventHandler)
+			hostR.Patch("/api/events/{id}", router.UpdateEventHandler)
 			hostR.Delete("/api/events/{id}", router.DeleteEventHandler)
+			hostR.Post("/api/events/{id}/restore", router.RestoreEventHandler)
+			hostR.Get("/api/events/{id}/attendees", router.AttendeesHandler)
+			hostR.Patch("/api/events/{id}/occurrences/{recurrence_id}", router.UpdateOccurrenceHandler)
+			hostR.Delete("/api/events/{id}/occurrences/{recurrence_id}", router.CancelOccurrenceHandler)
+			hostR.Get("/api/events/{id}/attendees.csv", router.AttendeesCSVHandler)
 		})
 	})
 
@@ -3,14 +3,18 @@ package webapi
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"time"
 )
 
-func NewServer(addr string, handler http.Handler) *http.Server {
+// NewServer returns a server whose request contexts derive from ctx, so
+// cancelling ctx cancels the work of every in-flight request.
+func NewServer(ctx context.Context, addr string, handler http.Handler) *http.Server {
 	return &http.Server{
 		Addr:         addr,
 		Handler:      handler,
+		BaseContext:  func(net.Listener) context.Context { return ctx },
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
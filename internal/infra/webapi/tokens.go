@@ -0,0 +1,100 @@
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/app"
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/domain/user"
+)
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func tokenResponse(w http.ResponseWriter, pair *app.TokenPair) {
+	JSONResponse(w, http.StatusOK, TokenResponse{
+		AccessToken:  pair.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(time.Until(pair.AccessExpiresAt).Seconds()),
+		RefreshToken: pair.RefreshToken,
+	})
+}
+
+// IssueTokenHandler exchanges an email and password for an access and a
+// refresh token, for clients that do not use the session cookie.
+func (rt *Router) IssueTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+	if req.Email == "" || req.Password == "" {
+		ErrorResponse(w, http.StatusBadRequest, "empty fields")
+		return
+	}
+
+	pair, err := rt.TokenService.IssueTokens(r.Context(), req.Email, req.Password, clientIP(r))
+	var lockedOut *security.LockedOutError
+	switch {
+	case errors.As(err, &lockedOut):
+		TooManyAttemptsResponse(w, lockedOut)
+		return
+	case errors.Is(err, user.ErrInvalidCredentials), errors.Is(err, user.ErrUserNotFound),
+		errors.Is(err, user.ErrInvalidEmailFormat), errors.Is(err, user.ErrPasswordTooShort),
+		errors.Is(err, user.ErrPasswordTooLong), errors.Is(err, user.ErrPasswordTooWeak):
+		ErrorResponse(w, http.StatusUnauthorized, user.ErrInvalidCredentials.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to issue tokens", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	tokenResponse(w, pair)
+}
+
+// RefreshTokenHandler exchanges a refresh token for a new pair. The refresh
+// token is used up.
+func (rt *Router) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+
+	pair, err := rt.TokenService.Refresh(r.Context(), req.RefreshToken)
+	switch {
+	case errors.Is(err, user.ErrInvalidRefreshToken):
+		ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	case err != nil:
+		slog.Error("Failed to refresh tokens", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	tokenResponse(w, pair)
+}
+
+// RevokeTokenHandler makes a refresh token unusable, logging the client out
+// once its access token expires. Unknown tokens are accepted as well.
+func (rt *Router) RevokeTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "bad request: "+err.Error())
+		return
+	}
+
+	if err := rt.TokenService.Revoke(r.Context(), req.RefreshToken); err != nil {
+		slog.Error("Failed to revoke refresh token", "err", err)
+		ErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	JSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"flag"
 	"fmt"
@@ -232,8 +233,8 @@ func generateEvents(ctx context.Context, db *sql.DB, count int, userIDs []string
 		organizerID := userIDs[rand.Intn(len(userIDs))]
 
 		_, err := db.ExecContext(ctx,
-			"INSERT INTO events (event_id, name, description, date, latitude, longitude, fee, organizer_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
-			eventID, name, description, eventDate, loc.lat, loc.lon, fee, organizerID)
+			"INSERT INTO events (event_id, name, description, starts_at, ends_at, latitude, longitude, fee, organizer_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+			eventID, name, description, eventDate, eventDate.Add(2*time.Hour), loc.lat, loc.lon, fee, organizerID)
 		if err != nil {
 			return err
 		}
@@ -303,11 +304,11 @@ func createUsers(ctx context.Context, db *sql.DB, count int, stats *TrafficStats
 
 func createSessions(ctx context.Context, db *sql.DB, userIDs []string, stats *TrafficStats) error {
 	for _, userID := range userIDs {
-		sessionID := uuid.New().String()
+		tokenHash := sha256.Sum256([]byte(uuid.New().String()))
 
 		_, err := db.ExecContext(ctx,
-			"INSERT INTO sessions (user_id, session_id) VALUES ($1, $2)",
-			userID, sessionID)
+			"INSERT INTO sessions (user_id, token_hash, expires_at) VALUES ($1, $2, now() + interval '7 days')",
+			userID, tokenHash[:])
 		if err != nil {
 			stats.mu.Lock()
 			stats.Errors++
@@ -359,8 +360,8 @@ func createEvents(ctx context.Context, db *sql.DB, count int, userIDs []string,
 		organizerID := userIDs[rand.Intn(len(userIDs))]
 
 		_, err := db.ExecContext(ctx,
-			"INSERT INTO events (event_id, name, description, date, latitude, longitude, fee, organizer_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
-			eventID, name, description, eventDate, loc.lat, loc.lon, fee, organizerID)
+			"INSERT INTO events (event_id, name, description, starts_at, ends_at, latitude, longitude, fee, organizer_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+			eventID, name, description, eventDate, eventDate.Add(2*time.Hour), loc.lat, loc.lon, fee, organizerID)
 		if err != nil {
 			stats.mu.Lock()
 			stats.Errors++
@@ -458,10 +459,10 @@ func simulateReadTraffic(ctx context.Context, db *sql.DB, userIDs, eventIDs []st
 			for eventID := range jobs {
 				// Query event details
 				var name, description string
-				var date time.Time
+				var startsAt time.Time
 				err := db.QueryRowContext(ctx,
-					"SELECT name, description, date FROM events WHERE event_id = $1",
-					eventID).Scan(&name, &description, &date)
+					"SELECT name, description, starts_at FROM events WHERE event_id = $1",
+					eventID).Scan(&name, &description, &startsAt)
 				if err == nil {
 					stats.mu.Lock()
 					stats.EventQueries++
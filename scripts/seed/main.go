@@ -29,7 +29,9 @@ type LoginRequest struct {
 type CreateEventRequest struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
-	Date        string   `json:"date"`
+	StartsAt    string   `json:"starts_at"`
+	EndsAt      string   `json:"ends_at"`
+	Timezone    string   `json:"timezone"`
 	Latitude    float64  `json:"latitude"`
 	Longitude   float64  `json:"longitude"`
 	Fee         float32  `json:"fee"`
@@ -184,8 +186,10 @@ func main() {
 			eventIdx := (i*2 + j) % len(dummyEvents)
 			event := dummyEvents[eventIdx]
 			daysFromNow := rand.Intn(60) + 1
-			eventDate := time.Now().AddDate(0, 0, daysFromNow)
-			event.Date = eventDate.Format(time.RFC3339)
+			startsAt := time.Now().AddDate(0, 0, daysFromNow).Truncate(time.Hour)
+			event.StartsAt = startsAt.Format(time.RFC3339)
+			event.EndsAt = startsAt.Add(time.Duration(1+rand.Intn(4)) * time.Hour).Format(time.RFC3339)
+			event.Timezone = "Europe/Warsaw"
 
 			err := createEvent(client, event)
 			if err != nil {
@@ -0,0 +1,163 @@
+package integral
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/user"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func sendAsUser(h http.Handler, method, path, sessionID string, body any) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestAccount_UpdateProfile(t *testing.T) {
+	sqlDb, router, _ := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := RegisterAndLoginUser(t, router.UserService, "Alice", "alice@example.com", "Secret123!")
+
+		name := "Alice Smith"
+		w := sendAsUser(router.Handler, http.MethodPatch, "/api/me", sessionID, webapi.UpdateProfileRequest{Name: &name})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var me user.User
+		require.NoError(t, json.NewDecoder(getMe(router.Handler, sessionID).Body).Decode(&me))
+		require.Equal(t, "Alice Smith", me.Name)
+		require.Equal(t, "alice@example.com", me.Email)
+
+		short := "Al"
+		w = sendAsUser(router.Handler, http.MethodPatch, "/api/me", sessionID, webapi.UpdateProfileRequest{Name: &short})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestAccount_ChangePassword(t *testing.T) {
+	sqlDb, router, _ := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := RegisterAndLoginUser(t, router.UserService, "Alice", "alice@example.com", "Secret123!")
+
+		w := sendAsUser(router.Handler, http.MethodPost, "/api/me/password", sessionID,
+			webapi.ChangePasswordRequest{CurrentPassword: "Wrong123!", NewPassword: "NewSecret123!"})
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		w = sendAsUser(router.Handler, http.MethodPost, "/api/me/password", sessionID,
+			webapi.ChangePasswordRequest{CurrentPassword: "Secret123!", NewPassword: "NewSecret123!"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		require.Equal(t, http.StatusUnauthorized, getMe(router.Handler, sessionID).Code)
+		_, err := router.UserService.Login(context.Background(), "alice@example.com", "Secret123!", user.Device{})
+		require.ErrorIs(t, err, user.ErrInvalidCredentials)
+		_, err = router.UserService.Login(context.Background(), "alice@example.com", "NewSecret123!", user.Device{})
+		require.NoError(t, err)
+	})
+}
+
+func TestAccount_ChangeEmail(t *testing.T) {
+	sqlDb, router, mailer := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := RegisterAndLoginUser(t, router.UserService, "Alice", "alice@example.com", "Secret123!")
+		RegisterAndLoginUser(t, router.UserService, "Bobby", "bob@example.com", "Secret123!")
+
+		w := sendAsUser(router.Handler, http.MethodPost, "/api/me/email", sessionID,
+			webapi.ChangeEmailRequest{Email: "bob@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusConflict, w.Code)
+
+		w = sendAsUser(router.Handler, http.MethodPost, "/api/me/email", sessionID,
+			webapi.ChangeEmailRequest{Email: "alice@new.example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusOK, w.Code)
+		token := lastLinkToken(t, mailer, verifyLinkRe)
+
+		// the email stays until the new address is verified
+		var me user.User
+		require.NoError(t, json.NewDecoder(getMe(router.Handler, sessionID).Body).Decode(&me))
+		require.Equal(t, "alice@example.com", me.Email)
+
+		w = postJSON(router.Handler, "/api/verify-email", webapi.VerifyEmailRequest{Token: token})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		require.NoError(t, json.NewDecoder(getMe(router.Handler, sessionID).Body).Decode(&me))
+		require.Equal(t, "alice@new.example.com", me.Email)
+		require.True(t, me.EmailVerified)
+		_, err := router.UserService.Login(context.Background(), "alice@new.example.com", "Secret123!", user.Device{})
+		require.NoError(t, err)
+	})
+}
+
+func TestAccount_Delete(t *testing.T) {
+	sqlDb, router, _ := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		aliceSessionID := registerHostAndLoginWithName(t, router.UserService, "Alice", "alice@example.com", "Secret123!")
+		createTestEventViaAPI(t, router, aliceSessionID, "Alice's Event", "2025-12-31T23:59:59Z", 10.0, []string{"Music"})
+		events, err := router.EventService.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		aliceEventID := events[0].EventID
+
+		// Alice holds the only seat of Carol's event; Dave waits for it
+		carolSessionID := registerHostAndLoginWithName(t, router.UserService, "Carol", "carol@example.com", "Secret123!")
+		carolEventID := createEventWithCapacity(t, router, router.EventService, carolSessionID, 1)
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, carolEventID, aliceSessionID))
+		daveSessionID := RegisterAndLoginUser(t, router.UserService, "Dave Jones", "dave@example.com", "Secret123!")
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, carolEventID, daveSessionID))
+
+		w := sendAsUser(router.Handler, http.MethodDelete, "/api/me", aliceSessionID, webapi.DeleteAccountRequest{Password: "Wrong123!"})
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		w = sendAsUser(router.Handler, http.MethodDelete, "/api/me", aliceSessionID, webapi.DeleteAccountRequest{Password: "Secret123!"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		require.Equal(t, http.StatusUnauthorized, getMe(router.Handler, aliceSessionID).Code)
+		_, err = router.UserService.GetByEmail(context.Background(), "alice@example.com")
+		require.ErrorIs(t, err, user.ErrUserNotFound)
+		require.Equal(t, http.StatusNotFound, getEventDetail(router.Handler, aliceEventID, daveSessionID))
+
+		dave, err := router.UserService.GetByEmail(context.Background(), "dave@example.com")
+		require.NoError(t, err)
+		require.True(t, router.EventService.IsUserAttending(context.Background(), dave.UUID.String(), carolEventID))
+	})
+}
+
+func TestAccount_DeleteReleasesOccurrenceSeats(t *testing.T) {
+	sqlDb, router, _ := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLoginWithName(t, router.UserService, "Carol", "carol@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, router.EventService, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO")
+		_, err := tx.Exec("UPDATE events SET capacity = 1 WHERE event_id = $1", eventID)
+		require.NoError(t, err)
+
+		// Alice holds the only seat of an upcoming occurrence; Dave waits
+		// for a series seat
+		aliceSessionID := RegisterAndLoginUser(t, router.UserService, "Alice", "alice@example.com", "Secret123!")
+		recurrenceID := upcomingMonday()
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, recurrenceID, "/register", aliceSessionID, nil).Code)
+		daveSessionID := RegisterAndLoginUser(t, router.UserService, "Dave Jones", "dave@example.com", "Secret123!")
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, daveSessionID))
+
+		w := sendAsUser(router.Handler, http.MethodDelete, "/api/me", aliceSessionID, webapi.DeleteAccountRequest{Password: "Secret123!"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		dave, err := router.UserService.GetByEmail(context.Background(), "dave@example.com")
+		require.NoError(t, err)
+		require.True(t, router.EventService.IsUserAttending(context.Background(), dave.UUID.String(), eventID))
+	})
+}
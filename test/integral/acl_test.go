@@ -2,6 +2,7 @@ package integral
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -26,7 +27,8 @@ func TestAclMiddleware_AttendeeCannotCreateEvent(t *testing.T) {
 			Latitude:    42.0,
 			Longitude:   21.37,
 			Fee:         10.0,
-			Date:        "2025-12-31T23:59:59Z",
+			StartsAt:    "2025-12-31T23:59:59Z",
+			EndsAt:      "2026-01-01T01:59:59Z",
 			Tags:        []string{"Music"},
 		}
 		body, err := json.Marshal(req)
@@ -55,7 +57,8 @@ func TestAclMiddleware_HostCanCreateEvent(t *testing.T) {
 			Latitude:    42.0,
 			Longitude:   21.37,
 			Fee:         10.0,
-			Date:        "2025-12-31T23:59:59Z",
+			StartsAt:    "2025-12-31T23:59:59Z",
+			EndsAt:      "2026-01-01T01:59:59Z",
 			Tags:        []string{"Music"},
 		}
 		body, err := json.Marshal(req)
@@ -70,7 +73,7 @@ func TestAclMiddleware_HostCanCreateEvent(t *testing.T) {
 
 		require.Equal(t, http.StatusCreated, w.Code)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 	})
@@ -2,6 +2,7 @@ package integral
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -21,7 +22,7 @@ func TestAttendance_RegisterSuccess(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForAttendance(t, router, hostSessionID)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -35,7 +36,7 @@ func TestAttendance_RegisterSuccess(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		attendees, err := eventSrvc.GetAttendees(eventID)
+		attendees, err := eventSrvc.GetAttendees(context.Background(), eventID)
 		require.NoError(t, err)
 		require.Len(t, attendees, 1)
 	})
@@ -48,7 +49,7 @@ func TestAttendance_RegisterUnauthorized(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForAttendance(t, router, hostSessionID)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -68,7 +69,7 @@ func TestAttendance_UnregisterSuccess(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForAttendance(t, router, hostSessionID)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -88,7 +89,7 @@ func TestAttendance_UnregisterSuccess(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		attendees, err := eventSrvc.GetAttendees(eventID)
+		attendees, err := eventSrvc.GetAttendees(context.Background(), eventID)
 		require.NoError(t, err)
 		require.Len(t, attendees, 0)
 	})
@@ -101,7 +102,7 @@ func TestAttendance_UnregisterUnauthorized(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForAttendance(t, router, hostSessionID)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -121,7 +122,7 @@ func TestAttendance_MultipleAttendees(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForAttendance(t, router, hostSessionID)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -140,7 +141,7 @@ func TestAttendance_MultipleAttendees(t *testing.T) {
 		router.Handler.ServeHTTP(w, req2)
 		require.Equal(t, http.StatusOK, w.Code)
 
-		attendees, err := eventSrvc.GetAttendees(eventID)
+		attendees, err := eventSrvc.GetAttendees(context.Background(), eventID)
 		require.NoError(t, err)
 		require.Len(t, attendees, 2)
 	})
@@ -169,7 +170,7 @@ func TestAttendance_DoubleRegistration(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForAttendance(t, router, hostSessionID)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -186,7 +187,7 @@ func TestAttendance_DoubleRegistration(t *testing.T) {
 		w = httptest.NewRecorder()
 		router.Handler.ServeHTTP(w, req2)
 
-		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, http.StatusConflict, w.Code)
 	})
 }
 
@@ -197,7 +198,7 @@ func TestAttendance_UnregisterWhenNotRegistered(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForAttendance(t, router, hostSessionID)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -237,7 +238,8 @@ func createEventForAttendance(t *testing.T, router *webapi.Router, sessionID str
 		Latitude:    42.0,
 		Longitude:   21.37,
 		Fee:         10.0,
-		Date:        "2025-12-31T23:59:59Z",
+		StartsAt:    "2025-12-31T23:59:59Z",
+		EndsAt:      "2026-01-01T01:59:59Z",
 		Tags:        []string{"Music"},
 	}
 	body, err := json.Marshal(req)
@@ -0,0 +1,105 @@
+package integral
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestAttendees_OrganizerSeesRoster(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 10)
+
+		first := RegisterAndLoginUser(t, userSrvc, "First Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Second Attendee", "attendee2@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, second))
+
+		attendees := listAttendees(t, router.Handler, eventID, hostSessionID, "")
+		require.Len(t, attendees, 2)
+		require.Equal(t, "First Attendee", attendees[0].Name)
+		require.Equal(t, "attendee1@example.com", attendees[0].Email)
+		require.False(t, attendees[0].RegisteredAt.IsZero())
+		require.Equal(t, "Second Attendee", attendees[1].Name)
+
+		page := listAttendeesPage(t, router.Handler, eventID, hostSessionID, "?page=1&page_size=1")
+		require.Len(t, page.Items, 1)
+		require.Equal(t, 2, page.Total)
+		require.True(t, page.HasNext)
+
+		page = listAttendeesPage(t, router.Handler, eventID, hostSessionID, "?page=2&page_size=1")
+		require.Len(t, page.Items, 1)
+		require.Equal(t, "attendee2@example.com", page.Items[0].Email)
+		require.Equal(t, 2, page.Page)
+		require.Equal(t, 1, page.PageSize)
+		require.Equal(t, 2, page.Total)
+		require.False(t, page.HasNext)
+	})
+}
+
+func TestAttendees_NotOrganizer(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		host1SessionID := registerHostAndLogin(t, userSrvc, "host1@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, host1SessionID, 10)
+
+		host2SessionID := registerHostAndLoginWithName(t, userSrvc, "Host 2", "host2@example.com", "Secret123!")
+		attendeeSessionID := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee@example.com", "Secret123!")
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/attendees", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: host2SessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/attendees", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: attendeeSessionID})
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAttendees_InvalidPage(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 10)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/attendees?page=0", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: hostSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func listAttendees(t *testing.T, h http.Handler, eventID, sessionID, query string) []event.Attendee {
+	t.Helper()
+	return listAttendeesPage(t, h, eventID, sessionID, query).Items
+}
+
+func listAttendeesPage(t *testing.T, h http.Handler, eventID, sessionID, query string) *event.Page[event.Attendee] {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/attendees"+query, nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var page event.Page[event.Attendee]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+	return &page
+}
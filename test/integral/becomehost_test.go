@@ -1,6 +1,7 @@
 package integral
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -27,7 +28,7 @@ func TestBecomeHost_Success(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		u, err := userSrvc.GetByEmail("alice@example.com")
+		u, err := userSrvc.GetByEmail(context.Background(), "alice@example.com")
 		require.NoError(t, err)
 		require.Equal(t, user.HOST, u.Role)
 	})
@@ -60,7 +61,7 @@ func TestBecomeHost_VerifyCanCreateEvent(t *testing.T) {
 
 		createTestEventViaAPI(t, router, sessionID, "My Event", "2025-12-31T23:59:59Z", 15.0, []string{"Music"})
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		require.Equal(t, "My Event", events[0].Name)
@@ -85,8 +86,9 @@ func TestBecomeHost_RolePersistedAfterRelogin(t *testing.T) {
 		router.Handler.ServeHTTP(w, logoutReq)
 		require.Equal(t, http.StatusOK, w.Code)
 
-		newSessionID, err := userSrvc.Login("alice@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "alice@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		newSessionID := session.Token
 
 		meReq := httptest.NewRequest(http.MethodGet, "/api/me", nil)
 		meReq.AddCookie(&http.Cookie{Name: "session-id", Value: newSessionID})
@@ -121,7 +123,7 @@ func TestBecomeHost_AlreadyHost(t *testing.T) {
 		router.Handler.ServeHTTP(w, req)
 		require.Equal(t, http.StatusOK, w.Code)
 
-		u, err := userSrvc.GetByEmail("alice@example.com")
+		u, err := userSrvc.GetByEmail(context.Background(), "alice@example.com")
 		require.NoError(t, err)
 		require.Equal(t, user.HOST, u.Role)
 	})
@@ -1,6 +1,7 @@
 package integral
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
@@ -24,14 +25,17 @@ func seedBenchmarkData(b *testing.B, eventRepo event.EventRepo, userID string, c
 			EventID:     uuid.New().String(),
 			Name:        fmt.Sprintf("Benchmark Event %d", i),
 			Description: fmt.Sprintf("Description for benchmark event %d", i),
-			Date:        time.Now().AddDate(0, 0, i%180),
+			StartsAt:    time.Now().AddDate(0, 0, i%180),
+			EndsAt:      time.Now().AddDate(0, 0, i%180).Add(2 * time.Hour),
+			Timezone:    "UTC",
+			Status:      event.StatusPublished,
 			Latitude:    52.2297 + float64(i%10)*0.01,
 			Longitude:   21.0122 + float64(i%10)*0.01,
 			Fee:         float32(i % 100),
 			OrganizerID: userID,
 			Tags:        []string{tags[i%len(tags)]},
 		}
-		eventRepo.Save(e)
+		eventRepo.Save(context.Background(), e)
 		eventIDs[i] = e.EventID
 	}
 
@@ -59,7 +63,7 @@ func createBenchmarkUser(b *testing.B, userRepo user.UserRepo) string {
 		PasswordHash: "hashedpass",
 		Role:         user.ATTENDEE,
 	}
-	userRepo.Save(u)
+	userRepo.Save(context.Background(), u)
 	return id.String()
 }
 
@@ -75,7 +79,7 @@ func BenchmarkFindAllEvents(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				eventRepo.FindAll()
+				eventRepo.FindAll(context.Background())
 			}
 		})
 	}
@@ -91,7 +95,7 @@ func BenchmarkFindEventByID(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		eventRepo.FindByID(eventIDs[i%len(eventIDs)])
+		eventRepo.FindByID(context.Background(), eventIDs[i%len(eventIDs)])
 	}
 }
 
@@ -108,7 +112,7 @@ func BenchmarkFindAllWithTagFilter(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		eventRepo.FindAllWithFilters(filter)
+		eventRepo.FindAllWithFilters(context.Background(), filter)
 	}
 }
 
@@ -128,7 +132,7 @@ func BenchmarkFindAllWithDateFilter(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		eventRepo.FindAllWithFilters(filter)
+		eventRepo.FindAllWithFilters(context.Background(), filter)
 	}
 }
 
@@ -154,7 +158,7 @@ func BenchmarkFindAllWithCombinedFilters(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		eventRepo.FindAllWithFilters(filter)
+		eventRepo.FindAllWithFilters(context.Background(), filter)
 	}
 }
 
@@ -167,7 +171,7 @@ func BenchmarkFindByOrganizer(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		eventRepo.FindByOrganizer(userID, nil)
+		eventRepo.FindByOrganizer(context.Background(), userID, nil)
 	}
 }
 
@@ -185,7 +189,7 @@ func BenchmarkRegisterAttendance(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		eventRepo.RegisterAttendance(attendees[i], eventIDs[i%len(eventIDs)])
+		eventRepo.RegisterAttendance(context.Background(), attendees[i], eventIDs[i%len(eventIDs)])
 	}
 }
 
@@ -197,13 +201,13 @@ func BenchmarkGetAttendees(b *testing.B) {
 
 	for i := 0; i < 100; i++ {
 		attendeeID := createBenchmarkUser(b, userRepo)
-		eventRepo.RegisterAttendance(attendeeID, eventIDs[i%len(eventIDs)])
+		eventRepo.RegisterAttendance(context.Background(), attendeeID, eventIDs[i%len(eventIDs)])
 	}
 
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		eventRepo.GetAttendees(eventIDs[i%len(eventIDs)])
+		eventRepo.GetAttendees(context.Background(), eventIDs[i%len(eventIDs)])
 	}
 }
 
@@ -218,7 +222,10 @@ func BenchmarkSaveEvent(b *testing.B) {
 			EventID:     uuid.New().String(),
 			Name:        fmt.Sprintf("New Event %d", i),
 			Description: "Benchmark insert test",
-			Date:        time.Now().AddDate(0, 0, i%30),
+			StartsAt:    time.Now().AddDate(0, 0, i%30),
+			EndsAt:      time.Now().AddDate(0, 0, i%30).Add(2 * time.Hour),
+			Timezone:    "UTC",
+			Status:      event.StatusPublished,
 			Latitude:    52.2297,
 			Longitude:   21.0122,
 			Fee:         25.0,
@@ -230,6 +237,51 @@ func BenchmarkSaveEvent(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		eventRepo.Save(events[i])
+		eventRepo.Save(context.Background(), events[i])
+	}
+}
+
+// BenchmarkPagination compares OFFSET pagination with keyset pagination at
+// increasing depths of the events feed.
+func BenchmarkPagination(b *testing.B) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	eventRepo, userRepo, _ := setupBenchmarkRepos(b)
+	userID := createBenchmarkUser(b, userRepo)
+	seedBenchmarkData(b, eventRepo, userID, 2000)
+
+	const pageSize = 20
+
+	for _, page := range []int{1, 10, 50, 90} {
+		b.Run(fmt.Sprintf("offset_page_%d", page), func(b *testing.B) {
+			filter := &event.EventFilter{
+				Pagination: &event.Pagination{Page: page, PageSize: pageSize},
+			}
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				eventRepo.FindAllWithFilters(context.Background(), filter)
+			}
+		})
+
+		b.Run(fmt.Sprintf("cursor_page_%d", page), func(b *testing.B) {
+			filter := &event.EventFilter{
+				Keyset: &event.Keyset{Limit: pageSize},
+			}
+			if page > 1 {
+				prev, err := eventRepo.FindAllWithFilters(context.Background(), &event.EventFilter{
+					Pagination: &event.Pagination{Page: page - 1, PageSize: pageSize},
+				})
+				if err != nil || len(prev) == 0 {
+					b.Fatalf("failed to find the cursor for page %d: %v", page, err)
+				}
+				after := event.CursorFor(prev[len(prev)-1])
+				filter.Keyset.After = &after
+			}
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				eventRepo.FindAllWithFilters(context.Background(), filter)
+			}
+		})
 	}
 }
@@ -0,0 +1,167 @@
+package integral
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/app"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestCapacity_RegisterWhenFull(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 1)
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, second))
+
+		count, err := eventSrvc.GetAttendeesCount(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+}
+
+func TestCapacity_ConcurrentRegistrations(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupCommittingServices(t)
+
+	WithCleanDatabase(t, sqlDb, func(t *testing.T) {
+		const capacity = 3
+		const attendees = 10
+
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, capacity)
+
+		sessions := make([]string, attendees)
+		for i := range sessions {
+			sessions[i] = RegisterAndLoginUser(t, userSrvc, "Attendee", fmt.Sprintf("attendee%d@example.com", i), "Secret123!")
+		}
+
+		codes := make([]int, attendees)
+		var wg sync.WaitGroup
+		for i, sessionID := range sessions {
+			wg.Add(1)
+			go func(i int, sessionID string) {
+				defer wg.Done()
+				codes[i] = registerForEvent(router.Handler, eventID, sessionID)
+			}(i, sessionID)
+		}
+		wg.Wait()
+
+		accepted := 0
+		for _, code := range codes {
+			if code == http.StatusOK {
+				accepted++
+			} else {
+				require.Equal(t, http.StatusAccepted, code)
+			}
+		}
+		require.Equal(t, capacity, accepted)
+
+		count, err := eventSrvc.GetAttendeesCount(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, capacity, count)
+	})
+}
+
+func TestCapacity_EventDetailRemainingSeats(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 5)
+
+		attendeeSessionID := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, attendeeSessionID))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID, nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: attendeeSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp eventDetailResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Equal(t, 5, *resp.Capacity)
+		require.Equal(t, 1, resp.AttendeesCount)
+		require.NotNil(t, resp.RemainingSeats)
+		require.Equal(t, 4, *resp.RemainingSeats)
+	})
+}
+
+func TestCapacity_InvalidCapacity(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		capacity := 0
+		body, err := json.Marshal(webapi.CreateEventRequest{
+			Name:     "Zero Seats",
+			StartsAt: "2025-12-31T23:59:59Z",
+			EndsAt:   "2026-01-01T01:59:59Z",
+			Capacity: &capacity,
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, newEventRequest(t, body, hostSessionID))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func createEventWithCapacity(t *testing.T, router *webapi.Router, eventSrvc *app.EventService, sessionID string, capacity int) string {
+	t.Helper()
+	req := webapi.CreateEventRequest{
+		Name:        "Limited Event",
+		Description: "Test event description",
+		Latitude:    42.0,
+		Longitude:   21.37,
+		Fee:         10.0,
+		StartsAt:    "2025-12-31T23:59:59Z",
+		EndsAt:      "2026-01-01T01:59:59Z",
+		Capacity:    &capacity,
+	}
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/events/add", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, httpReq)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	events, err := eventSrvc.GetAllEvents(context.Background())
+	require.NoError(t, err)
+	for _, e := range events {
+		if e.Name == "Limited Event" {
+			return e.EventID
+		}
+	}
+	t.Fatal("created event not found")
+	return ""
+}
+
+func registerForEvent(h http.Handler, eventID, sessionID string) int {
+	req := httptest.NewRequest(http.MethodPost, "/api/events/"+eventID+"/register", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
@@ -2,6 +2,7 @@ package integral
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/kapiw04/convenly/internal/app"
+	"github.com/kapiw04/convenly/internal/domain/user"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	"github.com/stretchr/testify/require"
 
@@ -21,8 +23,9 @@ func TestCreateEvent_Success(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "bob@example.com", "Secret123!")
 
-		sessionID, err := userSrvc.Login("bob@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "bob@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		body := createEventRequest(t)
 		req := newEventRequest(t, body, sessionID)
@@ -32,7 +35,7 @@ func TestCreateEvent_Success(t *testing.T) {
 
 		require.Equal(t, http.StatusCreated, w.Code)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		require.Equal(t, []string{"Music"}, events[0].Tags)
@@ -59,10 +62,11 @@ func TestCreateEvent_InvalidJSON(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "bob@example.com", "Secret123!")
 
-		sessionID, err := userSrvc.Login("bob@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "bob@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
-		invalidJSON := []byte(`{"name": "Event", "date": invalid}`)
+		invalidJSON := []byte(`{"name": "Event", "starts_at": invalid}`)
 
 		req := newEventRequest(t, invalidJSON, sessionID)
 		w := httptest.NewRecorder()
@@ -79,8 +83,9 @@ func TestCreateEvent_InvalidDateFormat(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "bob@example.com", "Secret123!")
 
-		sessionID, err := userSrvc.Login("bob@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "bob@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		req := webapi.CreateEventRequest{
 			Name:        "Event Name",
@@ -88,7 +93,8 @@ func TestCreateEvent_InvalidDateFormat(t *testing.T) {
 			Latitude:    42.0,
 			Longitude:   21.37,
 			Fee:         10.0,
-			Date:        "2005-04-02",
+			StartsAt:    "2005-04-02",
+			EndsAt:      "2005-04-02T23:00:00Z",
 		}
 		body, err := json.Marshal(req)
 		require.NoError(t, err)
@@ -105,11 +111,12 @@ func TestCreateEvent_NotHost(t *testing.T) {
 	sqlDb, userSrvc, _, router := setupAllServices(t)
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
-		err := userSrvc.Register("Bobby", "bob@example.com", "Secret123!")
+		err := userSrvc.Register(context.Background(), "Bobby", "bob@example.com", "Secret123!")
 		require.NoError(t, err)
 
-		sessionID, err := userSrvc.Login("bob@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "bob@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		body := createEventRequest(t)
 		req := newEventRequest(t, body, sessionID)
@@ -127,8 +134,9 @@ func TestCreateEvent_WithMinimumFee(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "bob@example.com", "Secret123!")
 
-		sessionID, err := userSrvc.Login("bob@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "bob@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		req := webapi.CreateEventRequest{
 			Name:        "Free Event",
@@ -136,7 +144,8 @@ func TestCreateEvent_WithMinimumFee(t *testing.T) {
 			Latitude:    42.0,
 			Longitude:   21.37,
 			Fee:         0.0,
-			Date:        "2025-12-31T23:59:59Z",
+			StartsAt:    "2025-12-31T23:59:59Z",
+			EndsAt:      "2026-01-01T01:59:59Z",
 		}
 		body, err := json.Marshal(req)
 		require.NoError(t, err)
@@ -147,7 +156,7 @@ func TestCreateEvent_WithMinimumFee(t *testing.T) {
 
 		require.Equal(t, http.StatusCreated, w.Code)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		require.Equal(t, float32(0.0), events[0].Fee)
@@ -160,8 +169,9 @@ func TestCreateEvent_MultipleEvents(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "bob@example.com", "Secret123!")
 
-		sessionID, err := userSrvc.Login("bob@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "bob@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		req1 := webapi.CreateEventRequest{
 			Name:        "First Event",
@@ -169,7 +179,8 @@ func TestCreateEvent_MultipleEvents(t *testing.T) {
 			Latitude:    42.0,
 			Longitude:   21.37,
 			Fee:         10.0,
-			Date:        "2025-04-02T21:37:00Z",
+			StartsAt:    "2025-04-02T21:37:00Z",
+			EndsAt:      "2025-04-02T23:37:00Z",
 		}
 		body1, err := json.Marshal(req1)
 		require.NoError(t, err)
@@ -185,7 +196,8 @@ func TestCreateEvent_MultipleEvents(t *testing.T) {
 			Latitude:    43.0,
 			Longitude:   22.37,
 			Fee:         20.0,
-			Date:        "2025-05-02T21:37:00Z",
+			StartsAt:    "2025-05-02T21:37:00Z",
+			EndsAt:      "2025-05-02T23:37:00Z",
 		}
 		body2, err := json.Marshal(req2)
 		require.NoError(t, err)
@@ -195,7 +207,7 @@ func TestCreateEvent_MultipleEvents(t *testing.T) {
 		router.Handler.ServeHTTP(w2, httpReq2)
 		require.Equal(t, http.StatusCreated, w2.Code)
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 	})
@@ -219,8 +231,9 @@ func TestCreateEvent_NonExistentTag(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "bob@example.com", "Secret123!")
-		sessionID, err := userSrvc.Login("bob@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "bob@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		req := webapi.CreateEventRequest{
 			Name:        "Event Name",
@@ -228,7 +241,8 @@ func TestCreateEvent_NonExistentTag(t *testing.T) {
 			Latitude:    42.0,
 			Longitude:   21.37,
 			Fee:         10.0,
-			Date:        "2025-12-31T23:59:59Z",
+			StartsAt:    "2025-12-31T23:59:59Z",
+			EndsAt:      "2026-01-01T01:59:59Z",
 			Tags:        []string{"NonExistentTag123"},
 		}
 		body, err := json.Marshal(req)
@@ -244,11 +258,12 @@ func TestCreateEvent_NonExistentTag(t *testing.T) {
 
 func registerAndPromoteHost(t *testing.T, userSrvc *app.UserService, email, password string) {
 	t.Helper()
-	err := userSrvc.Register("Bobby", email, password)
+	err := userSrvc.Register(context.Background(), "Bobby", email, password)
 	require.NoError(t, err)
-	user, err := userSrvc.GetByEmail(email)
+	verifyEmail(t, userSrvc, email)
+	user, err := userSrvc.GetByEmail(context.Background(), email)
 	require.NoError(t, err)
-	err = userSrvc.PromoteToHost(user.UUID.String())
+	err = userSrvc.PromoteToHost(context.Background(), user.UUID.String())
 	require.NoError(t, err)
 }
 
@@ -260,7 +275,8 @@ func createEventRequest(t *testing.T) []byte {
 		Latitude:    42.0,
 		Longitude:   21.37,
 		Fee:         10.0,
-		Date:        "2005-04-02T21:37:00Z",
+		StartsAt:    "2005-04-02T21:37:00Z",
+		EndsAt:      "2005-04-02T23:37:00Z",
 		Tags:        []string{"Music"},
 	}
 	body, err := json.Marshal(req)
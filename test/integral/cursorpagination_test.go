@@ -0,0 +1,78 @@
+package integral
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestCursorPagination_WalksAllEvents(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createEventWithDetails(t, router, sessionID, "Event 1", "2025-01-15T10:00:00Z", 10.0, []string{})
+		createEventWithDetails(t, router, sessionID, "Event 2", "2025-02-15T10:00:00Z", 10.0, []string{})
+		createEventWithDetails(t, router, sessionID, "Event 3", "2025-02-15T10:00:00Z", 10.0, []string{})
+		createEventWithDetails(t, router, sessionID, "Event 4", "2025-03-15T10:00:00Z", 10.0, []string{})
+		createEventWithDetails(t, router, sessionID, "Event 5", "2025-04-15T10:00:00Z", 99.0, []string{})
+
+		var names []string
+		cursor := ""
+		for range 5 {
+			page := listEventsByCursor(t, router, "/api/events?max_fee=50&page_size=2&cursor="+url.QueryEscape(cursor))
+			for _, e := range page.Items {
+				names = append(names, e.Name)
+			}
+			if !page.HasNext {
+				require.Empty(t, page.NextCursor)
+				break
+			}
+			require.Len(t, page.Items, 2)
+			cursor = page.NextCursor
+		}
+
+		require.Len(t, names, 4)
+		require.Equal(t, "Event 1", names[0])
+		require.ElementsMatch(t, []string{"Event 2", "Event 3"}, names[1:3])
+		require.Equal(t, "Event 4", names[3])
+	})
+}
+
+func TestCursorPagination_InvalidParams(t *testing.T) {
+	_, _, _, router := setupAllServices(t)
+
+	for _, path := range []string{
+		"/api/events?cursor=not-a-cursor",
+		"/api/events?cursor=&page=2",
+		"/api/events?cursor=&q=jazz",
+		"/api/events?cursor=&page_size=0",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func listEventsByCursor(t *testing.T, router *webapi.Router, path string) event.CursorPage[*event.Event] {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var page event.CursorPage[*event.Event]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	return page
+}
@@ -2,12 +2,14 @@ package integral
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/kapiw04/convenly/internal/domain/event"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	"github.com/stretchr/testify/require"
 
@@ -21,7 +23,7 @@ func TestDeleteEvent_Success(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForDelete(t, router, hostSessionID, "Test Event")
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		eventID := events[0].EventID
@@ -34,7 +36,34 @@ func TestDeleteEvent_Success(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		events, err = eventSrvc.GetAllEvents()
+		// the event is cancelled, not removed
+		events, err = eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, event.StatusCancelled, events[0].Status)
+	})
+}
+
+func TestDeleteEvent_Remove(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		createEventForDelete(t, router, hostSessionID, "Test Event")
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID+"?remove=true", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: hostSessionID})
+		w := httptest.NewRecorder()
+
+		router.Handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		events, err = eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 0)
 	})
@@ -47,7 +76,7 @@ func TestDeleteEvent_Unauthorized(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForDelete(t, router, hostSessionID, "Test Event")
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -58,7 +87,7 @@ func TestDeleteEvent_Unauthorized(t *testing.T) {
 
 		require.Equal(t, http.StatusUnauthorized, w.Code)
 
-		events, err = eventSrvc.GetAllEvents()
+		events, err = eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 	})
@@ -71,7 +100,7 @@ func TestDeleteEvent_NotOrganizer(t *testing.T) {
 		host1SessionID := registerHostAndLogin(t, userSrvc, "host1@example.com", "Secret123!")
 		createEventForDelete(t, router, host1SessionID, "Test Event")
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -85,7 +114,7 @@ func TestDeleteEvent_NotOrganizer(t *testing.T) {
 
 		require.Equal(t, http.StatusForbidden, w.Code)
 
-		events, err = eventSrvc.GetAllEvents()
+		events, err = eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 	})
@@ -98,7 +127,7 @@ func TestDeleteEvent_AttendeeCannotDelete(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForDelete(t, router, hostSessionID, "Test Event")
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -112,7 +141,7 @@ func TestDeleteEvent_AttendeeCannotDelete(t *testing.T) {
 
 		require.Equal(t, http.StatusForbidden, w.Code)
 
-		events, err = eventSrvc.GetAllEvents()
+		events, err = eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 	})
@@ -141,7 +170,7 @@ func TestDeleteEvent_WithAttendees(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForDelete(t, router, hostSessionID, "Test Event")
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -152,7 +181,7 @@ func TestDeleteEvent_WithAttendees(t *testing.T) {
 		router.Handler.ServeHTTP(w, registerReq)
 		require.Equal(t, http.StatusOK, w.Code)
 
-		attendees, err := eventSrvc.GetAttendees(eventID)
+		attendees, err := eventSrvc.GetAttendees(context.Background(), eventID)
 		require.NoError(t, err)
 		require.Len(t, attendees, 1)
 
@@ -164,9 +193,10 @@ func TestDeleteEvent_WithAttendees(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		events, err = eventSrvc.GetAllEvents()
+		// attendance is kept on cancelled events
+		attendees, err = eventSrvc.GetAttendees(context.Background(), eventID)
 		require.NoError(t, err)
-		require.Len(t, events, 0)
+		require.Len(t, attendees, 1)
 	})
 }
 
@@ -178,7 +208,8 @@ func createEventForDelete(t *testing.T, router *webapi.Router, sessionID string,
 		Latitude:    42.0,
 		Longitude:   21.37,
 		Fee:         10.0,
-		Date:        "2025-12-31T23:59:59Z",
+		StartsAt:    "2025-12-31T23:59:59Z",
+		EndsAt:      "2026-01-01T01:59:59Z",
 		Tags:        []string{"Music"},
 	}
 	body, err := json.Marshal(req)
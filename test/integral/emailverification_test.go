@@ -0,0 +1,114 @@
+package integral
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/user"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+var verifyLinkRe = regexp.MustCompile(`https://convenly\.test/verify-email\?token=(\S+)`)
+
+func TestEmailVerification(t *testing.T) {
+	sqlDb, router, mailer := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		w := postJSON(router.Handler, "/api/register", webapi.RegisterRequest{Name: "Alice", Email: "alice@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		token := lastLinkToken(t, mailer, verifyLinkRe)
+
+		session, err := router.UserService.Login(context.Background(), "alice@example.com", "Secret123!", user.Device{})
+		require.NoError(t, err)
+
+		// unverified users cannot host
+		req := httptest.NewRequest(http.MethodPost, "/api/become-host", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: session.Token})
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		w = postJSON(router.Handler, "/api/verify-email", webapi.VerifyEmailRequest{Token: token})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: session.Token})
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var me struct {
+			EmailVerified bool `json:"email_verified"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&me))
+		require.True(t, me.EmailVerified)
+
+		req = httptest.NewRequest(http.MethodPost, "/api/become-host", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: session.Token})
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		// the token works once
+		w = postJSON(router.Handler, "/api/verify-email", webapi.VerifyEmailRequest{Token: token})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestEmailVerification_Expired(t *testing.T) {
+	sqlDb, router, mailer := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		w := postJSON(router.Handler, "/api/register", webapi.RegisterRequest{Name: "Alice", Email: "alice@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		token := lastLinkToken(t, mailer, verifyLinkRe)
+
+		_, err := tx.Exec("UPDATE email_verifications SET expires_at = now() - interval '1 minute'")
+		require.NoError(t, err)
+
+		w = postJSON(router.Handler, "/api/verify-email", webapi.VerifyEmailRequest{Token: token})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestEmailVerification_Resend(t *testing.T) {
+	sqlDb, router, mailer := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		w := postJSON(router.Handler, "/api/register", webapi.RegisterRequest{Name: "Alice", Email: "alice@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		session, err := router.UserService.Login(context.Background(), "alice@example.com", "Secret123!", user.Device{})
+		require.NoError(t, err)
+
+		resend := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/api/me/verify-email/resend", nil)
+			req.AddCookie(&http.Cookie{Name: "session-id", Value: session.Token})
+			w := httptest.NewRecorder()
+			router.Handler.ServeHTTP(w, req)
+			return w
+		}
+
+		// the registration mail was just sent
+		w = resend()
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		_, err = tx.Exec("UPDATE email_verifications SET created_at = now() - interval '1 hour'")
+		require.NoError(t, err)
+		w = resend()
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = postJSON(router.Handler, "/api/verify-email", webapi.VerifyEmailRequest{Token: lastLinkToken(t, mailer, verifyLinkRe)})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = resend()
+		require.Equal(t, http.StatusConflict, w.Code)
+	})
+}
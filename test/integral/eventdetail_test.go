@@ -2,6 +2,7 @@ package integral
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/kapiw04/convenly/internal/app"
 	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/domain/user"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	"github.com/stretchr/testify/require"
 
@@ -24,7 +26,7 @@ func TestEventDetail_Success(t *testing.T) {
 
 		createTestEventViaAPI(t, router, sessionID, "Test Event", "2025-12-31T23:59:59Z", 25.0, []string{"Music"})
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		eventID := events[0].EventID
@@ -54,7 +56,7 @@ func TestEventDetail_WithAttendees(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createTestEventViaAPI(t, router, hostSessionID, "Party Event", "2025-12-31T23:59:59Z", 10.0, []string{"Music"})
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -87,7 +89,7 @@ func TestEventDetail_Unauthorized(t *testing.T) {
 		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createTestEventViaAPI(t, router, sessionID, "Test Event", "2025-12-31T23:59:59Z", 25.0, []string{"Music"})
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -135,15 +137,16 @@ func TestEventDetail_InvalidUUIDFormat(t *testing.T) {
 type eventDetailResponse struct {
 	*event.Event
 	AttendeesCount int  `json:"attendees_count"`
+	RemainingSeats *int `json:"remaining_seats,omitempty"`
 	UserRegistered bool `json:"user_registered"`
 }
 
 func registerHostAndLogin(t *testing.T, userSrvc *app.UserService, email, password string) string {
 	t.Helper()
 	registerAndPromoteHost(t, userSrvc, email, password)
-	sessionID, err := userSrvc.Login(email, password)
+	session, err := userSrvc.Login(context.Background(), email, password, user.Device{})
 	require.NoError(t, err)
-	return sessionID
+	return session.Token
 }
 
 func createTestEventViaAPI(t *testing.T, router *webapi.Router, sessionID, name, date string, fee float32, tags []string) {
@@ -154,7 +157,8 @@ func createTestEventViaAPI(t *testing.T, router *webapi.Router, sessionID, name,
 		Latitude:    42.0,
 		Longitude:   21.37,
 		Fee:         fee,
-		Date:        date,
+		StartsAt:    date,
+		EndsAt:      twoHoursAfter(t, date),
 		Tags:        tags,
 	}
 	body, err := json.Marshal(req)
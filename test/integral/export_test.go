@@ -0,0 +1,136 @@
+package integral
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestExport_AttendeesCSV(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 10)
+
+		attendeeSessionID := RegisterAndLoginUser(t, userSrvc, "=Attendee", "attendee@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, attendeeSessionID))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/attendees.csv", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: hostSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		require.Equal(t, []string{"name", "email", "registered_at", "recurrence_id"}, records[0])
+		require.Equal(t, "'=Attendee", records[1][0])
+		require.Equal(t, "attendee@example.com", records[1][1])
+
+		req = httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/attendees.csv", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: attendeeSessionID})
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestExport_EventICS(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 10)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/calendar.ics", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: hostSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/calendar"))
+		body := w.Body.String()
+		require.Contains(t, body, "BEGIN:VEVENT\r\n")
+		require.Contains(t, body, "UID:"+eventID+"@convenly\r\n")
+		require.Contains(t, body, "SUMMARY:Limited Event\r\n")
+	})
+}
+
+func TestExport_CalendarFeed(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 10)
+
+		attendeeSessionID := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, attendeeSessionID))
+
+		feedURL := calendarFeedURL(t, router.Handler, http.MethodGet, "/api/me/calendar", attendeeSessionID)
+
+		// only a digest of the token is stored, so the URL is not shown again
+		var stored int
+		token := strings.TrimSuffix(strings.TrimPrefix(feedURL, "/api/calendar/"), ".ics")
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM users WHERE calendar_token_hash = $1", tokenHash(token)).Scan(&stored))
+		require.Equal(t, 1, stored)
+		req := httptest.NewRequest(http.MethodGet, "/api/me/calendar", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: attendeeSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, `{}`, w.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, feedURL, nil)
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "UID:"+eventID+"@convenly\r\n")
+
+		rotated := calendarFeedURL(t, router.Handler, http.MethodPost, "/api/me/calendar/rotate", attendeeSessionID)
+		require.NotEqual(t, feedURL, rotated)
+
+		req = httptest.NewRequest(http.MethodGet, feedURL, nil)
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestExport_CalendarFeedUnknownToken(t *testing.T) {
+	_, _, _, router := setupAllServices(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/does-not-exist.ics", nil)
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func calendarFeedURL(t *testing.T, h http.Handler, method, path, sessionID string) string {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		FeedURL string `json:"feed_url"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.True(t, strings.HasSuffix(resp.FeedURL, ".ics"))
+	return resp.FeedURL
+}
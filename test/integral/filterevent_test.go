@@ -1,13 +1,16 @@
 package integral
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/domain/user"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	"github.com/stretchr/testify/require"
 
@@ -19,8 +22,9 @@ func TestFilterEvents_ByDateRange(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		sessionID, err := userSrvc.Login("host@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "host@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		createEventWithDetails(t, router, sessionID, "January Event", "2025-01-15T10:00:00Z", 10.0, []string{})
 		createEventWithDetails(t, router, sessionID, "February Event", "2025-02-15T10:00:00Z", 20.0, []string{})
@@ -33,7 +37,7 @@ func TestFilterEvents_ByDateRange(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 
 		var events []*event.Event
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 		require.Equal(t, "February Event", events[0].Name)
@@ -45,7 +49,7 @@ func TestFilterEvents_ByDateRange(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 		require.Equal(t, "January Event", events[0].Name)
@@ -57,12 +61,12 @@ func TestFilterEvents_ByDateRange(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		require.Equal(t, "February Event", events[0].Name)
 
-		allEvents, err := eventSrvc.GetAllEvents()
+		allEvents, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, allEvents, 3)
 	})
@@ -73,8 +77,9 @@ func TestFilterEvents_ByFeeRange(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		sessionID, err := userSrvc.Login("host@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "host@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		createEventWithDetails(t, router, sessionID, "Free Event", "2025-01-15T10:00:00Z", 0.0, []string{})
 		createEventWithDetails(t, router, sessionID, "Cheap Event", "2025-02-15T10:00:00Z", 10.0, []string{})
@@ -87,7 +92,7 @@ func TestFilterEvents_ByFeeRange(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 
 		var events []*event.Event
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		require.Equal(t, "Free Event", events[0].Name)
@@ -98,7 +103,7 @@ func TestFilterEvents_ByFeeRange(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 		require.Equal(t, "Cheap Event", events[0].Name)
@@ -110,12 +115,12 @@ func TestFilterEvents_ByFeeRange(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		require.Equal(t, "Cheap Event", events[0].Name)
 
-		allEvents, err := eventSrvc.GetAllEvents()
+		allEvents, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, allEvents, 3)
 	})
@@ -126,8 +131,9 @@ func TestFilterEvents_ByTags(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		sessionID, err := userSrvc.Login("host@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "host@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		createEventWithDetails(t, router, sessionID, "Music Festival", "2025-01-15T10:00:00Z", 50.0, []string{"Music"})
 		createEventWithDetails(t, router, sessionID, "Tech Conference", "2025-02-15T10:00:00Z", 100.0, []string{"Tech"})
@@ -140,7 +146,7 @@ func TestFilterEvents_ByTags(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 
 		var events []*event.Event
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 
@@ -150,7 +156,7 @@ func TestFilterEvents_ByTags(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 
@@ -160,11 +166,11 @@ func TestFilterEvents_ByTags(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 3)
 
-		allEvents, err := eventSrvc.GetAllEvents()
+		allEvents, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, allEvents, 3)
 	})
@@ -175,8 +181,9 @@ func TestFilterEvents_CombinedFilters(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		sessionID, err := userSrvc.Login("host@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "host@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 		createEventWithDetails(t, router, sessionID, "Cheap Music January", "2025-01-15T10:00:00Z", 10.0, []string{"Music"})
 		createEventWithDetails(t, router, sessionID, "Expensive Music February", "2025-02-15T10:00:00Z", 100.0, []string{"Music"})
 		createEventWithDetails(t, router, sessionID, "Cheap Tech March", "2025-03-15T10:00:00Z", 15.0, []string{"Tech"})
@@ -189,7 +196,7 @@ func TestFilterEvents_CombinedFilters(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 
 		var events []*event.Event
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 		require.Equal(t, "Cheap Music January", events[0].Name)
@@ -200,7 +207,7 @@ func TestFilterEvents_CombinedFilters(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 
@@ -210,7 +217,7 @@ func TestFilterEvents_CombinedFilters(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 	})
@@ -221,8 +228,9 @@ func TestFilterEvents_NoResults(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		sessionID, err := userSrvc.Login("host@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "host@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		createEventWithDetails(t, router, sessionID, "Test Event", "2025-06-15T10:00:00Z", 50.0, []string{"Music"})
 
@@ -233,7 +241,7 @@ func TestFilterEvents_NoResults(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 
 		var events []*event.Event
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 0)
 	})
@@ -268,8 +276,9 @@ func TestFilterEvents_WithPagination(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		sessionID, err := userSrvc.Login("host@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "host@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		createEventWithDetails(t, router, sessionID, "Event 1", "2025-01-15T10:00:00Z", 10.0, []string{})
 		createEventWithDetails(t, router, sessionID, "Event 2", "2025-02-15T10:00:00Z", 20.0, []string{})
@@ -280,7 +289,7 @@ func TestFilterEvents_WithPagination(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 		var events []*event.Event
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 
@@ -290,19 +299,64 @@ func TestFilterEvents_WithPagination(t *testing.T) {
 
 		events = []*event.Event{}
 		require.Equal(t, http.StatusOK, w.Code)
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 1)
 	})
 }
 
+func TestFilterEvents_PageMetadata(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createEventWithDetails(t, router, sessionID, "Event 1", "2025-01-15T10:00:00Z", 10.0, []string{})
+		createEventWithDetails(t, router, sessionID, "Event 2", "2025-02-15T10:00:00Z", 20.0, []string{})
+		createEventWithDetails(t, router, sessionID, "Event 3", "2025-03-15T10:00:00Z", 30.0, []string{})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events?page=1&page_size=2&max_fee=25", nil)
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var page event.Page[*event.Event]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		require.Len(t, page.Items, 2)
+		require.Equal(t, 1, page.Page)
+		require.Equal(t, 2, page.PageSize)
+		require.Equal(t, 2, page.Total)
+		require.False(t, page.HasNext)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/events?page=1&page_size=2", nil)
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		require.Equal(t, 3, page.Total)
+		require.True(t, page.HasNext)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/events?page=2&page_size=2", nil)
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		require.Len(t, page.Items, 1)
+		require.Equal(t, "Event 3", page.Items[0].Name)
+		require.False(t, page.HasNext)
+	})
+}
+
 func TestFilterEvents_NoFilters_ReturnsAll(t *testing.T) {
 	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		sessionID, err := userSrvc.Login("host@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "host@example.com", "Secret123!", user.Device{})
 		require.NoError(t, err)
+		sessionID := session.Token
 
 		createEventWithDetails(t, router, sessionID, "Event 1", "2025-01-15T10:00:00Z", 10.0, []string{})
 		createEventWithDetails(t, router, sessionID, "Event 2", "2025-02-15T10:00:00Z", 20.0, []string{})
@@ -314,11 +368,11 @@ func TestFilterEvents_NoFilters_ReturnsAll(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 
 		var events []*event.Event
-		err = json.Unmarshal(w.Body.Bytes(), &events)
+		events, err = decodeEventsPage(w.Body.Bytes())
 		require.NoError(t, err)
 		require.Len(t, events, 2)
 
-		allEvents, err := eventSrvc.GetAllEvents()
+		allEvents, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		require.Len(t, allEvents, 2)
 	})
@@ -333,7 +387,8 @@ func createEventWithDetails(t *testing.T, router *webapi.Router, sessionID, name
 		Latitude:    42.0,
 		Longitude:   21.37,
 		Fee:         fee,
-		Date:        date,
+		StartsAt:    date,
+		EndsAt:      twoHoursAfter(t, date),
 		Tags:        tags,
 	}
 	body, err := json.Marshal(req)
@@ -344,3 +399,19 @@ func createEventWithDetails(t *testing.T, router *webapi.Router, sessionID, name
 	router.Handler.ServeHTTP(w, httpReq)
 	require.Equal(t, http.StatusCreated, w.Code)
 }
+
+func decodeEventsPage(body []byte) ([]*event.Event, error) {
+	var page event.Page[*event.Event]
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// twoHoursAfter returns the end of a two hour event starting at startsAt.
+func twoHoursAfter(t *testing.T, startsAt string) string {
+	t.Helper()
+	start, err := time.Parse(time.RFC3339, startsAt)
+	require.NoError(t, err)
+	return start.Add(2 * time.Hour).Format(time.RFC3339)
+}
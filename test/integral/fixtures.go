@@ -1,27 +1,34 @@
 package integral
 
 import (
+	"context"
 	"database/sql"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/kapiw04/convenly/internal/app"
+	"github.com/kapiw04/convenly/internal/domain/user"
 	"github.com/kapiw04/convenly/internal/infra/db"
+	"github.com/kapiw04/convenly/internal/infra/mail"
 	"github.com/kapiw04/convenly/internal/infra/security"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	"github.com/stretchr/testify/require"
 )
 
-func setupDb(t *testing.T) *sql.DB {
+// setupDb returns the database of a test, for services that run in the
+// transaction of its WithTx.
+func setupDb(t *testing.T) *testDB {
 	t.Helper()
 	require.NotNil(t, sqlDB)
-	return sqlDB
+	return &testDB{conn: sqlDB}
 }
 
-func setupUserService(t *testing.T, dbConn *sql.DB) *app.UserService {
+func setupUserService(t *testing.T, dbConn db.DBTX) *app.UserService {
 	t.Helper()
 
 	hasher := &security.BcryptHasher{}
-	pgUserRepo := db.NewPostgresUserRepo(dbConn)
+	pgUserRepo := &db.PostgresUserRepo{DB: dbConn}
 	pgSessionRepo := &db.PostgresSessionRepo{
 		DB:       dbConn,
 		UserRepo: pgUserRepo,
@@ -30,34 +37,104 @@ func setupUserService(t *testing.T, dbConn *sql.DB) *app.UserService {
 	return app.NewUserService(pgUserRepo, pgSessionRepo, hasher)
 }
 
-func setupEventService(t *testing.T, dbConn *sql.DB) *app.EventService {
+func setupEventService(t *testing.T, dbConn db.DBTX) *app.EventService {
 	t.Helper()
 
-	pgTagRepo := db.NewPostgresTagRepo(dbConn)
-	pgEventRepo := db.NewPostgresEventRepo(dbConn, pgTagRepo)
+	pgTagRepo := &db.PostgresTagRepo{DB: dbConn}
+	pgEventRepo := &db.PostgresEventRepo{DB: dbConn, TagRepo: pgTagRepo}
 
-	return app.NewEventService(pgEventRepo)
+	return app.NewEventService(pgEventRepo, &db.PostgresTxManager{DB: dbConn})
+}
+
+// testTokenSecret signs the access tokens of the integration tests.
+var testTokenSecret = []byte("integration-test-secret")
+
+func setupTokenService(t *testing.T, dbConn db.DBTX, userSrvc *app.UserService) *app.TokenService {
+	t.Helper()
+
+	return app.NewTokenService(
+		userSrvc,
+		&db.PostgresRefreshTokenRepo{DB: dbConn},
+		security.NewJWTSigner(testTokenSecret),
+		15*time.Minute,
+		24*time.Hour,
+	)
+}
+
+// testMailer writes the mail sent by a test to a file in its temp dir.
+func testMailer(t *testing.T) *mail.LogMailer {
+	t.Helper()
+
+	return &mail.LogMailer{Path: filepath.Join(t.TempDir(), "mail.log"), From: "noreply@convenly.test"}
+}
+
+func setupAccountService(t *testing.T, dbConn db.DBTX, userSrvc *app.UserService, tokenSrvc *app.TokenService, mailer *mail.LogMailer) *app.AccountService {
+	t.Helper()
+
+	return app.NewAccountService(
+		userSrvc,
+		tokenSrvc,
+		&db.PostgresTxManager{DB: dbConn},
+		&db.PostgresPasswordResetRepo{DB: dbConn},
+		&db.PostgresEmailVerificationRepo{DB: dbConn},
+		mailer,
+		"https://convenly.test",
+		app.DefaultAccountPolicy,
+	)
+}
+
+// verifyEmail marks the user's email verified without going through the
+// mailed link.
+func verifyEmail(t *testing.T, userSrvc *app.UserService, email string) {
+	t.Helper()
+
+	u, err := userSrvc.GetByEmail(context.Background(), email)
+	require.NoError(t, err)
+	require.NoError(t, userSrvc.MarkEmailVerified(context.Background(), u.UUID.String(), u.Email))
 }
 
 func RegisterAndLoginUser(t *testing.T, userSrvc *app.UserService, name, email, password string) string {
 	t.Helper()
 
-	err := userSrvc.Register(name, email, password)
+	err := userSrvc.Register(context.Background(), name, email, password)
 	require.NoError(t, err)
+	verifyEmail(t, userSrvc, email)
 
-	sessionID, err := userSrvc.Login(email, password)
+	session, err := userSrvc.Login(context.Background(), email, password, user.Device{})
 	require.NoError(t, err)
 
-	return sessionID
+	return session.Token
 }
 
-func setupAllServices(t *testing.T) (*sql.DB, *app.UserService, *app.EventService, *webapi.Router) {
+// setupAllServices returns services that run inside the transaction of
+// WithTx, so everything a test writes is rolled back.
+func setupAllServices(t *testing.T) (*testDB, *app.UserService, *app.EventService, *webapi.Router) {
 	t.Helper()
 
 	dbConn := setupDb(t)
+	db.NewPostgresTagRepo(dbConn.conn) // seeds the default tags
+	userSrvc := setupUserService(t, dbConn)
+	eventSrvc := setupEventService(t, dbConn)
+	tokenSrvc := setupTokenService(t, dbConn, userSrvc)
+	accountSrvc := setupAccountService(t, dbConn, userSrvc, tokenSrvc, testMailer(t))
+	router := webapi.NewRouter(userSrvc, eventSrvc, tokenSrvc, accountSrvc, webapi.RateLimits{})
+
+	return dbConn, userSrvc, eventSrvc, router
+}
+
+// setupCommittingServices returns services that use the database directly,
+// for tests that need several concurrent transactions. Use them with
+// WithCleanDatabase.
+func setupCommittingServices(t *testing.T) (*sql.DB, *app.UserService, *app.EventService, *webapi.Router) {
+	t.Helper()
+
+	dbConn := setupDb(t).conn
+	db.NewPostgresTagRepo(dbConn) // seeds the default tags
 	userSrvc := setupUserService(t, dbConn)
 	eventSrvc := setupEventService(t, dbConn)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	tokenSrvc := setupTokenService(t, dbConn, userSrvc)
+	accountSrvc := setupAccountService(t, dbConn, userSrvc, tokenSrvc, testMailer(t))
+	router := webapi.NewRouter(userSrvc, eventSrvc, tokenSrvc, accountSrvc, webapi.RateLimits{})
 
 	return dbConn, userSrvc, eventSrvc, router
 }
@@ -0,0 +1,125 @@
+package integral
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestGeoSearch_WithinRadius(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createEventAt(t, router, sessionID, "Warsaw Event", 52.2297, 21.0122)
+		createEventAt(t, router, sessionID, "Krakow Event", 50.0647, 19.9450)
+		createEventAt(t, router, sessionID, "Berlin Event", 52.5200, 13.4050)
+
+		events := listEvents(t, router, "/api/events?lat=52.2297&lng=21.0122&radius_km=300")
+		require.Len(t, events, 2)
+		for _, e := range events {
+			require.NotNil(t, e.DistanceKm)
+			require.LessOrEqual(t, *e.DistanceKm, 300.0)
+		}
+
+		events = listEvents(t, router, "/api/events?lat=52.2297&lng=21.0122&radius_km=10")
+		require.Len(t, events, 1)
+		require.Equal(t, "Warsaw Event", events[0].Name)
+		require.InDelta(t, 0, *events[0].DistanceKm, 0.01)
+	})
+}
+
+func TestGeoSearch_SortByDistance(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createEventAt(t, router, sessionID, "Berlin Event", 52.5200, 13.4050)
+		createEventAt(t, router, sessionID, "Krakow Event", 50.0647, 19.9450)
+		createEventAt(t, router, sessionID, "Warsaw Event", 52.2297, 21.0122)
+
+		events := listEvents(t, router, "/api/events?lat=52.2297&lng=21.0122&sort=distance")
+		require.Len(t, events, 3)
+		require.Equal(t, "Warsaw Event", events[0].Name)
+		require.Equal(t, "Krakow Event", events[1].Name)
+		require.Equal(t, "Berlin Event", events[2].Name)
+		require.InDelta(t, 252, *events[1].DistanceKm, 2)
+	})
+}
+
+func TestGeoSearch_InvalidParams(t *testing.T) {
+	_, _, _, router := setupAllServices(t)
+
+	for _, path := range []string{
+		"/api/events?lat=52.2",
+		"/api/events?lat=91&lng=0",
+		"/api/events?lat=0&lng=181",
+		"/api/events?radius_km=10",
+		"/api/events?lat=0&lng=0&radius_km=-1",
+		"/api/events?sort=distance",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func TestGeoSearch_CreateWithInvalidCoordinates(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		body, err := json.Marshal(webapi.CreateEventRequest{
+			Name:      "Nowhere",
+			StartsAt:  "2025-12-31T23:59:59Z",
+			EndsAt:    "2026-01-01T01:59:59Z",
+			Latitude:  123,
+			Longitude: 21.0,
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, newEventRequest(t, body, sessionID))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func createEventAt(t *testing.T, router *webapi.Router, sessionID, name string, lat, lng float64) {
+	t.Helper()
+	body, err := json.Marshal(webapi.CreateEventRequest{
+		Name:      name,
+		StartsAt:  "2025-12-31T23:59:59Z",
+		EndsAt:    "2026-01-01T01:59:59Z",
+		Latitude:  lat,
+		Longitude: lng,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, newEventRequest(t, body, sessionID))
+	require.Equal(t, http.StatusCreated, w.Code)
+}
+
+func listEvents(t *testing.T, router *webapi.Router, path string) []*event.Event {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	events, err := decodeEventsPage(w.Body.Bytes())
+	require.NoError(t, err)
+	return events
+}
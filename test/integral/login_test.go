@@ -2,6 +2,7 @@ package integral
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -19,10 +20,11 @@ func TestLogin_Success(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := userSrvc.Register(
+			context.Background(),
 			"Bobby",
 			"bob@example.com",
 			"Secret123!",
@@ -54,10 +56,11 @@ func TestLogin_InvalidPassword(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := userSrvc.Register(
+			context.Background(),
 			"Bobby",
 			"bob@example.com",
 			"Secret123!",
@@ -85,7 +88,7 @@ func TestLogin_NonExistentUser(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		loginReq := webapi.LoginRequest{
@@ -109,7 +112,7 @@ func TestLogin_EmptyEmail(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		loginReq := webapi.LoginRequest{
@@ -140,7 +143,7 @@ func TestLogin_EmptyPassword(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		loginReq := webapi.LoginRequest{
@@ -171,10 +174,11 @@ func TestLogin_CaseInsensitiveEmail(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := userSrvc.Register(
+			context.Background(),
 			"Bobby",
 			"bob@example.com",
 			"Secret123!",
@@ -206,10 +210,11 @@ func TestLogin_EmailWithWhitespace(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := userSrvc.Register(
+			context.Background(),
 			"Bobby",
 			"bob@example.com",
 			"Secret123!",
@@ -241,7 +246,7 @@ func TestLogin_InvalidJSON(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		body := []byte(`{"email": "bob@example.com", "password":`)
@@ -266,10 +271,10 @@ func TestLogin_SessionCookieIsSet(t *testing.T) {
 	sqlDb := setupDb(t)
 	userSrvc := setupUserService(t, sqlDb)
 	eventSrvc := setupEventService(t, sqlDb)
-	router := webapi.NewRouter(userSrvc, eventSrvc)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{})
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
-		err := userSrvc.Register("Bobby", "bob@example.com", "Secret123!")
+		err := userSrvc.Register(context.Background(), "Bobby", "bob@example.com", "Secret123!")
 		require.NoError(t, err)
 
 		loginReq := webapi.LoginRequest{
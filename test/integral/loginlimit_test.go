@@ -0,0 +1,129 @@
+package integral
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/app"
+	"github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/infra/db"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+var testLockoutPolicy = security.LockoutPolicy{
+	MaxFailures: 3,
+	BaseDelay:   time.Minute,
+	MaxDelay:    time.Hour,
+	Window:      24 * time.Hour,
+}
+
+func limitLogins(d *testDB, userSrvc *app.UserService) {
+	userSrvc.LimitLogins(
+		&db.PostgresLoginLimiter{DB: d, Scope: "ip", Policy: testLockoutPolicy},
+		&db.PostgresLoginLimiter{DB: d, Scope: "email", Policy: testLockoutPolicy},
+	)
+}
+
+func TestLogin_LockedOutAfterFailures(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+	limitLogins(sqlDb, userSrvc)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+
+		for range 3 {
+			w := postJSON(router.Handler, "/api/login", webapi.LoginRequest{Email: "alice@example.com", Password: "Wrong123!"})
+			require.Equal(t, http.StatusBadRequest, w.Code)
+		}
+
+		// the right password does not help while locked out
+		w := postJSON(router.Handler, "/api/login", webapi.LoginRequest{Email: "alice@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+		require.NoError(t, err)
+		require.InDelta(t, 60, retryAfter, 5)
+
+		w = postJSON(router.Handler, "/api/token", webapi.LoginRequest{Email: "alice@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		var failures int
+		require.NoError(t, tx.QueryRow("SELECT failures FROM login_failures WHERE scope = 'email' AND key = 'alice@example.com'").Scan(&failures))
+		require.Equal(t, 3, failures)
+	})
+}
+
+func TestLogin_LockoutBacksOff(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+	limitLogins(sqlDb, userSrvc)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+		for range 3 {
+			postJSON(router.Handler, "/api/login", webapi.LoginRequest{Email: "alice@example.com", Password: "Wrong123!"})
+		}
+
+		// let the first lockout run out, then fail once more
+		_, err := tx.Exec("UPDATE login_failures SET locked_until = now() - interval '1 second'")
+		require.NoError(t, err)
+		w := postJSON(router.Handler, "/api/login", webapi.LoginRequest{Email: "alice@example.com", Password: "Wrong123!"})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = postJSON(router.Handler, "/api/login", webapi.LoginRequest{Email: "alice@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+		require.NoError(t, err)
+		require.InDelta(t, 120, retryAfter, 5)
+	})
+}
+
+func TestLogin_SuccessResetsFailures(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+	limitLogins(sqlDb, userSrvc)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+		for range 2 {
+			postJSON(router.Handler, "/api/login", webapi.LoginRequest{Email: "alice@example.com", Password: "Wrong123!"})
+		}
+
+		w := postJSON(router.Handler, "/api/login", webapi.LoginRequest{Email: "alice@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var accounts int
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM login_failures WHERE scope = 'email'").Scan(&accounts))
+		require.Equal(t, 0, accounts)
+	})
+}
+
+func TestPostgresLoginLimiter_Purge(t *testing.T) {
+	sqlDb := setupDb(t)
+	limiter := &db.PostgresLoginLimiter{DB: sqlDb, Scope: "email", Policy: testLockoutPolicy}
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		ctx := context.Background()
+		now := time.Now()
+		_, err := limiter.Fail(ctx, "old@example.com", now.Add(-25*time.Hour))
+		require.NoError(t, err)
+		_, err = limiter.Fail(ctx, "recent@example.com", now.Add(-time.Hour))
+		require.NoError(t, err)
+
+		n, err := limiter.Purge(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		// a key past the window starts over
+		lockout, err := limiter.Fail(ctx, "recent@example.com", now.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, lockout)
+		var failures int
+		require.NoError(t, tx.QueryRow("SELECT failures FROM login_failures WHERE key = 'recent@example.com'").Scan(&failures))
+		require.Equal(t, 1, failures)
+	})
+}
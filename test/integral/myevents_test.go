@@ -2,6 +2,7 @@ package integral
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/kapiw04/convenly/internal/app"
 	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/domain/user"
 	"github.com/kapiw04/convenly/internal/infra/webapi"
 	"github.com/stretchr/testify/require"
 
@@ -17,8 +19,8 @@ import (
 )
 
 type MyEventsResponse struct {
-	Hosting   []*event.Event `json:"hosting"`
-	Attending []*event.Event `json:"attending"`
+	Hosting   event.Page[*event.Event] `json:"hosting"`
+	Attending event.Page[*event.Event] `json:"attending"`
 }
 
 func TestMyEvents_Unauthorized(t *testing.T) {
@@ -34,6 +36,33 @@ func TestMyEvents_Unauthorized(t *testing.T) {
 	})
 }
 
+func TestMyEvents_Pagination(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		createEventForMyEvents(t, router, hostSessionID, "Test Event 1")
+		createEventForMyEvents(t, router, hostSessionID, "Test Event 2")
+		createEventForMyEvents(t, router, hostSessionID, "Test Event 3")
+
+		req := httptest.NewRequest(http.MethodGet, "/api/my-events?page=2&page_size=2", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: hostSessionID})
+		w := httptest.NewRecorder()
+
+		router.Handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp MyEventsResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Len(t, resp.Hosting.Items, 1)
+		require.Equal(t, 3, resp.Hosting.Total)
+		require.Equal(t, 2, resp.Hosting.Page)
+		require.False(t, resp.Hosting.HasNext)
+		require.Equal(t, 0, resp.Attending.Total)
+	})
+}
+
 func TestMyEvents_EmptyEvents(t *testing.T) {
 	sqlDb, userSrvc, _, router := setupAllServices(t)
 
@@ -51,8 +80,8 @@ func TestMyEvents_EmptyEvents(t *testing.T) {
 		var resp MyEventsResponse
 		err := json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
-		require.Len(t, resp.Hosting, 0)
-		require.Len(t, resp.Attending, 0)
+		require.Len(t, resp.Hosting.Items, 0)
+		require.Len(t, resp.Attending.Items, 0)
 	})
 }
 
@@ -75,8 +104,8 @@ func TestMyEvents_HostingEvents(t *testing.T) {
 		var resp MyEventsResponse
 		err := json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
-		require.Len(t, resp.Hosting, 2)
-		require.Len(t, resp.Attending, 0)
+		require.Len(t, resp.Hosting.Items, 2)
+		require.Len(t, resp.Attending.Items, 0)
 	})
 }
 
@@ -87,7 +116,7 @@ func TestMyEvents_AttendingEvents(t *testing.T) {
 		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
 		createEventForMyEvents(t, router, hostSessionID, "Test Event 1")
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		eventID := events[0].EventID
 
@@ -110,9 +139,9 @@ func TestMyEvents_AttendingEvents(t *testing.T) {
 		var resp MyEventsResponse
 		err = json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
-		require.Len(t, resp.Hosting, 0)
-		require.Len(t, resp.Attending, 1)
-		require.Equal(t, "Test Event 1", resp.Attending[0].Name)
+		require.Len(t, resp.Hosting.Items, 0)
+		require.Len(t, resp.Attending.Items, 1)
+		require.Equal(t, "Test Event 1", resp.Attending.Items[0].Name)
 	})
 }
 
@@ -123,7 +152,7 @@ func TestMyEvents_HostingAndAttending(t *testing.T) {
 		host1SessionID := registerHostAndLogin(t, userSrvc, "host1@example.com", "Secret123!")
 		createEventForMyEvents(t, router, host1SessionID, "Host 1 Event")
 
-		events, err := eventSrvc.GetAllEvents()
+		events, err := eventSrvc.GetAllEvents(context.Background())
 		require.NoError(t, err)
 		host1EventID := events[0].EventID
 
@@ -147,10 +176,10 @@ func TestMyEvents_HostingAndAttending(t *testing.T) {
 		var resp MyEventsResponse
 		err = json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
-		require.Len(t, resp.Hosting, 1)
-		require.Len(t, resp.Attending, 1)
-		require.Equal(t, "Host 2 Event", resp.Hosting[0].Name)
-		require.Equal(t, "Host 1 Event", resp.Attending[0].Name)
+		require.Len(t, resp.Hosting.Items, 1)
+		require.Len(t, resp.Attending.Items, 1)
+		require.Equal(t, "Host 2 Event", resp.Hosting.Items[0].Name)
+		require.Equal(t, "Host 1 Event", resp.Attending.Items[0].Name)
 	})
 }
 
@@ -162,7 +191,8 @@ func createEventForMyEvents(t *testing.T, router *webapi.Router, sessionID strin
 		Latitude:    42.0,
 		Longitude:   21.37,
 		Fee:         10.0,
-		Date:        "2025-12-31T23:59:59Z",
+		StartsAt:    "2025-12-31T23:59:59Z",
+		EndsAt:      "2026-01-01T01:59:59Z",
 		Tags:        []string{"Music"},
 	}
 	body, err := json.Marshal(req)
@@ -178,13 +208,14 @@ func createEventForMyEvents(t *testing.T, router *webapi.Router, sessionID strin
 
 func registerHostAndLoginWithName(t *testing.T, userSrvc *app.UserService, name, email, password string) string {
 	t.Helper()
-	err := userSrvc.Register(name, email, password)
+	err := userSrvc.Register(context.Background(), name, email, password)
 	require.NoError(t, err)
-	user, err := userSrvc.GetByEmail(email)
+	verifyEmail(t, userSrvc, email)
+	u, err := userSrvc.GetByEmail(context.Background(), email)
 	require.NoError(t, err)
-	err = userSrvc.PromoteToHost(user.UUID.String())
+	err = userSrvc.PromoteToHost(context.Background(), u.UUID.String())
 	require.NoError(t, err)
-	sessionID, err := userSrvc.Login(email, password)
+	session, err := userSrvc.Login(context.Background(), email, password, user.Device{})
 	require.NoError(t, err)
-	return sessionID
+	return session.Token
 }
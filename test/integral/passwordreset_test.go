@@ -0,0 +1,148 @@
+package integral
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/user"
+	"github.com/kapiw04/convenly/internal/infra/mail"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+var resetLinkRe = regexp.MustCompile(`https://convenly\.test/reset-password\?token=(\S+)`)
+
+func setupAccounts(t *testing.T) (*testDB, *webapi.Router, *mail.LogMailer) {
+	t.Helper()
+
+	sqlDb := setupDb(t)
+	userSrvc := setupUserService(t, sqlDb)
+	tokenSrvc := setupTokenService(t, sqlDb, userSrvc)
+	mailer := testMailer(t)
+	accountSrvc := setupAccountService(t, sqlDb, userSrvc, tokenSrvc, mailer)
+	router := webapi.NewRouter(userSrvc, setupEventService(t, sqlDb), tokenSrvc, accountSrvc, webapi.RateLimits{})
+	return sqlDb, router, mailer
+}
+
+// lastLinkToken returns the token of the last link matching linkRe that was
+// mailed.
+func lastLinkToken(t *testing.T, mailer *mail.LogMailer, linkRe *regexp.Regexp) string {
+	t.Helper()
+
+	b, err := os.ReadFile(mailer.Path)
+	require.NoError(t, err)
+	matches := linkRe.FindAllStringSubmatch(string(b), -1)
+	require.NotEmpty(t, matches)
+	token, err := url.QueryUnescape(matches[len(matches)-1][1])
+	require.NoError(t, err)
+	return token
+}
+
+func TestPasswordReset(t *testing.T) {
+	sqlDb, router, mailer := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		userSrvc := router.UserService
+		sessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		tokens := issueTokens(t, router.Handler, "alice@example.com", "Secret123!")
+
+		w := postJSON(router.Handler, "/api/password-reset", webapi.PasswordResetRequest{Email: "alice@example.com"})
+		require.Equal(t, http.StatusOK, w.Code)
+		router.AccountService.Wait()
+		token := lastLinkToken(t, mailer, resetLinkRe)
+
+		// only a digest of the token is stored
+		var stored int
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM password_resets WHERE token_hash = $1", tokenHash(token)).Scan(&stored))
+		require.Equal(t, 1, stored)
+
+		w = postJSON(router.Handler, "/api/password-reset/confirm", webapi.ConfirmPasswordResetRequest{Token: token, Password: "NewSecret123!"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		_, err := userSrvc.Login(context.Background(), "alice@example.com", "Secret123!", user.Device{})
+		require.ErrorIs(t, err, user.ErrInvalidCredentials)
+		_, err = userSrvc.Login(context.Background(), "alice@example.com", "NewSecret123!", user.Device{})
+		require.NoError(t, err)
+
+		// whoever was logged in before is logged out
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		w = postJSON(router.Handler, "/api/token/refresh", webapi.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, http.StatusUnauthorized, getMeWithBearer(router.Handler, tokens.AccessToken))
+
+		// the token works once
+		w = postJSON(router.Handler, "/api/password-reset/confirm", webapi.ConfirmPasswordResetRequest{Token: token, Password: "Other123!"})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPasswordReset_UnknownEmail(t *testing.T) {
+	sqlDb, router, mailer := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		w := postJSON(router.Handler, "/api/password-reset", webapi.PasswordResetRequest{Email: "nobody@example.com"})
+		require.Equal(t, http.StatusOK, w.Code)
+		router.AccountService.Wait()
+
+		_, err := os.Stat(mailer.Path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestPasswordReset_OnlyLatestLinkWorks(t *testing.T) {
+	sqlDb, router, mailer := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, router.UserService.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+
+		postJSON(router.Handler, "/api/password-reset", webapi.PasswordResetRequest{Email: "alice@example.com"})
+		router.AccountService.Wait()
+		first := lastLinkToken(t, mailer, resetLinkRe)
+
+		// asking again right away sends nothing
+		postJSON(router.Handler, "/api/password-reset", webapi.PasswordResetRequest{Email: "alice@example.com"})
+		router.AccountService.Wait()
+		require.Equal(t, first, lastLinkToken(t, mailer, resetLinkRe))
+
+		_, err := tx.Exec("UPDATE password_resets SET created_at = now() - interval '1 hour'")
+		require.NoError(t, err)
+		postJSON(router.Handler, "/api/password-reset", webapi.PasswordResetRequest{Email: "alice@example.com"})
+		router.AccountService.Wait()
+		second := lastLinkToken(t, mailer, resetLinkRe)
+		require.NotEqual(t, first, second)
+
+		w := postJSON(router.Handler, "/api/password-reset/confirm", webapi.ConfirmPasswordResetRequest{Token: first, Password: "NewSecret123!"})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		w = postJSON(router.Handler, "/api/password-reset/confirm", webapi.ConfirmPasswordResetRequest{Token: second, Password: "NewSecret123!"})
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestPasswordReset_Expired(t *testing.T) {
+	sqlDb, router, mailer := setupAccounts(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, router.UserService.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+		postJSON(router.Handler, "/api/password-reset", webapi.PasswordResetRequest{Email: "alice@example.com"})
+		router.AccountService.Wait()
+		token := lastLinkToken(t, mailer, resetLinkRe)
+
+		_, err := tx.Exec("UPDATE password_resets SET expires_at = now() - interval '1 minute'")
+		require.NoError(t, err)
+
+		w := postJSON(router.Handler, "/api/password-reset/confirm", webapi.ConfirmPasswordResetRequest{Token: token, Password: "NewSecret123!"})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
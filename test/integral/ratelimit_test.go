@@ -0,0 +1,99 @@
+package integral
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainsecurity "github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/infra/security"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestRateLimit_RouteGroups(t *testing.T) {
+	sqlDb := setupDb(t)
+	userSrvc := setupUserService(t, sqlDb)
+	eventSrvc := setupEventService(t, sqlDb)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{
+		Store:         security.NewMemoryRateLimiter(),
+		Anonymous:     domainsecurity.RateLimit{Requests: 2, Per: time.Minute},
+		PreAuth:       domainsecurity.RateLimit{Requests: 10, Per: time.Minute},
+		Authenticated: domainsecurity.RateLimit{Requests: 3, Per: time.Minute},
+	})
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		get := func(path, sessionID, remoteAddr string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.RemoteAddr = remoteAddr
+			if sessionID != "" {
+				req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+			}
+			w := httptest.NewRecorder()
+			router.Handler.ServeHTTP(w, req)
+			return w
+		}
+
+		for range 2 {
+			require.Equal(t, http.StatusOK, get("/api/events", "", "203.0.113.1:1234").Code)
+		}
+		w := get("/api/events", "", "203.0.113.1:1234")
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		// health checks are never limited
+		require.Equal(t, http.StatusOK, get("/api/health", "", "203.0.113.1:1234").Code)
+
+		// logged-in users have their own, larger bucket
+		alice := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		bob := RegisterAndLoginUser(t, userSrvc, "Bobby", "bob@example.com", "Secret123!")
+		for range 3 {
+			w := get("/api/me", alice, "203.0.113.2:1234")
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, "3", w.Header().Get("X-RateLimit-Limit"))
+		}
+		require.Equal(t, http.StatusTooManyRequests, get("/api/me", alice, "203.0.113.3:1234").Code)
+		require.Equal(t, http.StatusOK, get("/api/me", bob, "203.0.113.3:1234").Code)
+
+		// requests failing authentication count against the client IP
+		for range 10 {
+			require.Equal(t, http.StatusUnauthorized, get("/api/me", "forged-session", "203.0.113.4:1234").Code)
+		}
+		require.Equal(t, http.StatusTooManyRequests, get("/api/me", "forged-session", "203.0.113.4:1234").Code)
+	})
+}
+
+func TestRateLimit_UsersSharingAnIP(t *testing.T) {
+	sqlDb := setupDb(t)
+	userSrvc := setupUserService(t, sqlDb)
+	eventSrvc := setupEventService(t, sqlDb)
+	router := webapi.NewRouter(userSrvc, eventSrvc, setupTokenService(t, sqlDb, userSrvc), nil, webapi.RateLimits{
+		Store:         security.NewMemoryRateLimiter(),
+		PreAuth:       domainsecurity.RateLimit{Requests: 10, Per: time.Minute},
+		Authenticated: domainsecurity.RateLimit{Requests: 3, Per: time.Minute},
+	})
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		get := func(sessionID string) int {
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			req.RemoteAddr = "203.0.113.9:1234"
+			req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+			w := httptest.NewRecorder()
+			router.Handler.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		alice := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		bob := RegisterAndLoginUser(t, userSrvc, "Bobby", "bob@example.com", "Secret123!")
+		for _, sessionID := range []string{alice, bob} {
+			for range 3 {
+				require.Equal(t, http.StatusOK, get(sessionID))
+			}
+			require.Equal(t, http.StatusTooManyRequests, get(sessionID))
+		}
+	})
+}
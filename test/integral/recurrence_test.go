@@ -0,0 +1,343 @@
+package integral
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/app"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestRecurrence_ExpandsOccurrencesInDateWindow(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createRecurringEvent(t, router, eventSrvc, sessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO;COUNT=4")
+		w := createEventInRange(t, router, sessionID, "Workshop", "2025-03-12T10:00:00Z", "2025-03-12T12:00:00Z", "UTC")
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		events := listEvents(t, router, "/api/events?date_from=2025-03-01&date_to=2025-03-31")
+		require.Equal(t, []string{"Weekly Meetup", "Weekly Meetup", "Workshop", "Weekly Meetup", "Weekly Meetup"}, eventNames(events))
+		require.True(t, events[1].StartsAt.Equal(time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)))
+		require.NotNil(t, events[1].RecurrenceID)
+		require.True(t, events[1].RecurrenceID.Equal(events[1].StartsAt))
+		require.Nil(t, events[2].RecurrenceID)
+
+		// each series is listed once without a date window
+		require.Equal(t, []string{"Weekly Meetup", "Workshop"}, eventNames(listEvents(t, router, "/api/events")))
+	})
+}
+
+func TestRecurrence_InvalidRule(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		for _, rule := range []string{"FREQ=YEARLY", "FREQ=WEEKLY;BYDAY=TU"} {
+			w := postRecurringEvent(t, router, sessionID, "Broken Series", rule)
+			require.Equal(t, http.StatusBadRequest, w.Code, rule)
+		}
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+}
+
+func TestRecurrence_RegisterForOccurrence(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, eventSrvc, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO;COUNT=4")
+		_, err := tx.Exec("UPDATE events SET capacity = 1 WHERE event_id = $1", eventID)
+		require.NoError(t, err)
+
+		alice := RegisterAndLoginUser(t, userSrvc, "alice", "alice@example.com", "Secret123!")
+		bob := RegisterAndLoginUser(t, userSrvc, "bob", "bob@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-10T18:00:00Z", "/register", alice, nil).Code)
+		require.Equal(t, http.StatusConflict, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-10T18:00:00Z", "/register", alice, nil).Code)
+
+		// the seat is only taken for that occurrence
+		require.Equal(t, http.StatusConflict, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-10T18:00:00Z", "/register", bob, nil).Code)
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-17T18:00:00Z", "/register", bob, nil).Code)
+
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodDelete, eventID, "2025-03-10T18:00:00Z", "/unregister", alice, nil).Code)
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-10T18:00:00Z", "/register", bob, nil).Code)
+
+		// not generated by the rule
+		require.Equal(t, http.StatusNotFound, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-11T18:00:00Z", "/register", alice, nil).Code)
+		require.Equal(t, http.StatusNotFound, occurrenceRequest(router.Handler, http.MethodPost, eventID, "next-monday", "/register", alice, nil).Code)
+		require.Equal(t, http.StatusNotFound, occurrenceRequest(router.Handler, http.MethodDelete, eventID, "2025-03-11T18:00:00Z", "/unregister", alice, nil).Code)
+		require.Equal(t, http.StatusNotFound, occurrenceRequest(router.Handler, http.MethodDelete, "00000000-0000-0000-0000-000000000000", "2025-03-10T18:00:00Z", "/unregister", alice, nil).Code)
+	})
+}
+
+func TestRecurrence_SeriesRegistrationRespectsOccurrenceCapacity(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, eventSrvc, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO")
+		_, err := tx.Exec("UPDATE events SET capacity = 1 WHERE event_id = $1", eventID)
+		require.NoError(t, err)
+
+		alice := RegisterAndLoginUser(t, userSrvc, "alice", "alice@example.com", "Secret123!")
+		bob := RegisterAndLoginUser(t, userSrvc, "bob", "bob@example.com", "Secret123!")
+
+		recurrenceID := upcomingMonday()
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, recurrenceID, "/register", alice, nil).Code)
+
+		// the detail shows no seat left, as a series seat would overbook the
+		// occurrence Alice holds
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID, nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: bob})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp eventDetailResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Equal(t, 0, resp.AttendeesCount)
+		require.NotNil(t, resp.RemainingSeats)
+		require.Equal(t, 0, *resp.RemainingSeats)
+
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, bob))
+	})
+}
+
+func TestRecurrence_LeavingFullestOccurrencePromotesWaitlist(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, eventSrvc, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO")
+		_, err := tx.Exec("UPDATE events SET capacity = 1 WHERE event_id = $1", eventID)
+		require.NoError(t, err)
+
+		alice := RegisterAndLoginUser(t, userSrvc, "alice", "alice@example.com", "Secret123!")
+		bob := RegisterAndLoginUser(t, userSrvc, "bob", "bob@example.com", "Secret123!")
+		carol := RegisterAndLoginUser(t, userSrvc, "carol", "carol@example.com", "Secret123!")
+
+		recurrenceID := upcomingMonday()
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, recurrenceID, "/register", alice, nil).Code)
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, bob))
+
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodDelete, eventID, recurrenceID, "/unregister", alice, nil).Code)
+
+		u, err := userSrvc.GetByEmail(context.Background(), "bob@example.com")
+		require.NoError(t, err)
+		require.True(t, eventSrvc.IsUserAttending(context.Background(), u.UUID.String(), eventID))
+
+		// the seat went to Bob, so a newcomer queues
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, carol))
+	})
+}
+
+func TestRecurrence_CancellingFullestOccurrencePromotesWaitlist(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, eventSrvc, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO")
+		_, err := tx.Exec("UPDATE events SET capacity = 1 WHERE event_id = $1", eventID)
+		require.NoError(t, err)
+
+		alice := RegisterAndLoginUser(t, userSrvc, "alice", "alice@example.com", "Secret123!")
+		bob := RegisterAndLoginUser(t, userSrvc, "bob", "bob@example.com", "Secret123!")
+
+		recurrenceID := upcomingMonday()
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, recurrenceID, "/register", alice, nil).Code)
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, bob))
+
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodDelete, eventID, recurrenceID, "", hostSessionID, nil).Code)
+
+		// Alice keeps her registration, but it no longer takes a seat
+		u, err := userSrvc.GetByEmail(context.Background(), "bob@example.com")
+		require.NoError(t, err)
+		require.True(t, eventSrvc.IsUserAttending(context.Background(), u.UUID.String(), eventID))
+		seats, err := eventSrvc.GetSeatsTaken(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, 1, seats)
+	})
+}
+
+func TestRecurrence_OccurrenceAttendeesAreListed(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, eventSrvc, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO;COUNT=4")
+		alice := RegisterAndLoginUser(t, userSrvc, "alice", "alice@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-10T18:00:00Z", "/register", alice, nil).Code)
+		recurrenceID := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+
+		attendees := listAttendees(t, router.Handler, eventID, hostSessionID, "")
+		require.Len(t, attendees, 1)
+		require.Equal(t, "alice@example.com", attendees[0].Email)
+		require.NotNil(t, attendees[0].RecurrenceID)
+		require.True(t, attendees[0].RecurrenceID.Equal(recurrenceID))
+
+		require.True(t, eventSrvc.IsUserAttending(context.Background(), attendees[0].UserID, eventID))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/my-events", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: alice})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp MyEventsResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Equal(t, 1, resp.Attending.Total)
+		require.Len(t, resp.Attending.Items, 1)
+		require.True(t, resp.Attending.Items[0].StartsAt.Equal(recurrenceID))
+		require.True(t, resp.Attending.Items[0].RecurrenceID.Equal(recurrenceID))
+	})
+}
+
+func TestRecurrence_CancelOccurrence(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, eventSrvc, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO;COUNT=4")
+
+		otherHost := registerHostAndLogin(t, userSrvc, "other@example.com", "Secret123!")
+		require.Equal(t, http.StatusForbidden, occurrenceRequest(router.Handler, http.MethodDelete, eventID, "2025-03-10T18:00:00Z", "", otherHost, nil).Code)
+
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodDelete, eventID, "2025-03-10T18:00:00Z", "", hostSessionID, nil).Code)
+
+		events := listEvents(t, router, "/api/events?date_from=2025-03-01&date_to=2025-03-31")
+		require.Len(t, events, 3)
+		for _, e := range events {
+			require.False(t, e.StartsAt.Equal(time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)))
+		}
+
+		// the series itself is untouched
+		e, err := eventSrvc.GetEventByID(context.Background(), eventID)
+		require.NoError(t, err)
+		require.True(t, e.StartsAt.Equal(time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)))
+
+		user := RegisterAndLoginUser(t, userSrvc, "alice", "alice@example.com", "Secret123!")
+		require.Equal(t, http.StatusNotFound, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-10T18:00:00Z", "/register", user, nil).Code)
+	})
+}
+
+func TestRecurrence_RescheduleOccurrence(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, eventSrvc, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO;COUNT=4")
+
+		body := []byte(`{"starts_at":"2025-03-11T19:00:00Z","ends_at":"2025-03-11T21:00:00Z"}`)
+		w := occurrenceRequest(router.Handler, http.MethodPatch, eventID, "2025-03-10T18:00:00Z", "", hostSessionID, body)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		events := listEvents(t, router, "/api/events?date_from=2025-03-11&date_to=2025-03-11")
+		require.Len(t, events, 1)
+		require.True(t, events[0].StartsAt.Equal(time.Date(2025, 3, 11, 19, 0, 0, 0, time.UTC)))
+		require.True(t, events[0].RecurrenceID.Equal(time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)))
+		require.Empty(t, listEvents(t, router, "/api/events?date_from=2025-03-10&date_to=2025-03-10"))
+
+		w = occurrenceRequest(router.Handler, http.MethodPatch, eventID, "2025-03-17T18:00:00Z", "", hostSessionID, []byte(`{"ends_at":"2025-03-17T17:00:00Z"}`))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRecurrence_RuleChangeDropsStaleOccurrences(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createRecurringEvent(t, router, eventSrvc, hostSessionID, "Weekly Meetup", "FREQ=WEEKLY;BYDAY=MO;COUNT=4")
+		alice := RegisterAndLoginUser(t, userSrvc, "alice", "alice@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-10T18:00:00Z", "/register", alice, nil).Code)
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPost, eventID, "2025-03-24T18:00:00Z", "/register", alice, nil).Code)
+		body := []byte(`{"starts_at":"2025-03-11T19:00:00Z","ends_at":"2025-03-11T21:00:00Z"}`)
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodPatch, eventID, "2025-03-10T18:00:00Z", "", hostSessionID, body).Code)
+		require.Equal(t, http.StatusOK, occurrenceRequest(router.Handler, http.MethodDelete, eventID, "2025-03-17T18:00:00Z", "", hostSessionID, nil).Code)
+
+		w := updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"recurrence":"FREQ=WEEKLY;BYDAY=MO;COUNT=2"}`))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		attendees := listAttendees(t, router.Handler, eventID, hostSessionID, "")
+		require.Len(t, attendees, 1)
+		require.True(t, attendees[0].RecurrenceID.Equal(time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)))
+
+		// only the rescheduled occurrence is left
+		var exceptions int
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM occurrence_exceptions WHERE event_id = $1", eventID).Scan(&exceptions))
+		require.Equal(t, 1, exceptions)
+
+		w = updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"recurrence":""}`))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, listAttendees(t, router.Handler, eventID, hostSessionID, ""))
+	})
+}
+
+func postRecurringEvent(t *testing.T, router *webapi.Router, sessionID, name, rule string) *httptest.ResponseRecorder {
+	t.Helper()
+	// 2025-03-03 is a Monday
+	body, err := json.Marshal(webapi.CreateEventRequest{
+		Name:       name,
+		StartsAt:   "2025-03-03T18:00:00Z",
+		EndsAt:     "2025-03-03T20:00:00Z",
+		Timezone:   "UTC",
+		Recurrence: rule,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, newEventRequest(t, body, sessionID))
+	return w
+}
+
+func createRecurringEvent(t *testing.T, router *webapi.Router, eventSrvc *app.EventService, sessionID, name, rule string) string {
+	t.Helper()
+	w := postRecurringEvent(t, router, sessionID, name, rule)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	events, err := eventSrvc.GetAllEvents(context.Background())
+	require.NoError(t, err)
+	for _, e := range events {
+		if e.Name == name {
+			return e.EventID
+		}
+	}
+	t.Fatal("created event not found")
+	return ""
+}
+
+// upcomingMonday returns the recurrence ID of an occurrence at least a week
+// ahead in the weekly Monday series created by createRecurringEvent.
+func upcomingMonday() string {
+	next := time.Now().UTC().AddDate(0, 0, 7)
+	for next.Weekday() != time.Monday {
+		next = next.AddDate(0, 0, 1)
+	}
+	return time.Date(next.Year(), next.Month(), next.Day(), 18, 0, 0, 0, time.UTC).Format(time.RFC3339)
+}
+
+func occurrenceRequest(h http.Handler, method, eventID, recurrenceID, suffix, sessionID string, body []byte) *httptest.ResponseRecorder {
+	path := "/api/events/" + eventID + "/occurrences/" + url.PathEscape(recurrenceID) + suffix
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
@@ -1,6 +1,7 @@
 package integral
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
@@ -16,12 +17,13 @@ func TestUserRepo_SaveAndGet(t *testing.T) {
 	srvc := setupUserService(t, sqlDb)
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := srvc.Register(
+			context.Background(),
 			"Alice",
 			"alice@example.com",
 			"Secret123!",
 		)
 		require.NoError(t, err)
-		u, err := srvc.GetByEmail("alice@example.com")
+		u, err := srvc.GetByEmail(context.Background(), "alice@example.com")
 		assert.NoError(t, err)
 		assert.Equal(t, string(u.Email), "alice@example.com")
 		assert.Equal(t, u.Name, "Alice")
@@ -33,19 +35,20 @@ func TestUserRepo_RegisterGeneratesUUID(t *testing.T) {
 	srvc := setupUserService(t, sqlDb)
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := srvc.Register(
+			context.Background(),
 			"TestUser",
 			"testuser@example.com",
 			"Secret123!",
 		)
 		require.NoError(t, err)
 
-		u, err := srvc.GetByEmail("testuser@example.com")
+		u, err := srvc.GetByEmail(context.Background(), "testuser@example.com")
 		require.NoError(t, err)
 
 		assert.NotEqual(t, [16]byte{}, u.UUID)
 		assert.NotEmpty(t, u.UUID.String())
 
-		uByID, err := srvc.GetByUUID(u.UUID.String())
+		uByID, err := srvc.GetByUUID(context.Background(), u.UUID.String())
 		require.NoError(t, err)
 		assert.Equal(t, u.UUID, uByID.UUID)
 		assert.Equal(t, "testuser@example.com", string(uByID.Email))
@@ -56,7 +59,7 @@ func TestUserRepo_UserIDHasDefaultValue(t *testing.T) {
 	sqlDb := setupDb(t)
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		var columnDefault sql.NullString
-		err := sqlDb.QueryRow(`
+		err := tx.QueryRow(`
 			SELECT column_default 
 			FROM information_schema.columns 
 			WHERE table_name = 'users' AND column_name = 'user_id'
@@ -74,6 +77,7 @@ func TestUserRepo_RegisterDuplicateEmail(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 
 		err := srvc.Register(
+			context.Background(),
 			"Alice",
 			"alice@example.com",
 			"Secret123!",
@@ -81,6 +85,7 @@ func TestUserRepo_RegisterDuplicateEmail(t *testing.T) {
 		require.NoError(t, err)
 
 		err = srvc.Register(
+			context.Background(),
 			"Bobby",
 			"alice@example.com",
 			"AnotherSecret456!",
@@ -97,21 +102,26 @@ func TestUserRepo_RegisterCaseInsensitiveDuplicateEmail(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 
 		err := srvc.Register(
+			context.Background(),
 			"Alice",
 			"alice@example.com",
 			"Secret123!",
 		)
 		require.NoError(t, err)
 
-		err = srvc.Register(
-			"Bobby",
-			"ALICE@EXAMPLE.COM",
-			"AnotherSecret456!",
-		)
+		WithSavepoint(t, tx, func() {
+			err = srvc.Register(
+				context.Background(),
+				"Bobby",
+				"ALICE@EXAMPLE.COM",
+				"AnotherSecret456!",
+			)
+		})
 		assert.Error(t, err)
 		assert.Equal(t, user.ErrUserExists, err)
 
 		err = srvc.Register(
+			context.Background(),
 			"Charlie",
 			"Alice@Example.Com",
 			"YetAnother789!",
@@ -128,18 +138,20 @@ func TestUserRepo_RegisterEmailWithWhitespace(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 
 		err := srvc.Register(
+			context.Background(),
 			"Alice",
 			"  alice@example.com  ",
 			"Secret123!",
 		)
 		require.NoError(t, err)
 
-		u, err := srvc.GetByEmail("alice@example.com")
+		u, err := srvc.GetByEmail(context.Background(), "alice@example.com")
 		assert.NoError(t, err)
 		assert.Equal(t, string(u.Email), "alice@example.com")
 		assert.Equal(t, u.Name, "Alice")
 
 		err = srvc.Register(
+			context.Background(),
 			"Bobby",
 			" alice@example.com ",
 			"AnotherSecret456!",
@@ -155,6 +167,7 @@ func TestUserRepo_RegisterUsernameTooShort(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := srvc.Register(
+			context.Background(),
 			"Bob",
 			"bob@example.com",
 			"Secret123!",
@@ -170,6 +183,7 @@ func TestUserRepo_RegisterUsernameFourChars(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := srvc.Register(
+			context.Background(),
 			"John",
 			"john@example.com",
 			"Secret123!",
@@ -185,13 +199,14 @@ func TestUserRepo_RegisterUsernameExactlyFiveChars(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		err := srvc.Register(
+			context.Background(),
 			"Bobby",
 			"bobby@example.com",
 			"Secret123!",
 		)
 		require.NoError(t, err)
 
-		u, err := srvc.GetByEmail("bobby@example.com")
+		u, err := srvc.GetByEmail(context.Background(), "bobby@example.com")
 		assert.NoError(t, err)
 		assert.Equal(t, "Bobby", u.Name)
 	})
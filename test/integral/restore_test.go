@@ -0,0 +1,153 @@
+package integral
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func removeEvent(h http.Handler, eventID, sessionID string) int {
+	req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID+"?remove=true", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func restoreEvent(h http.Handler, eventID, sessionID string) int {
+	req := httptest.NewRequest(http.MethodPost, "/api/events/"+eventID+"/restore", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func myHostedEvents(t *testing.T, router *webapi.Router, sessionID string) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/my-events", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp MyEventsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp.Hosting.Total
+}
+
+func TestRemoveEvent_HiddenEverywhere(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		createEventForDelete(t, router, hostSessionID, "Test Event")
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+
+		attendeeSessionID := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, attendeeSessionID))
+
+		require.Equal(t, http.StatusOK, removeEvent(router.Handler, eventID, hostSessionID))
+
+		require.Empty(t, listEvents(t, router, "/api/events"))
+		require.NotEqual(t, http.StatusOK, getEventDetail(router.Handler, eventID, hostSessionID))
+		require.Equal(t, 0, myHostedEvents(t, router, hostSessionID))
+		require.Equal(t, http.StatusNotFound, removeEvent(router.Handler, eventID, hostSessionID))
+
+		// the row and its attendance are kept until the purge
+		var attendees int
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM attendance WHERE event_id = $1", eventID).Scan(&attendees))
+		require.Equal(t, 1, attendees)
+	})
+}
+
+func TestRestoreEvent_Success(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		createEventForDelete(t, router, hostSessionID, "Test Event")
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+
+		require.Equal(t, http.StatusOK, removeEvent(router.Handler, eventID, hostSessionID))
+		require.Equal(t, http.StatusOK, restoreEvent(router.Handler, eventID, hostSessionID))
+
+		events = listEvents(t, router, "/api/events")
+		require.Len(t, events, 1)
+		require.Equal(t, []string{"Music"}, events[0].Tags)
+		require.Equal(t, http.StatusOK, getEventDetail(router.Handler, eventID, hostSessionID))
+		require.Equal(t, 1, myHostedEvents(t, router, hostSessionID))
+
+		// nothing left to restore
+		require.Equal(t, http.StatusNotFound, restoreEvent(router.Handler, eventID, hostSessionID))
+	})
+}
+
+func TestRestoreEvent_NotOrganizer(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		host1SessionID := registerHostAndLogin(t, userSrvc, "host1@example.com", "Secret123!")
+		createEventForDelete(t, router, host1SessionID, "Test Event")
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+		require.Equal(t, http.StatusOK, removeEvent(router.Handler, eventID, host1SessionID))
+
+		host2SessionID := registerHostAndLoginWithName(t, userSrvc, "Host 2", "host2@example.com", "Secret123!")
+		require.Equal(t, http.StatusForbidden, restoreEvent(router.Handler, eventID, host2SessionID))
+		require.Empty(t, listEvents(t, router, "/api/events"))
+	})
+}
+
+func TestPurgeDeletedEvents(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		createEventForDelete(t, router, hostSessionID, "Old Event")
+		createEventForDelete(t, router, hostSessionID, "Recent Event")
+		createEventForDelete(t, router, hostSessionID, "Live Event")
+
+		ids := map[string]string{}
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		for _, e := range events {
+			ids[e.Name] = e.EventID
+		}
+
+		require.Equal(t, http.StatusOK, removeEvent(router.Handler, ids["Old Event"], hostSessionID))
+		require.Equal(t, http.StatusOK, removeEvent(router.Handler, ids["Recent Event"], hostSessionID))
+		_, err = tx.Exec("UPDATE events SET deleted_at = now() - interval '31 days' WHERE event_id = $1", ids["Old Event"])
+		require.NoError(t, err)
+
+		n, err := eventSrvc.PurgeDeletedEvents(context.Background(), time.Now(), 30*24*time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		var count int
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM events WHERE event_id = $1", ids["Old Event"]).Scan(&count))
+		require.Equal(t, 0, count)
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM event_tag WHERE event_id = $1", ids["Old Event"]).Scan(&count))
+		require.Equal(t, 0, count)
+
+		// still within the retention period
+		require.Equal(t, http.StatusOK, restoreEvent(router.Handler, ids["Recent Event"], hostSessionID))
+		require.ElementsMatch(t, []string{"Recent Event", "Live Event"}, eventNames(listEvents(t, router, "/api/events")))
+	})
+}
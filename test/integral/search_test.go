@@ -0,0 +1,95 @@
+package integral
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestSearch_ByNameAndDescription(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createEventWithDescription(t, router, sessionID, "Golang Meetup", "Talks about concurrency", 0)
+		createEventWithDescription(t, router, sessionID, "Jazz Night", "Live music and golang developers welcome", 0)
+		createEventWithDescription(t, router, sessionID, "Board Games", "Bring your friends", 0)
+		createEventWithDescription(t, router, sessionID, "Gopher Party", `<img src=x onerror="alert(1)"> rust & gophers`, 0)
+
+		events := listEvents(t, router, "/api/events?q=golang")
+		require.Len(t, events, 2)
+		// matches in the name rank higher than in the description
+		require.Equal(t, "Golang Meetup", events[0].Name)
+		require.Equal(t, "Jazz Night", events[1].Name)
+		require.NotNil(t, events[0].SearchRank)
+		require.GreaterOrEqual(t, *events[0].SearchRank, *events[1].SearchRank)
+		require.Contains(t, events[1].Headline, "<b>golang</b>")
+
+		// the organizer's text is escaped
+		events = listEvents(t, router, "/api/events?q=rust")
+		require.Len(t, events, 1)
+		require.Contains(t, events[0].Headline, "<b>rust</b>")
+		require.NotContains(t, events[0].Headline, "<img")
+		require.Contains(t, events[0].Headline, "&amp;")
+
+		events = listEvents(t, router, "/api/events?q=nothing+matches+this")
+		require.Empty(t, events)
+	})
+}
+
+func TestSearch_CombinesWithFilters(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createEventWithDescription(t, router, sessionID, "Free Workshop", "Hands-on workshop", 0)
+		createEventWithDescription(t, router, sessionID, "Paid Workshop", "Hands-on workshop", 50)
+
+		events := listEvents(t, router, "/api/events?q=workshop&max_fee=10")
+		require.Len(t, events, 1)
+		require.Equal(t, "Free Workshop", events[0].Name)
+
+		events = listEvents(t, router, "/api/events?q=workshop+-free")
+		require.Len(t, events, 1)
+		require.Equal(t, "Paid Workshop", events[0].Name)
+	})
+}
+
+func TestSearch_QueryTooLong(t *testing.T) {
+	_, _, _, router := setupAllServices(t)
+
+	long := make([]byte, 201)
+	for i := range long {
+		long[i] = 'a'
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/events?q="+string(long), nil)
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func createEventWithDescription(t *testing.T, router *webapi.Router, sessionID, name, description string, fee float32) {
+	t.Helper()
+	body, err := json.Marshal(webapi.CreateEventRequest{
+		Name:        name,
+		Description: description,
+		StartsAt:    "2025-12-31T23:59:59Z",
+		EndsAt:      "2026-01-01T01:59:59Z",
+		Fee:         fee,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, newEventRequest(t, body, sessionID))
+	require.Equal(t, http.StatusCreated, w.Code)
+}
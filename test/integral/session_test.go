@@ -0,0 +1,282 @@
+package integral
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/domain/user"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func getMe(h http.Handler, sessionID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func tokenHash(sessionID string) []byte {
+	sum := sha256.Sum256([]byte(sessionID))
+	return sum[:]
+}
+
+func sessionExpiry(t *testing.T, tx *sql.Tx, sessionID string) time.Time {
+	t.Helper()
+	var expiresAt time.Time
+	require.NoError(t, tx.QueryRow("SELECT expires_at FROM sessions WHERE token_hash = $1", tokenHash(sessionID)).Scan(&expiresAt))
+	return expiresAt
+}
+
+func TestLogin_CookieExpiresWithSession(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+
+		body, err := json.Marshal(webapi.LoginRequest{Email: "alice@example.com", Password: "Secret123!"})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		expiresAt := sessionExpiry(t, tx, cookies[0].Value)
+		require.WithinDuration(t, time.Now().Add(user.DefaultSessionPolicy.TTL), expiresAt, time.Minute)
+		require.WithinDuration(t, expiresAt, cookies[0].Expires, time.Second)
+		require.InDelta(t, user.DefaultSessionPolicy.TTL.Seconds(), cookies[0].MaxAge, 60)
+	})
+}
+
+func TestSession_TokenStoredHashed(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+
+		var stored []byte
+		require.NoError(t, tx.QueryRow("SELECT token_hash FROM sessions").Scan(&stored))
+		require.Equal(t, tokenHash(sessionID), stored)
+
+		// what is in the table does not work as a token
+		stolen := base64.RawURLEncoding.EncodeToString(stored)
+		require.Equal(t, http.StatusUnauthorized, getMe(router.Handler, stolen).Code)
+		require.Equal(t, http.StatusOK, getMe(router.Handler, sessionID).Code)
+	})
+}
+
+func TestSession_Expired(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+
+		_, err := tx.Exec("UPDATE sessions SET expires_at = now() - interval '1 minute' WHERE token_hash = $1", tokenHash(sessionID))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusUnauthorized, getMe(router.Handler, sessionID).Code)
+	})
+}
+
+func TestSession_RenewedOnUse(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+
+		_, err := tx.Exec("UPDATE sessions SET expires_at = now() + interval '1 minute' WHERE token_hash = $1", tokenHash(sessionID))
+		require.NoError(t, err)
+
+		w := getMe(router.Handler, sessionID)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		expiresAt := sessionExpiry(t, tx, sessionID)
+		require.WithinDuration(t, time.Now().Add(user.DefaultSessionPolicy.TTL), expiresAt, time.Minute)
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.WithinDuration(t, expiresAt, cookies[0].Expires, time.Second)
+	})
+}
+
+func TestSession_RenewalCappedByMaxAge(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+
+		// logged in almost MaxAge ago
+		_, err := tx.Exec(
+			"UPDATE sessions SET created_at = now() - interval '29 days 23 hours' WHERE token_hash = $1",
+			tokenHash(sessionID),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, getMe(router.Handler, sessionID).Code)
+		require.WithinDuration(t, time.Now().Add(time.Hour), sessionExpiry(t, tx, sessionID), time.Minute)
+	})
+}
+
+func TestLogoutEverywhere(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		laptop := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		session, err := userSrvc.Login(context.Background(), "alice@example.com", "Secret123!", user.Device{})
+		require.NoError(t, err)
+		phone := session.Token
+		other := RegisterAndLoginUser(t, userSrvc, "Bobby", "bob@example.com", "Secret123!")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/logout-all", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: phone})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		require.Equal(t, http.StatusUnauthorized, getMe(router.Handler, laptop).Code)
+		require.Equal(t, http.StatusUnauthorized, getMe(router.Handler, phone).Code)
+		require.Equal(t, http.StatusOK, getMe(router.Handler, other).Code)
+	})
+}
+
+func TestPurgeExpiredSessions(t *testing.T) {
+	sqlDb, userSrvc, _, _ := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		expired := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		live := RegisterAndLoginUser(t, userSrvc, "Bobby", "bob@example.com", "Secret123!")
+
+		_, err := tx.Exec("UPDATE sessions SET expires_at = now() - interval '1 hour' WHERE token_hash = $1", tokenHash(expired))
+		require.NoError(t, err)
+
+		n, err := userSrvc.PurgeExpiredSessions(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		var count int
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM sessions WHERE token_hash = $1", tokenHash(expired)).Scan(&count))
+		require.Equal(t, 0, count)
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM sessions WHERE token_hash = $1", tokenHash(live)).Scan(&count))
+		require.Equal(t, 1, count)
+	})
+}
+
+type sessionListItem struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+func listSessions(t *testing.T, h http.Handler, sessionID string) []sessionListItem {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/me/sessions", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var sessions []sessionListItem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+	return sessions
+}
+
+func revokeSession(h http.Handler, id, sessionID string) int {
+	req := httptest.NewRequest(http.MethodDelete, "/api/me/sessions/"+id, nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestLogin_RecordsDevice(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+
+		body, err := json.Marshal(webapi.LoginRequest{Email: "alice@example.com", Password: "Secret123!"})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(body))
+		req.Header.Set("User-Agent", "Firefox/128.0")
+		req.RemoteAddr = "203.0.113.7:52100"
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		sessions := listSessions(t, router.Handler, w.Result().Cookies()[0].Value)
+		require.Len(t, sessions, 1)
+		require.Equal(t, "Firefox/128.0", sessions[0].UserAgent)
+		require.Equal(t, "203.0.113.7", sessions[0].IP)
+		require.True(t, sessions[0].Current)
+		require.False(t, sessions[0].CreatedAt.IsZero())
+	})
+}
+
+func TestListSessions_MarksCurrent(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		laptop := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		phone, err := userSrvc.Login(context.Background(), "alice@example.com", "Secret123!", user.Device{UserAgent: "Phone"})
+		require.NoError(t, err)
+		RegisterAndLoginUser(t, userSrvc, "Bobby", "bob@example.com", "Secret123!")
+
+		sessions := listSessions(t, router.Handler, phone.Token)
+		require.Len(t, sessions, 2)
+		// the listing request itself made the phone session the latest used
+		require.Equal(t, phone.ID.String(), sessions[0].ID)
+		require.True(t, sessions[0].Current)
+		require.Equal(t, "Phone", sessions[0].UserAgent)
+		require.False(t, sessions[1].Current)
+
+		_, err = tx.Exec("UPDATE sessions SET expires_at = now() - interval '1 minute' WHERE token_hash = $1", tokenHash(laptop))
+		require.NoError(t, err)
+		require.Len(t, listSessions(t, router.Handler, phone.Token), 1)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		laptop := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		phone, err := userSrvc.Login(context.Background(), "alice@example.com", "Secret123!", user.Device{})
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, revokeSession(router.Handler, phone.ID.String(), laptop))
+
+		require.Equal(t, http.StatusUnauthorized, getMe(router.Handler, phone.Token).Code)
+		require.Equal(t, http.StatusOK, getMe(router.Handler, laptop).Code)
+		require.Equal(t, http.StatusNotFound, revokeSession(router.Handler, phone.ID.String(), laptop))
+	})
+}
+
+func TestRevokeSession_OtherUser(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		alice := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		require.NoError(t, userSrvc.Register(context.Background(), "Bobby", "bob@example.com", "Secret123!"))
+		bob, err := userSrvc.Login(context.Background(), "bob@example.com", "Secret123!", user.Device{})
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusNotFound, revokeSession(router.Handler, bob.ID.String(), alice))
+		require.Equal(t, http.StatusNotFound, revokeSession(router.Handler, "not-a-uuid", alice))
+		require.Equal(t, http.StatusOK, getMe(router.Handler, bob.Token).Code)
+	})
+}
@@ -0,0 +1,84 @@
+package integral
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestSortEvents_ByDateAndFee(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createEventWithDetails(t, router, sessionID, "March Event", "2025-03-15T10:00:00Z", 10.0, []string{})
+		createEventWithDetails(t, router, sessionID, "January Event", "2025-01-15T10:00:00Z", 30.0, []string{})
+		createEventWithDetails(t, router, sessionID, "February Event", "2025-02-15T10:00:00Z", 20.0, []string{})
+
+		require.Equal(t, []string{"January Event", "February Event", "March Event"}, eventNames(listEvents(t, router, "/api/events?sort=date_asc")))
+		require.Equal(t, []string{"March Event", "February Event", "January Event"}, eventNames(listEvents(t, router, "/api/events?sort=date_desc")))
+		require.Equal(t, []string{"March Event", "February Event", "January Event"}, eventNames(listEvents(t, router, "/api/events?sort=fee_asc")))
+		require.Equal(t, []string{"January Event", "February Event", "March Event"}, eventNames(listEvents(t, router, "/api/events?sort=fee_desc")))
+		require.Equal(t, []string{"February Event", "January Event", "March Event"}, eventNames(listEvents(t, router, "/api/events?sort=newest")))
+
+		require.Equal(t, []string{"February Event"}, eventNames(listEvents(t, router, "/api/events?sort=fee_asc&page=2&page_size=1")))
+	})
+}
+
+func TestSortEvents_ByPopularity(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		createEventWithDetails(t, router, sessionID, "Quiet Event", "2025-01-15T10:00:00Z", 0, []string{})
+		createEventWithDetails(t, router, sessionID, "Busy Event", "2025-02-15T10:00:00Z", 0, []string{})
+		createEventWithDetails(t, router, sessionID, "Medium Event", "2025-03-15T10:00:00Z", 0, []string{})
+
+		ids := map[string]string{}
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		for _, e := range events {
+			ids[e.Name] = e.EventID
+		}
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, ids["Busy Event"], first))
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, ids["Busy Event"], second))
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, ids["Medium Event"], first))
+
+		require.Equal(t, []string{"Busy Event", "Medium Event", "Quiet Event"}, eventNames(listEvents(t, router, "/api/events?sort=popularity")))
+	})
+}
+
+func TestSortEvents_InvalidSort(t *testing.T) {
+	_, _, _, router := setupAllServices(t)
+
+	for _, path := range []string{
+		"/api/events?sort=name",
+		"/api/events?sort=distance",
+		"/api/events?sort=fee_asc&cursor=",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func eventNames(events []*event.Event) []string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e.Name
+	}
+	return names
+}
@@ -0,0 +1,146 @@
+package integral
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestStatus_DraftOnlyVisibleToOrganizer(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithStatus(t, router, tx, hostSessionID, "Secret Gig", "draft")
+
+		require.Empty(t, listEvents(t, router, "/api/events"))
+		require.Empty(t, listEvents(t, router, "/api/events?date_from=2025-01-01"))
+
+		userSessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		require.Equal(t, http.StatusBadRequest, getEventDetail(router.Handler, eventID, userSessionID))
+		require.Equal(t, http.StatusOK, getEventDetail(router.Handler, eventID, hostSessionID))
+		require.Equal(t, http.StatusConflict, registerForEvent(router.Handler, eventID, userSessionID))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/my-events", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: hostSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp MyEventsResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Len(t, resp.Hosting.Items, 1)
+		require.Equal(t, event.StatusDraft, resp.Hosting.Items[0].Status)
+	})
+}
+
+func TestStatus_PublishDraft(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithStatus(t, router, tx, hostSessionID, "Secret Gig", "draft")
+
+		w := updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"status":"published"}`))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		events := listEvents(t, router, "/api/events")
+		require.Len(t, events, 1)
+		require.Equal(t, event.StatusPublished, events[0].Status)
+
+		userSessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, userSessionID))
+
+		// published events cannot go back to drafts
+		w = updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"status":"draft"}`))
+		require.Equal(t, http.StatusConflict, w.Code)
+
+		w = updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"status":"archived"}`))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestStatus_CancelledRefusesRegistrations(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithStatus(t, router, tx, hostSessionID, "Rained Out", "")
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID, nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: hostSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		userSessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		require.Equal(t, http.StatusConflict, registerForEvent(router.Handler, eventID, userSessionID))
+		require.Equal(t, http.StatusOK, getEventDetail(router.Handler, eventID, userSessionID))
+
+		events := listEvents(t, router, "/api/events")
+		require.Len(t, events, 1)
+		require.Equal(t, event.StatusCancelled, events[0].Status)
+
+		w = updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"status":"published"}`))
+		require.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestStatus_CannotCreateCancelled(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		w := postEventWithStatus(t, router, hostSessionID, "Never Was", "cancelled")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+}
+
+func postEventWithStatus(t *testing.T, router *webapi.Router, sessionID, name, status string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(webapi.CreateEventRequest{
+		Name:     name,
+		StartsAt: "2025-06-01T18:00:00Z",
+		EndsAt:   "2025-06-01T20:00:00Z",
+		Status:   status,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, newEventRequest(t, body, sessionID))
+	return w
+}
+
+// createEventWithStatus looks the event up in the table rather than the
+// listings, which leave drafts out.
+func createEventWithStatus(t *testing.T, router *webapi.Router, tx *sql.Tx, sessionID, name, status string) string {
+	t.Helper()
+	w := postEventWithStatus(t, router, sessionID, name, status)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var eventID string
+	require.NoError(t, tx.QueryRow("SELECT event_id FROM events WHERE name = $1", name).Scan(&eventID))
+	return eventID
+}
+
+func getEventDetail(h http.Handler, eventID, sessionID string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID, nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
@@ -1,6 +1,7 @@
 package integral
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 	"time"
@@ -14,9 +15,11 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func setupTagRepo(t *testing.T, dbConn *sql.DB) *db.PostgresTagRepo {
+func setupTagRepo(t *testing.T, dbConn db.DBTX) *db.PostgresTagRepo {
 	t.Helper()
-	return db.NewPostgresTagRepo(dbConn)
+	repo := &db.PostgresTagRepo{DB: dbConn}
+	require.NoError(t, repo.SeedDefaults(context.Background()))
+	return repo
 }
 
 func TestTagRepo_FindAll_ReturnsDefaultTags(t *testing.T) {
@@ -25,7 +28,7 @@ func TestTagRepo_FindAll_ReturnsDefaultTags(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		tagRepo := setupTagRepo(t, sqlDb)
 
-		tags, err := tagRepo.FindAll()
+		tags, err := tagRepo.FindAll(context.Background())
 		require.NoError(t, err)
 
 		assert.Len(t, tags, len(event.DefaultTagNames))
@@ -47,7 +50,7 @@ func TestTagRepo_FindByName_ExistingTag(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		tagRepo := setupTagRepo(t, sqlDb)
 
-		tag, err := tagRepo.FindByName("Music")
+		tag, err := tagRepo.FindByName(context.Background(), "Music")
 		require.NoError(t, err)
 
 		require.NotNil(t, tag)
@@ -62,7 +65,7 @@ func TestTagRepo_FindByName_NonExistingTag(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		tagRepo := setupTagRepo(t, sqlDb)
 
-		tag, err := tagRepo.FindByName("NonExistentTag")
+		tag, err := tagRepo.FindByName(context.Background(), "NonExistentTag")
 		require.NoError(t, err)
 
 		assert.Nil(t, tag)
@@ -75,14 +78,14 @@ func TestTagRepo_CreateIfNotExists_NewTag(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		tagRepo := setupTagRepo(t, sqlDb)
 
-		tag, err := tagRepo.CreateIfNotExists("CustomTag")
+		tag, err := tagRepo.CreateIfNotExists(context.Background(), "CustomTag")
 		require.NoError(t, err)
 
 		require.NotNil(t, tag)
 		assert.Equal(t, "CustomTag", tag.Name)
 		assert.Greater(t, tag.TagID, int64(0))
 
-		foundTag, err := tagRepo.FindByName("CustomTag")
+		foundTag, err := tagRepo.FindByName(context.Background(), "CustomTag")
 		require.NoError(t, err)
 		require.NotNil(t, foundTag)
 		assert.Equal(t, tag.TagID, foundTag.TagID)
@@ -95,11 +98,11 @@ func TestTagRepo_CreateIfNotExists_ExistingTag(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		tagRepo := setupTagRepo(t, sqlDb)
 
-		existingTag, err := tagRepo.FindByName("Music")
+		existingTag, err := tagRepo.FindByName(context.Background(), "Music")
 		require.NoError(t, err)
 		require.NotNil(t, existingTag)
 
-		tag, err := tagRepo.CreateIfNotExists("Music")
+		tag, err := tagRepo.CreateIfNotExists(context.Background(), "Music")
 		require.NoError(t, err)
 
 		require.NotNil(t, tag)
@@ -114,14 +117,14 @@ func TestTagRepo_SeedDefaults_Idempotent(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		tagRepo := setupTagRepo(t, sqlDb)
 
-		initialTags, err := tagRepo.FindAll()
+		initialTags, err := tagRepo.FindAll(context.Background())
 		require.NoError(t, err)
 		initialCount := len(initialTags)
 
-		err = tagRepo.SeedDefaults()
+		err = tagRepo.SeedDefaults(context.Background())
 		require.NoError(t, err)
 
-		tags, err := tagRepo.FindAll()
+		tags, err := tagRepo.FindAll(context.Background())
 		require.NoError(t, err)
 		assert.Len(t, tags, initialCount)
 	})
@@ -133,7 +136,7 @@ func TestTagRepo_FindAll_ReturnedTagsHaveValidIDs(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		tagRepo := setupTagRepo(t, sqlDb)
 
-		tags, err := tagRepo.FindAll()
+		tags, err := tagRepo.FindAll(context.Background())
 		require.NoError(t, err)
 
 		for _, tag := range tags {
@@ -149,13 +152,13 @@ func TestTagRepo_CreateIfNotExists_MultipleTimes(t *testing.T) {
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		tagRepo := setupTagRepo(t, sqlDb)
 
-		tag1, err := tagRepo.CreateIfNotExists("UniqueTag")
+		tag1, err := tagRepo.CreateIfNotExists(context.Background(), "UniqueTag")
 		require.NoError(t, err)
 
-		tag2, err := tagRepo.CreateIfNotExists("UniqueTag")
+		tag2, err := tagRepo.CreateIfNotExists(context.Background(), "UniqueTag")
 		require.NoError(t, err)
 
-		tag3, err := tagRepo.CreateIfNotExists("UniqueTag")
+		tag3, err := tagRepo.CreateIfNotExists(context.Background(), "UniqueTag")
 		require.NoError(t, err)
 
 		assert.Equal(t, tag1.TagID, tag2.TagID)
@@ -168,18 +171,18 @@ func TestEventRepo_FindAllByTags_SingleTag(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		user, err := userSrvc.GetByEmail("host@example.com")
+		user, err := userSrvc.GetByEmail(context.Background(), "host@example.com")
 		require.NoError(t, err)
 
 		musicEvent := createTestEvent(t, "Music Festival", user.UUID.String(), []string{"Music"})
-		err = eventSrvc.CreateEvent(musicEvent)
+		err = eventSrvc.CreateEvent(context.Background(), musicEvent)
 		require.NoError(t, err)
 
 		sportsEvent := createTestEvent(t, "Sports Day", user.UUID.String(), []string{"Sports"})
-		err = eventSrvc.CreateEvent(sportsEvent)
+		err = eventSrvc.CreateEvent(context.Background(), sportsEvent)
 		require.NoError(t, err)
 
-		events, err := eventSrvc.GetEventByTag([]string{"Music"})
+		events, err := eventSrvc.GetEventByTag(context.Background(), []string{"Music"})
 		require.NoError(t, err)
 
 		assert.Len(t, events, 1)
@@ -192,22 +195,22 @@ func TestEventRepo_FindAllByTags_MultipleTags(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		user, err := userSrvc.GetByEmail("host@example.com")
+		user, err := userSrvc.GetByEmail(context.Background(), "host@example.com")
 		require.NoError(t, err)
 
 		musicEvent := createTestEvent(t, "Music Festival", user.UUID.String(), []string{"Music"})
-		err = eventSrvc.CreateEvent(musicEvent)
+		err = eventSrvc.CreateEvent(context.Background(), musicEvent)
 		require.NoError(t, err)
 
 		sportsEvent := createTestEvent(t, "Sports Day", user.UUID.String(), []string{"Sports"})
-		err = eventSrvc.CreateEvent(sportsEvent)
+		err = eventSrvc.CreateEvent(context.Background(), sportsEvent)
 		require.NoError(t, err)
 
 		techEvent := createTestEvent(t, "Tech Conference", user.UUID.String(), []string{"Tech"})
-		err = eventSrvc.CreateEvent(techEvent)
+		err = eventSrvc.CreateEvent(context.Background(), techEvent)
 		require.NoError(t, err)
 
-		events, err := eventSrvc.GetEventByTag([]string{"Music", "Sports"})
+		events, err := eventSrvc.GetEventByTag(context.Background(), []string{"Music", "Sports"})
 		require.NoError(t, err)
 
 		assert.Len(t, events, 2)
@@ -222,14 +225,14 @@ func TestEventRepo_FindAllByTags_NoMatchingEvents(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		user, err := userSrvc.GetByEmail("host@example.com")
+		user, err := userSrvc.GetByEmail(context.Background(), "host@example.com")
 		require.NoError(t, err)
 
 		musicEvent := createTestEvent(t, "Music Festival", user.UUID.String(), []string{"Music"})
-		err = eventSrvc.CreateEvent(musicEvent)
+		err = eventSrvc.CreateEvent(context.Background(), musicEvent)
 		require.NoError(t, err)
 
-		events, err := eventSrvc.GetEventByTag([]string{"Gaming"})
+		events, err := eventSrvc.GetEventByTag(context.Background(), []string{"Gaming"})
 		require.NoError(t, err)
 
 		assert.Empty(t, events)
@@ -241,18 +244,18 @@ func TestEventRepo_FindAllByTags_EventWithMultipleTags(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		user, err := userSrvc.GetByEmail("host@example.com")
+		user, err := userSrvc.GetByEmail(context.Background(), "host@example.com")
 		require.NoError(t, err)
 
 		multiTagEvent := createTestEvent(t, "Tech Music Party", user.UUID.String(), []string{"Tech", "Music", "Party"})
-		err = eventSrvc.CreateEvent(multiTagEvent)
+		err = eventSrvc.CreateEvent(context.Background(), multiTagEvent)
 		require.NoError(t, err)
 
 		singleTagEvent := createTestEvent(t, "Pure Music", user.UUID.String(), []string{"Music"})
-		err = eventSrvc.CreateEvent(singleTagEvent)
+		err = eventSrvc.CreateEvent(context.Background(), singleTagEvent)
 		require.NoError(t, err)
 
-		events, err := eventSrvc.GetEventByTag([]string{"Tech"})
+		events, err := eventSrvc.GetEventByTag(context.Background(), []string{"Tech"})
 		require.NoError(t, err)
 
 		assert.Len(t, events, 1)
@@ -265,14 +268,14 @@ func TestEventRepo_FindAllByTags_EmptyTagList(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		user, err := userSrvc.GetByEmail("host@example.com")
+		user, err := userSrvc.GetByEmail(context.Background(), "host@example.com")
 		require.NoError(t, err)
 
 		musicEvent := createTestEvent(t, "Music Festival", user.UUID.String(), []string{"Music"})
-		err = eventSrvc.CreateEvent(musicEvent)
+		err = eventSrvc.CreateEvent(context.Background(), musicEvent)
 		require.NoError(t, err)
 
-		events, err := eventSrvc.GetEventByTag([]string{})
+		events, err := eventSrvc.GetEventByTag(context.Background(), []string{})
 		require.NoError(t, err)
 
 		assert.Empty(t, events)
@@ -284,14 +287,14 @@ func TestEventRepo_FindAllByTags_NonExistentTag(t *testing.T) {
 
 	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
 		registerAndPromoteHost(t, userSrvc, "host@example.com", "Secret123!")
-		user, err := userSrvc.GetByEmail("host@example.com")
+		user, err := userSrvc.GetByEmail(context.Background(), "host@example.com")
 		require.NoError(t, err)
 
 		musicEvent := createTestEvent(t, "Music Festival", user.UUID.String(), []string{"Music"})
-		err = eventSrvc.CreateEvent(musicEvent)
+		err = eventSrvc.CreateEvent(context.Background(), musicEvent)
 		require.NoError(t, err)
 
-		events, err := eventSrvc.GetEventByTag([]string{"NonExistentTag123"})
+		events, err := eventSrvc.GetEventByTag(context.Background(), []string{"NonExistentTag123"})
 		require.NoError(t, err)
 
 		assert.Empty(t, events)
@@ -304,7 +307,10 @@ func createTestEvent(t *testing.T, name, organizerID string, tags []string) *eve
 		EventID:     uuid.New().String(),
 		Name:        name,
 		Description: "Test description",
-		Date:        time.Now().Add(24 * time.Hour),
+		StartsAt:    time.Now().Add(24 * time.Hour),
+		EndsAt:      time.Now().Add(26 * time.Hour),
+		Timezone:    "UTC",
+		Status:      event.StatusPublished,
 		Latitude:    42.0,
 		Longitude:   21.37,
 		Fee:         10.0,
@@ -0,0 +1,130 @@
+package integral
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestTimeRange_KeepsTimeOfDayAndTimezone(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		w := createEventInRange(t, router, sessionID, "Evening Concert", "2025-12-31T18:30:00+01:00", "2025-12-31T22:00:00+01:00", "Europe/Warsaw")
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.True(t, events[0].StartsAt.Equal(time.Date(2025, 12, 31, 17, 30, 0, 0, time.UTC)))
+		require.True(t, events[0].EndsAt.Equal(time.Date(2025, 12, 31, 21, 0, 0, 0, time.UTC)))
+		require.Equal(t, "Europe/Warsaw", events[0].Timezone)
+	})
+}
+
+func TestTimeRange_DefaultsToUTC(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		w := createEventInRange(t, router, sessionID, "Morning Run", "2025-06-01T07:00:00Z", "2025-06-01T08:00:00Z", "")
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, "UTC", events[0].Timezone)
+	})
+}
+
+func TestTimeRange_Invalid(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		w := createEventInRange(t, router, sessionID, "Backwards", "2025-06-01T10:00:00Z", "2025-06-01T09:00:00Z", "UTC")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = createEventInRange(t, router, sessionID, "Instant", "2025-06-01T10:00:00Z", "2025-06-01T10:00:00Z", "UTC")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = createEventInRange(t, router, sessionID, "Nowhere", "2025-06-01T10:00:00Z", "2025-06-01T12:00:00Z", "Europe/Atlantis")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+}
+
+func TestTimeRange_UpdateEndBeforeStart(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		w := createEventInRange(t, router, sessionID, "Workshop", "2025-06-01T10:00:00Z", "2025-06-01T12:00:00Z", "UTC")
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+
+		w = updateEvent(t, router.Handler, http.MethodPatch, eventID, sessionID, []byte(`{"starts_at":"2025-06-01T13:00:00Z"}`))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = updateEvent(t, router.Handler, http.MethodPatch, eventID, sessionID, []byte(`{"starts_at":"2025-06-01T13:00:00Z","ends_at":"2025-06-01T15:00:00Z","timezone":"America/New_York"}`))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		updated, err := eventSrvc.GetEventByID(context.Background(), eventID)
+		require.NoError(t, err)
+		require.True(t, updated.StartsAt.Equal(time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC)))
+		require.Equal(t, "America/New_York", updated.Timezone)
+	})
+}
+
+func TestTimeRange_DateFilterOverlap(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		w := createEventInRange(t, router, sessionID, "Festival", "2025-02-27T12:00:00Z", "2025-03-03T22:00:00Z", "UTC")
+		require.Equal(t, http.StatusCreated, w.Code)
+		w = createEventInRange(t, router, sessionID, "Workshop", "2025-03-02T10:00:00Z", "2025-03-02T12:00:00Z", "UTC")
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		require.Equal(t, []string{"Festival", "Workshop"}, eventNames(listEvents(t, router, "/api/events?date_from=2025-03-01&date_to=2025-03-02")))
+		require.Equal(t, []string{"Festival"}, eventNames(listEvents(t, router, "/api/events?date_from=2025-03-03")))
+		require.Equal(t, []string{"Festival"}, eventNames(listEvents(t, router, "/api/events?date_to=2025-02-28")))
+		require.Empty(t, listEvents(t, router, "/api/events?date_from=2025-03-04"))
+	})
+}
+
+func createEventInRange(t *testing.T, router *webapi.Router, sessionID, name, startsAt, endsAt, timezone string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(webapi.CreateEventRequest{
+		Name:     name,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+		Timezone: timezone,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.Handler.ServeHTTP(w, newEventRequest(t, body, sessionID))
+	return w
+}
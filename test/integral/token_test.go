@@ -0,0 +1,182 @@
+package integral
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainsecurity "github.com/kapiw04/convenly/internal/domain/security"
+	"github.com/kapiw04/convenly/internal/infra/security"
+	"github.com/kapiw04/convenly/internal/infra/webapi"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func postJSON(h http.Handler, path string, body any) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func issueTokens(t *testing.T, h http.Handler, email, password string) webapi.TokenResponse {
+	t.Helper()
+	w := postJSON(h, "/api/token", webapi.LoginRequest{Email: email, Password: password})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp webapi.TokenResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp
+}
+
+func getMeWithBearer(h http.Handler, accessToken string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestIssueToken_BearerAuth(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+
+		tokens := issueTokens(t, router.Handler, "alice@example.com", "Secret123!")
+		require.Equal(t, "Bearer", tokens.TokenType)
+		require.NotEmpty(t, tokens.RefreshToken)
+		require.InDelta(t, (15 * time.Minute).Seconds(), tokens.ExpiresIn, 5)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.Result().Cookies())
+
+		var me map[string]any
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&me))
+		require.Equal(t, "alice@example.com", me["email"])
+
+		// no session is created for token clients
+		var sessions int
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM sessions").Scan(&sessions))
+		require.Equal(t, 0, sessions)
+	})
+}
+
+func TestIssueToken_InvalidCredentials(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+
+		w := postJSON(router.Handler, "/api/token", webapi.LoginRequest{Email: "alice@example.com", Password: "Wrong123!"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = postJSON(router.Handler, "/api/token", webapi.LoginRequest{Email: "nobody@example.com", Password: "Secret123!"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestBearerAuth_ExpiredOrForged(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+		u, err := userSrvc.GetByEmail(context.Background(), "alice@example.com")
+		require.NoError(t, err)
+
+		claims := domainsecurity.Claims{
+			Subject:   u.UUID.String(),
+			IssuedAt:  time.Now().Add(-time.Hour),
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}
+		expired, err := security.NewJWTSigner(testTokenSecret).Sign(claims)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, getMeWithBearer(router.Handler, expired))
+
+		claims.ExpiresAt = time.Now().Add(time.Hour)
+		forged, err := security.NewJWTSigner([]byte("guessed-secret")).Sign(claims)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, getMeWithBearer(router.Handler, forged))
+	})
+}
+
+func TestRefreshToken_Rotates(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+		first := issueTokens(t, router.Handler, "alice@example.com", "Secret123!")
+
+		w := postJSON(router.Handler, "/api/token/refresh", webapi.RefreshTokenRequest{RefreshToken: first.RefreshToken})
+		require.Equal(t, http.StatusOK, w.Code)
+		var second webapi.TokenResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&second))
+		require.NotEqual(t, first.RefreshToken, second.RefreshToken)
+		require.Equal(t, http.StatusOK, getMeWithBearer(router.Handler, second.AccessToken))
+
+		// a refresh token works once
+		w = postJSON(router.Handler, "/api/token/refresh", webapi.RefreshTokenRequest{RefreshToken: first.RefreshToken})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestRefreshToken_Expired(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+		tokens := issueTokens(t, router.Handler, "alice@example.com", "Secret123!")
+
+		_, err := tx.Exec("UPDATE refresh_tokens SET expires_at = now() - interval '1 minute'")
+		require.NoError(t, err)
+
+		w := postJSON(router.Handler, "/api/token/refresh", webapi.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		require.NoError(t, userSrvc.Register(context.Background(), "Alice", "alice@example.com", "Secret123!"))
+		tokens := issueTokens(t, router.Handler, "alice@example.com", "Secret123!")
+
+		w := postJSON(router.Handler, "/api/token/revoke", webapi.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = postJSON(router.Handler, "/api/token/refresh", webapi.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestLogoutEverywhere_RevokesTokens(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		sessionID := RegisterAndLoginUser(t, userSrvc, "Alice", "alice@example.com", "Secret123!")
+		tokens := issueTokens(t, router.Handler, "alice@example.com", "Secret123!")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/logout-all", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = postJSON(router.Handler, "/api/token/refresh", webapi.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, http.StatusUnauthorized, getMeWithBearer(router.Handler, tokens.AccessToken))
+	})
+}
@@ -1,17 +1,74 @@
 package integral
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
+	"github.com/kapiw04/convenly/internal/infra/db"
 	"github.com/stretchr/testify/require"
 )
 
-func WithTx(t *testing.T, db *sql.DB, fn func(t *testing.T, tx *sql.Tx)) {
+// testDB is the database as the services of one test see it: it sends
+// their statements to the transaction of the test's WithTx. Each test sets
+// up its own, so tests can run in parallel.
+type testDB struct {
+	conn *sql.DB
+	tx   *sql.Tx
+}
+
+var _ db.DBTX = (*testDB)(nil)
+
+func (d *testDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.tx.ExecContext(ctx, query, args...)
+}
+
+func (d *testDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.tx.QueryContext(ctx, query, args...)
+}
+
+func (d *testDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.tx.QueryRowContext(ctx, query, args...)
+}
+
+// WithTx runs fn in a transaction that is rolled back afterwards. Services
+// set up on d, and fn through tx, see each other's writes, but nothing
+// reaches the database.
+func WithTx(t *testing.T, d *testDB, fn func(t *testing.T, tx *sql.Tx)) {
+	t.Helper()
+
+	tx, err := d.conn.Begin()
+	require.NoError(t, err)
+	d.tx = tx
+	defer func() {
+		d.tx = nil
+		require.NoError(t, tx.Rollback())
+	}()
+
+	fn(t, tx)
+}
+
+// WithSavepoint runs fn, which is expected to fail in the database, and
+// undoes it. A failed statement, such as a duplicate insert, aborts the
+// transaction, so a test goes on using it only past a savepoint.
+func WithSavepoint(t *testing.T, tx *sql.Tx, fn func()) {
+	t.Helper()
+
+	_, err := tx.Exec("SAVEPOINT test_step")
+	require.NoError(t, err)
+	fn()
+	_, err = tx.Exec("ROLLBACK TO SAVEPOINT test_step")
+	require.NoError(t, err)
+}
+
+// WithCleanDatabase runs fn against the database itself and deletes what it
+// left behind. It is for tests that need several concurrent transactions,
+// which cannot share the one of WithTx.
+func WithCleanDatabase(t *testing.T, db *sql.DB, fn func(t *testing.T)) {
 	t.Helper()
 	cleanDatabase(t, db)
-	fn(t, nil)
-	cleanDatabase(t, db)
+	defer cleanDatabase(t, db)
+	fn(t)
 }
 
 func cleanDatabase(t *testing.T, db *sql.DB) {
@@ -19,9 +76,16 @@ func cleanDatabase(t *testing.T, db *sql.DB) {
 
 	queries := []string{
 		"DELETE FROM event_tag",
+		"DELETE FROM occurrence_attendance",
+		"DELETE FROM occurrence_exceptions",
 		"DELETE FROM attendance",
+		"DELETE FROM waitlist",
 		"DELETE FROM events",
 		"DELETE FROM sessions",
+		"DELETE FROM refresh_tokens",
+		"DELETE FROM login_failures",
+		"DELETE FROM password_resets",
+		"DELETE FROM email_verifications",
 		"DELETE FROM users",
 	}
 
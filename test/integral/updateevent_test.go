@@ -0,0 +1,137 @@
+package integral
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestUpdateEvent_PartialUpdate(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		createEventForDelete(t, router, hostSessionID, "Test Event")
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+
+		body := []byte(`{"name":"Renamed Event","fee":25.5,"tags":["Tech","Meetup"]}`)
+		w := updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, body)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		updated, err := eventSrvc.GetEventByID(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, "Renamed Event", updated.Name)
+		require.Equal(t, "Test event description", updated.Description)
+		require.Equal(t, float32(25.5), updated.Fee)
+		require.ElementsMatch(t, []string{"Tech", "Meetup"}, updated.Tags)
+	})
+}
+
+func TestUpdateEvent_KeepsAttendance(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		createEventForDelete(t, router, hostSessionID, "Test Event")
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+
+		attendeeSessionID := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee@example.com", "Secret123!")
+		registerReq := httptest.NewRequest(http.MethodPost, "/api/events/"+eventID+"/register", nil)
+		registerReq.AddCookie(&http.Cookie{Name: "session-id", Value: attendeeSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, registerReq)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		body := []byte(`{"starts_at":"2026-01-15T18:00:00Z","ends_at":"2026-01-15T21:00:00Z"}`)
+		w = updateEvent(t, router.Handler, http.MethodPut, eventID, hostSessionID, body)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		attendees, err := eventSrvc.GetAttendees(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Len(t, attendees, 1)
+	})
+}
+
+func TestUpdateEvent_NotOrganizer(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		host1SessionID := registerHostAndLogin(t, userSrvc, "host1@example.com", "Secret123!")
+		createEventForDelete(t, router, host1SessionID, "Test Event")
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+
+		host2SessionID := registerHostAndLoginWithName(t, userSrvc, "Host 2", "host2@example.com", "Secret123!")
+
+		w := updateEvent(t, router.Handler, http.MethodPatch, eventID, host2SessionID, []byte(`{"name":"Hijacked"}`))
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		unchanged, err := eventSrvc.GetEventByID(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, "Test Event", unchanged.Name)
+	})
+}
+
+func TestUpdateEvent_NonExistentTagRollsBack(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		createEventForDelete(t, router, hostSessionID, "Test Event")
+
+		events, err := eventSrvc.GetAllEvents(context.Background())
+		require.NoError(t, err)
+		eventID := events[0].EventID
+
+		body := []byte(`{"name":"Renamed Event","tags":["NoSuchTag"]}`)
+		w := updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, body)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		unchanged, err := eventSrvc.GetEventByID(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, "Test Event", unchanged.Name)
+		require.Equal(t, []string{"Music"}, unchanged.Tags)
+	})
+}
+
+func TestUpdateEvent_NonExistent(t *testing.T) {
+	sqlDb, userSrvc, _, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+
+		w := updateEvent(t, router.Handler, http.MethodPatch, "00000000-0000-0000-0000-000000000000", hostSessionID, []byte(`{"name":"Whatever"}`))
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func updateEvent(t *testing.T, h http.Handler, method, eventID, sessionID string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	require.True(t, json.Valid(body))
+	req := httptest.NewRequest(method, "/api/events/"+eventID, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
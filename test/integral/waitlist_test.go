@@ -0,0 +1,237 @@
+package integral
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kapiw04/convenly/internal/domain/event"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+func TestWaitlist_JoinWhenFull(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 1)
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+		third := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee3@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/events/"+eventID+"/register", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: second})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		var resp struct {
+			Status   string `json:"status"`
+			Position int    `json:"position"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Equal(t, "waitlisted", resp.Status)
+		require.Equal(t, 1, resp.Position)
+
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, third))
+		require.Equal(t, 2, waitlistPosition(t, router.Handler, eventID, third))
+	})
+}
+
+func TestWaitlist_RegisterTwiceWhileWaitlisted(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 1)
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusConflict, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, second))
+		require.Equal(t, http.StatusConflict, registerForEvent(router.Handler, eventID, second))
+	})
+}
+
+func TestWaitlist_PromotedOnUnregister(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 1)
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+		third := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee3@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, second))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, third))
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID+"/unregister", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: first})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		secondUser, err := userSrvc.GetByEmail(context.Background(), "attendee2@example.com")
+		require.NoError(t, err)
+		require.True(t, eventSrvc.IsUserAttending(context.Background(), secondUser.UUID.String(), eventID))
+
+		count, err := eventSrvc.GetAttendeesCount(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		require.Equal(t, 1, waitlistPosition(t, router.Handler, eventID, third))
+	})
+}
+
+func TestWaitlist_PromotedOnCapacityRaise(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 1)
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+		third := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee3@example.com", "Secret123!")
+		fourth := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee4@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, second))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, third))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, fourth))
+
+		w := updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"capacity": 3}`))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		for _, email := range []string{"attendee2@example.com", "attendee3@example.com"} {
+			u, err := userSrvc.GetByEmail(context.Background(), email)
+			require.NoError(t, err)
+			require.True(t, eventSrvc.IsUserAttending(context.Background(), u.UUID.String(), eventID))
+		}
+		count, err := eventSrvc.GetAttendeesCount(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+		require.Equal(t, 1, waitlistPosition(t, router.Handler, eventID, fourth))
+	})
+}
+
+func TestWaitlist_PromotedOnCapacityCleared(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 1)
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, second))
+
+		w := updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"capacity": 5, "clear_capacity": true}`))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = updateEvent(t, router.Handler, http.MethodPatch, eventID, hostSessionID, []byte(`{"clear_capacity": true}`))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		e, err := eventSrvc.GetEventByID(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Nil(t, e.Capacity)
+		count, err := eventSrvc.GetAttendeesCount(context.Background(), eventID)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+}
+
+func TestWaitlist_HostSeesQueue(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 1)
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+		third := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee3@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, second))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, third))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/waitlist", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: hostSessionID})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var entries []event.WaitlistEntry
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&entries))
+		require.Len(t, entries, 2)
+
+		secondUser, err := userSrvc.GetByEmail(context.Background(), "attendee2@example.com")
+		require.NoError(t, err)
+		require.Equal(t, secondUser.UUID.String(), entries[0].UserID)
+		require.Equal(t, 1, entries[0].Position)
+		require.Equal(t, 2, entries[1].Position)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/waitlist", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: second})
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestWaitlist_Leave(t *testing.T) {
+	sqlDb, userSrvc, eventSrvc, router := setupAllServices(t)
+
+	WithTx(t, sqlDb, func(t *testing.T, tx *sql.Tx) {
+		hostSessionID := registerHostAndLogin(t, userSrvc, "host@example.com", "Secret123!")
+		eventID := createEventWithCapacity(t, router, eventSrvc, hostSessionID, 1)
+
+		first := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee1@example.com", "Secret123!")
+		second := RegisterAndLoginUser(t, userSrvc, "Attendee", "attendee2@example.com", "Secret123!")
+
+		require.Equal(t, http.StatusOK, registerForEvent(router.Handler, eventID, first))
+		require.Equal(t, http.StatusAccepted, registerForEvent(router.Handler, eventID, second))
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID+"/waitlist", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: second})
+		w := httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/waitlist/me", nil)
+		req.AddCookie(&http.Cookie{Name: "session-id", Value: second})
+		w = httptest.NewRecorder()
+		router.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func waitlistPosition(t *testing.T, h http.Handler, eventID, sessionID string) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID+"/waitlist/me", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: sessionID})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Position int `json:"position"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp.Position
+}